@@ -0,0 +1,190 @@
+package unravel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ValueKind describes the kind of value held by a [KindSource].
+type ValueKind int
+
+const (
+	// KindUnknown is returned if the kind of the value can not be determined.
+	KindUnknown ValueKind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindObject
+	KindArray
+)
+
+var tyAny = reflect.TypeFor[any]()
+
+// defaultMaxMaterializeDepth limits the nesting of values decoded into an empty interface
+// if the decoder has no maximum depth, like encoding/json does.
+const defaultMaxMaterializeDepth = 10_000
+
+// setAny decodes into an empty interface, e.g. `any`. See [materialize].
+func (d *Decoder) setAny(ctx context.Context, source Source, target reflect.Value) error {
+	value, err := d.materialize(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		target.SetZero()
+		return nil
+	}
+
+	target.Set(reflect.ValueOf(value))
+	return nil
+}
+
+// materialize works like the materialize function, but enforces the limits and the
+// maximum depth of the decoder, see WithLimits and WithMaxDepth.
+func (d *Decoder) materialize(ctx context.Context, source Source) (any, error) {
+	maxDepth := d.maxDepth
+	if maxDepth == 0 {
+		maxDepth = defaultMaxMaterializeDepth
+	}
+
+	m := materializer{ctx: ctx, limits: d.limits, maxDepth: maxDepth}
+	return m.materialize(source, 0)
+}
+
+// materialize returns the natural Go representation of the value of a source,
+// using [KindSource] to learn about the kind of the value:
+//   - null is returned as nil
+//   - booleans and strings are returned as bool and string
+//   - numbers are returned as int64 if possible, as uint64 if they are too
+//     large for an int64, and as float64 otherwise
+//   - objects are returned as map[string]any
+//   - arrays are returned as []any
+//
+// Sources that do not implement [KindSource], or return [KindUnknown], are decoded as string.
+func materialize(source Source) (any, error) {
+	return materializer{ctx: context.Background()}.materialize(source, 0)
+}
+
+// materializer builds the values returned by materialize. Objects and arrays may be
+// nested within maxDepth other objects and arrays, zero does not restrict the depth.
+type materializer struct {
+	ctx      context.Context
+	limits   Limits
+	maxDepth int
+}
+
+func (m materializer) materialize(source Source, depth int) (any, error) {
+	kind := KindUnknown
+	if kindSource, ok := source.(KindSource); ok {
+		kind = kindSource.Kind()
+	}
+
+	if (kind == KindObject || kind == KindArray) && m.maxDepth > 0 && depth > m.maxDepth {
+		return nil, fmt.Errorf("%w: values nested more than %d times", ErrMaxDepth, m.maxDepth)
+	}
+
+	switch kind {
+	case KindNull:
+		return nil, nil
+
+	case KindBool:
+		boolValue, err := source.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("get bool value: %w", err)
+		}
+
+		return boolValue, nil
+
+	case KindNumber:
+		if intValue, err := source.Int(); err == nil {
+			return intValue, nil
+		}
+
+		if uintValue, err := source.Uint(); err == nil {
+			return uintValue, nil
+		}
+
+		floatValue, err := source.Float()
+		if err != nil {
+			return nil, fmt.Errorf("get float value: %w", err)
+		}
+
+		return floatValue, nil
+
+	case KindObject:
+		entries, err := keyValuesContext(m.ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("as key values: %w", err)
+		}
+
+		object := map[string]any{}
+
+		for keySource, valueSource := range entries {
+			if m.limits.MaxMapEntries > 0 && len(object) >= m.limits.MaxMapEntries {
+				return nil, fmt.Errorf("%w: more than %d map entries", ErrLimitExceeded, m.limits.MaxMapEntries)
+			}
+
+			key, err := m.stringOf(keySource)
+			if err != nil {
+				return nil, fmt.Errorf("get key: %w", err)
+			}
+
+			value, err := m.materialize(valueSource, depth+1)
+			if err != nil {
+				return nil, withPath(err, keySegment(key), tyAny)
+			}
+
+			object[key] = value
+		}
+
+		return object, nil
+
+	case KindArray:
+		elements, err := iterContext(m.ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("as iter: %w", err)
+		}
+
+		array := []any{}
+
+		for elementSource := range elements {
+			if m.limits.MaxSliceLen > 0 && len(array) >= m.limits.MaxSliceLen {
+				return nil, fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, m.limits.MaxSliceLen)
+			}
+
+			value, err := m.materialize(elementSource, depth+1)
+			if err != nil {
+				return nil, withPath(err, indexSegment(len(array)), tyAny)
+			}
+
+			array = append(array, value)
+		}
+
+		return array, nil
+
+	default:
+		stringValue, err := m.stringOf(source)
+		if err != nil {
+			return nil, fmt.Errorf("get string value: %w", err)
+		}
+
+		return stringValue, nil
+	}
+}
+
+// stringOf returns the string value of the source, if it does not exceed MaxStringLen.
+func (m materializer) stringOf(source Source) (string, error) {
+	value, err := source.String()
+	if err != nil {
+		return "", err
+	}
+
+	if m.limits.MaxStringLen > 0 && len(value) > m.limits.MaxStringLen {
+		return "", fmt.Errorf("%w: string of %d bytes is longer than %d bytes", ErrLimitExceeded, len(value), m.limits.MaxStringLen)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,59 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDecodeAny(t *testing.T) {
+	type Event struct {
+		Kind  string `json:"kind"`
+		Extra any    `json:"extra"`
+		Items []any  `json:"items"`
+		Empty any    `json:"empty"`
+	}
+
+	input := `{
+		"kind": "create",
+		"extra": {"user": {"id": 7, "roles": ["admin"]}, "score": 1.5, "active": true},
+		"items": [1, "two", null, 18446744073709551615],
+		"empty": null
+	}`
+
+	event, err := UnmarshalNew[Event](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	require.Equal(t, event, Event{
+		Kind: "create",
+		Extra: map[string]any{
+			"user":   map[string]any{"id": int64(7), "roles": []any{"admin"}},
+			"score":  1.5,
+			"active": true,
+		},
+		Items: []any{int64(1), "two", nil, uint64(18446744073709551615)},
+	})
+}
+
+func TestDecodeAnyFromReflectSource(t *testing.T) {
+	type Inner struct {
+		Name string `json:"name"`
+	}
+
+	value, err := UnmarshalNew[any](SourceOf(map[string]any{
+		"inner": Inner{Name: "Alex"},
+		"ids":   [2]uint8{1, 2},
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, value, map[string]any{
+		"inner": map[string]any{"name": "Alex"},
+		"ids":   []any{int64(1), int64(2)},
+	})
+}
+
+func TestDecodeAnyFallsBackToString(t *testing.T) {
+	value, err := UnmarshalNew[any](StringSource("42"))
+	require.NoError(t, err)
+	require.Equal(t, value, "42")
+}
@@ -0,0 +1,123 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestDecoderWithArena(t *testing.T) {
+	type Commit struct {
+		Sha1   string  `json:"sha1"`
+		Parent *Commit `json:"parent"`
+	}
+
+	var history any
+	for _, sha1 := range []string{"c", "b", "a"} {
+		history = map[string]any{"sha1": sha1, "parent": history}
+	}
+
+	dec := NewDecoder().WithArena(16)
+
+	expected, err := UnmarshalNew[Commit](SourceOf(history))
+	require.NoError(t, err)
+
+	commit, err := UnmarshalNewWith[Commit](dec, SourceOf(history))
+	require.NoError(t, err)
+	require.Equal(t, commit, expected)
+
+	// the parents are allocated next to each other
+	parent := unsafe.Pointer(commit.Parent)
+	require.Equal(t, uintptr(unsafe.Pointer(commit.Parent.Parent))-uintptr(parent), unsafe.Sizeof(Commit{}))
+
+	// values of a failed decode are not used
+	_, err = UnmarshalNewWith[Commit](dec, SourceOf(map[string]any{"parent": map[string]any{"sha1": 1.5}}))
+	require.Error(t, err)
+
+	commit, err = UnmarshalNewWith[Commit](dec, SourceOf(history))
+	require.NoError(t, err)
+	require.Equal(t, commit, expected)
+}
+
+func TestDecoderWithArenaReleasesChunks(t *testing.T) {
+	type Node struct {
+		Value int   `json:"value"`
+		Next  *Node `json:"next"`
+	}
+
+	source := SourceOf(map[string]any{"value": 1, "next": map[string]any{"value": 2}})
+
+	dec := NewDecoder().WithArena(16)
+
+	first, err := UnmarshalNewWith[Node](dec, source)
+	require.NoError(t, err)
+
+	// the decoder does not keep the chunk of a finished call
+	require.Len(t, dec.arenas.arenas, 1)
+	require.False(t, dec.arenas.arenas[0].chunk.IsValid())
+
+	// each call allocates from a chunk of its own
+	second, err := UnmarshalNewWith[Node](dec, source)
+	require.NoError(t, err)
+	require.Equal(t, second, first)
+
+	distance := uintptr(unsafe.Pointer(second.Next)) - uintptr(unsafe.Pointer(first.Next))
+	require.NotEqual(t, distance, unsafe.Sizeof(Node{}))
+
+	dec.ResetCache()
+	require.Empty(t, dec.arenas.arenas)
+}
+
+func TestDecoderWithArenaDerived(t *testing.T) {
+	dec := NewDecoder().WithArena(16)
+
+	var value *int
+	err := dec.Unmarshal(SourceOf(1), &value)
+	require.NoError(t, err)
+	require.Len(t, dec.arenas.arenas, 1)
+
+	// a derived decoder does not allocate from the arenas of its parent,
+	// but from arenas of its own, which it releases after each call
+	derived := dec.WithTag("yaml")
+
+	var other *int
+	err = derived.Unmarshal(SourceOf(2), &other)
+	require.NoError(t, err)
+	require.Equal(t, *other, 2)
+
+	require.Len(t, derived.arenas.arenas, 1)
+	require.NotSame(t, derived.arenas.arenas[0], dec.arenas.arenas[0])
+	require.False(t, derived.arenas.arenas[0].chunk.IsValid())
+
+	// setters without pointers are still kept
+	_, err = UnmarshalNewWith[[]int](dec, SourceOf([]int{1}))
+	require.NoError(t, err)
+
+	_, ok := dec.WithTag("yaml").setterCache.Load(reflect.TypeFor[[]int]())
+	require.True(t, ok)
+}
+
+func TestDecoderWithArenaAllocations(t *testing.T) {
+	type Node struct {
+		Value int   `json:"value"`
+		Next  *Node `json:"next"`
+	}
+
+	var list any
+	for idx := range 64 {
+		list = map[string]any{"value": idx, "next": list}
+	}
+
+	source := SourceOf(list)
+
+	allocs := func(dec *Decoder) float64 {
+		var node Node
+		return testing.AllocsPerRun(10, func() {
+			_ = dec.Unmarshal(source, &node)
+		})
+	}
+
+	// one allocation for the chunk instead of one per node
+	require.LessOrEqual(t, allocs(NewDecoder().WithArena(64)), allocs(NewDecoder())-60)
+}
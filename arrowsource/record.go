@@ -0,0 +1,358 @@
+// Package arrowsource adapts Apache Arrow records to the [unravel.Source] interface.
+//
+// A [RecordSource] exposes every row of an [arrow.Record] as a child source, with the
+// columns of a row addressable by name. Scalar values implement [unravel.BinarySource],
+// so integer columns are decoded into Go integer types of matching width without a
+// round-trip through int64 or string formatting.
+//
+// The package is a module of its own, so that depending on unravel does not pull in
+// the Apache Arrow libraries.
+package arrowsource
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/go-gum/unravel"
+)
+
+// RecordSource adapts an [arrow.Record] to the [unravel.Source] interface.
+// The record is interpreted as a list of rows, [RecordSource.Iter] yields one
+// [RowSource] per row.
+//
+// Example:
+//
+//	type Measurement struct {
+//	    Sensor string  `json:"sensor"`
+//	    Value  float32 `json:"value"`
+//	}
+//
+//	rows, err := unravel.UnmarshalNew[[]Measurement](arrowsource.RecordSource{Record: rec})
+type RecordSource struct {
+	unravel.EmptySource
+	Record arrow.Record
+}
+
+var _ unravel.Source = RecordSource{}
+var _ unravel.LenSource = RecordSource{}
+var _ unravel.IndexSource = RecordSource{}
+
+func (r RecordSource) Iter() (iter.Seq[unravel.Source], error) {
+	it := func(yield func(unravel.Source) bool) {
+		for row := range int(r.Record.NumRows()) {
+			if !yield(RowSource{Record: r.Record, Row: row}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (r RecordSource) Len() (int, bool) {
+	return int(r.Record.NumRows()), true
+}
+
+func (r RecordSource) Index(idx int) (unravel.Source, error) {
+	if idx < 0 || idx >= int(r.Record.NumRows()) {
+		return nil, unravel.ErrNoValue
+	}
+
+	return RowSource{Record: r.Record, Row: idx}, nil
+}
+
+// RowSource is a single row of an [arrow.Record]. Columns of the row are
+// accessible by name using [RowSource.Get] and [RowSource.KeyValues].
+type RowSource struct {
+	unravel.EmptySource
+	Record arrow.Record
+	Row    int
+}
+
+var _ unravel.Source = RowSource{}
+
+func (r RowSource) Get(key string) (unravel.Source, error) {
+	indices := r.Record.Schema().FieldIndices(key)
+	if len(indices) == 0 {
+		return nil, unravel.ErrNoValue
+	}
+
+	column := r.Record.Column(indices[0])
+	if column.IsNull(r.Row) {
+		return nil, unravel.ErrNoValue
+	}
+
+	return valueSource{Array: column, Index: r.Row}, nil
+}
+
+func (r RowSource) KeyValues() (iter.Seq2[unravel.Source, unravel.Source], error) {
+	it := func(yield func(unravel.Source, unravel.Source) bool) {
+		for idx := range int(r.Record.NumCols()) {
+			column := r.Record.Column(idx)
+			if column.IsNull(r.Row) {
+				continue
+			}
+
+			key := unravel.StringSource(r.Record.ColumnName(idx))
+			if !yield(key, valueSource{Array: column, Index: r.Row}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// valueSource is a single value within an [arrow.Array].
+type valueSource struct {
+	Array arrow.Array
+	Index int
+}
+
+var _ unravel.Source = valueSource{}
+var _ unravel.BinarySource = valueSource{}
+var _ unravel.BytesSource = valueSource{}
+
+func (v valueSource) Bool() (bool, error) {
+	if arr, ok := v.Array.(*array.Boolean); ok {
+		return arr.Value(v.Index), nil
+	}
+
+	return false, unravel.ErrNotSupported
+}
+
+func (v valueSource) Int() (int64, error) {
+	return v.Int64()
+}
+
+func (v valueSource) Uint() (uint64, error) {
+	return v.Uint64()
+}
+
+func (v valueSource) Float() (float64, error) {
+	return v.Float64()
+}
+
+func (v valueSource) String() (string, error) {
+	switch arr := v.Array.(type) {
+	case *array.String:
+		return arr.Value(v.Index), nil
+	case *array.LargeString:
+		return arr.Value(v.Index), nil
+	case *array.Binary:
+		return string(arr.Value(v.Index)), nil
+	case *array.LargeBinary:
+		return string(arr.Value(v.Index)), nil
+	case *array.Timestamp:
+		unit := arr.DataType().(*arrow.TimestampType).Unit
+		return arr.Value(v.Index).ToTime(unit).Format(time.RFC3339Nano), nil
+	default:
+		return "", unravel.ErrNotSupported
+	}
+}
+
+func (v valueSource) Bytes() ([]byte, error) {
+	switch arr := v.Array.(type) {
+	case *array.Binary:
+		return arr.Value(v.Index), nil
+	case *array.LargeBinary:
+		return arr.Value(v.Index), nil
+	case *array.FixedSizeBinary:
+		return arr.Value(v.Index), nil
+	default:
+		return nil, unravel.ErrNotSupported
+	}
+}
+
+func (v valueSource) Get(key string) (unravel.Source, error) {
+	arr, ok := v.Array.(*array.Struct)
+	if !ok {
+		return nil, unravel.ErrNotSupported
+	}
+
+	fieldIdx, ok := arr.DataType().(*arrow.StructType).FieldIdx(key)
+	if !ok {
+		return nil, unravel.ErrNoValue
+	}
+
+	field := arr.Field(fieldIdx)
+	if field.IsNull(v.Index) {
+		return nil, unravel.ErrNoValue
+	}
+
+	return valueSource{Array: field, Index: v.Index}, nil
+}
+
+func (v valueSource) KeyValues() (iter.Seq2[unravel.Source, unravel.Source], error) {
+	arr, ok := v.Array.(*array.Struct)
+	if !ok {
+		return nil, unravel.ErrNotSupported
+	}
+
+	ty := arr.DataType().(*arrow.StructType)
+
+	it := func(yield func(unravel.Source, unravel.Source) bool) {
+		for idx := range arr.NumField() {
+			field := arr.Field(idx)
+			if field.IsNull(v.Index) {
+				continue
+			}
+
+			key := unravel.StringSource(ty.Field(idx).Name)
+			if !yield(key, valueSource{Array: field, Index: v.Index}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (v valueSource) Iter() (iter.Seq[unravel.Source], error) {
+	arr, ok := v.Array.(array.ListLike)
+	if !ok {
+		return nil, unravel.ErrNotSupported
+	}
+
+	values := arr.ListValues()
+	start, end := arr.ValueOffsets(v.Index)
+
+	it := func(yield func(unravel.Source) bool) {
+		for idx := int(start); idx < int(end); idx++ {
+			if !yield(valueSource{Array: values, Index: idx}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (v valueSource) Int8() (int8, error) {
+	return signedValue[int8](v, math.MinInt8, math.MaxInt8)
+}
+
+func (v valueSource) Int16() (int16, error) {
+	return signedValue[int16](v, math.MinInt16, math.MaxInt16)
+}
+
+func (v valueSource) Int32() (int32, error) {
+	return signedValue[int32](v, math.MinInt32, math.MaxInt32)
+}
+
+func (v valueSource) Int64() (int64, error) {
+	return signedValue[int64](v, math.MinInt64, math.MaxInt64)
+}
+
+func (v valueSource) Uint8() (uint8, error) {
+	return unsignedValue[uint8](v, math.MaxUint8)
+}
+
+func (v valueSource) Uint16() (uint16, error) {
+	return unsignedValue[uint16](v, math.MaxUint16)
+}
+
+func (v valueSource) Uint32() (uint32, error) {
+	return unsignedValue[uint32](v, math.MaxUint32)
+}
+
+func (v valueSource) Uint64() (uint64, error) {
+	return unsignedValue[uint64](v, math.MaxUint64)
+}
+
+func (v valueSource) Float32() (float32, error) {
+	if arr, ok := v.Array.(*array.Float32); ok {
+		return arr.Value(v.Index), nil
+	}
+
+	floatValue, err := v.Float64()
+	return float32(floatValue), err
+}
+
+func (v valueSource) Float64() (float64, error) {
+	switch arr := v.Array.(type) {
+	case *array.Float32:
+		return float64(arr.Value(v.Index)), nil
+	case *array.Float64:
+		return arr.Value(v.Index), nil
+	}
+
+	if intValue, signed, ok := v.integer(); ok {
+		if signed {
+			return float64(int64(intValue)), nil
+		}
+
+		return float64(intValue), nil
+	}
+
+	return 0, unravel.ErrNotSupported
+}
+
+// integer returns the raw bits of an integer column value. signed reports
+// if the value must be interpreted as an int64 instead of an uint64.
+func (v valueSource) integer() (value uint64, signed bool, ok bool) {
+	switch arr := v.Array.(type) {
+	case *array.Int8:
+		return uint64(arr.Value(v.Index)), true, true
+	case *array.Int16:
+		return uint64(arr.Value(v.Index)), true, true
+	case *array.Int32:
+		return uint64(arr.Value(v.Index)), true, true
+	case *array.Int64:
+		return uint64(arr.Value(v.Index)), true, true
+	case *array.Uint8:
+		return uint64(arr.Value(v.Index)), false, true
+	case *array.Uint16:
+		return uint64(arr.Value(v.Index)), false, true
+	case *array.Uint32:
+		return uint64(arr.Value(v.Index)), false, true
+	case *array.Uint64:
+		return arr.Value(v.Index), false, true
+	default:
+		return 0, false, false
+	}
+}
+
+func signedValue[T int8 | int16 | int32 | int64](v valueSource, minValue, maxValue int64) (T, error) {
+	bits, signed, ok := v.integer()
+	if !ok {
+		return 0, unravel.ErrNotSupported
+	}
+
+	if !signed {
+		if bits > uint64(maxValue) {
+			return 0, fmt.Errorf("invalid %T value %d: %w", T(0), bits, strconv.ErrRange)
+		}
+
+		return T(bits), nil
+	}
+
+	intValue := int64(bits)
+	if intValue < minValue || intValue > maxValue {
+		return 0, fmt.Errorf("invalid %T value %d: %w", T(0), intValue, strconv.ErrRange)
+	}
+
+	return T(intValue), nil
+}
+
+func unsignedValue[T uint8 | uint16 | uint32 | uint64](v valueSource, maxValue uint64) (T, error) {
+	bits, signed, ok := v.integer()
+	if !ok {
+		return 0, unravel.ErrNotSupported
+	}
+
+	if signed && int64(bits) < 0 {
+		return 0, fmt.Errorf("invalid %T value %d: %w", T(0), int64(bits), strconv.ErrRange)
+	}
+
+	if bits > maxValue {
+		return 0, fmt.Errorf("invalid %T value %d: %w", T(0), bits, strconv.ErrRange)
+	}
+
+	return T(bits), nil
+}
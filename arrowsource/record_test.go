@@ -0,0 +1,59 @@
+package arrowsource
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/go-gum/unravel"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func TestRecordSource(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "sensor", Type: arrow.BinaryTypes.String},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int16},
+		{Name: "flags", Type: arrow.PrimitiveTypes.Uint8, Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	}, nil)
+
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"temp", "humidity"}, nil)
+	builder.Field(1).(*array.Int16Builder).AppendValues([]int16{-12, 300}, nil)
+	builder.Field(2).(*array.Uint8Builder).AppendValues([]uint8{7, 0}, []bool{true, false})
+
+	tags := builder.Field(3).(*array.ListBuilder)
+	tags.Append(true)
+	tags.ValueBuilder().(*array.StringBuilder).AppendValues([]string{"indoor", "celsius"}, nil)
+	tags.Append(true)
+
+	record := builder.NewRecord()
+	defer record.Release()
+
+	type Measurement struct {
+		Sensor string   `json:"sensor"`
+		Value  int16    `json:"value"`
+		Flags  *uint8   `json:"flags"`
+		Tags   []string `json:"tags"`
+	}
+
+	flags := uint8(7)
+
+	parsed, err := unravel.UnmarshalNew[[]Measurement](RecordSource{Record: record})
+	require.NoError(t, err)
+	require.Equal(t, parsed, []Measurement{
+		{Sensor: "temp", Value: -12, Flags: &flags, Tags: []string{"indoor", "celsius"}},
+		{Sensor: "humidity", Value: 300},
+	})
+
+	// a value of 300 does not fit into an int8
+	type Narrow struct {
+		Value int8 `json:"value"`
+	}
+
+	_, err = unravel.UnmarshalNew[[]Narrow](RecordSource{Record: record})
+	require.ErrorIs(t, err, strconv.ErrRange)
+}
@@ -0,0 +1,137 @@
+package unravel
+
+import (
+	"iter"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// staticSource serves prebuilt values without allocating, so that benchmarks
+// only measure the allocations of the decoder.
+type staticSource struct {
+	EmptySource
+	keys     []string
+	values   []Source
+	elements []Source
+}
+
+func (s staticSource) Get(key string) (Source, error) {
+	for idx, candidate := range s.keys {
+		if candidate == key {
+			return s.values[idx], nil
+		}
+	}
+
+	return nil, ErrNoValue
+}
+
+func (s staticSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	return func(yield func(Source, Source) bool) {
+		for idx, key := range s.keys {
+			if !yield(StringSource(key), s.values[idx]) {
+				return
+			}
+		}
+	}, nil
+}
+
+func (s staticSource) Iter() (iter.Seq[Source], error) {
+	return func(yield func(Source) bool) {
+		for _, element := range s.elements {
+			if !yield(element) {
+				return
+			}
+		}
+	}, nil
+}
+
+type benchmarkRecord struct {
+	Name    string  `json:"name"`
+	Email   string  `json:"email"`
+	Age     int     `json:"age"`
+	Score   float64 `json:"score"`
+	Active  bool    `json:"active"`
+	Visits  uint32  `json:"visits"`
+	Comment string  `json:"comment"`
+}
+
+var benchmarkRecordSource = staticSource{
+	keys: []string{"name", "email", "age", "score", "active", "visits"},
+	values: []Source{
+		StringSource("alex"),
+		StringSource("alex@example.com"),
+		StringSource("42"),
+		StringSource("1.5"),
+		StringSource("true"),
+		StringSource("1234"),
+	},
+}
+
+type benchmarkCollections struct {
+	Tags   []string       `json:"tags"`
+	Counts map[string]int `json:"counts"`
+	Point  [3]float64     `json:"point"`
+	Record *benchmarkRecord
+}
+
+var benchmarkCollectionsSource = staticSource{
+	keys: []string{"tags", "counts", "point", "Record"},
+	values: []Source{
+		staticSource{elements: []Source{StringSource("a"), StringSource("b"), StringSource("c")}},
+		staticSource{keys: []string{"a", "b"}, values: []Source{StringSource("1"), StringSource("2")}},
+		staticSource{elements: []Source{StringSource("1"), StringSource("2"), StringSource("3")}},
+		benchmarkRecordSource,
+	},
+}
+
+func benchmarkUnmarshal[T any](b *testing.B, dec *Decoder, source Source) {
+	b.ReportAllocs()
+
+	var target T
+	for range b.N {
+		if err := dec.Unmarshal(source, &target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalStruct(b *testing.B) {
+	benchmarkUnmarshal[benchmarkRecord](b, NewDecoder(), benchmarkRecordSource)
+}
+
+func BenchmarkUnmarshalCollections(b *testing.B) {
+	dec := NewDecoder().WithSlicePolicy(SliceReuse)
+	benchmarkUnmarshal[benchmarkCollections](b, dec, benchmarkCollectionsSource)
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	input := `{"tags": ["a", "b", "c"], "counts": {"a": 1, "b": 2}, "point": [1, 2, 3],
+		"Record": {"name": "alex", "email": "alex@example.com", "age": 42, "score": 1.5, "active": true, "visits": 1234}}`
+
+	b.ReportAllocs()
+
+	var target benchmarkCollections
+	for range b.N {
+		if err := Unmarshal(JSONStreamSource(strings.NewReader(input)), &target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalInt(b *testing.B) {
+	benchmarkUnmarshal[int64](b, NewDecoder(), StringSource("1234"))
+}
+
+func BenchmarkUnmarshalBestEffort(b *testing.B) {
+	benchmarkUnmarshal[benchmarkRecord](b, NewDecoder().BestEffort(), benchmarkRecordSource)
+}
+
+func BenchmarkUnmarshalMap(b *testing.B) {
+	input := map[string]int{}
+	for idx := range 100 {
+		input["key"+strconv.Itoa(idx)] = idx
+	}
+
+	benchmarkUnmarshal[map[string]int](b, NewDecoder(), SourceOf(input))
+}
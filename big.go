@@ -0,0 +1,148 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+var tyBigInt = reflect.TypeFor[big.Int]()
+var tyBigFloat = reflect.TypeFor[big.Float]()
+var tyBigRat = reflect.TypeFor[big.Rat]()
+
+// numberTextOf returns the textual representation of a number. Strings are returned
+// as is. For numbers, the raw representation is used if the source implements [RawSource],
+// so no precision is lost by going through an int64 or float64.
+func numberTextOf(source Source) (string, error) {
+	text, err := source.String()
+	if !errors.Is(err, ErrNotSupported) {
+		return text, err
+	}
+
+	rawSource, ok := source.(RawSource)
+	if !ok {
+		return "", ErrNotSupported
+	}
+
+	raw, err := rawSource.Raw()
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+// setBigInt sets a [big.Int] from a string or raw number, falling back to the int
+// and uint accessors of the source.
+func setBigInt(ctx context.Context, source Source, target reflect.Value) error {
+	value := target.Addr().Interface().(*big.Int)
+
+	text, err := numberTextOf(source)
+	switch {
+	case err == nil:
+		if err := value.UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("parse big.Int %q: %w", text, err)
+		}
+
+		return nil
+
+	case !errors.Is(err, ErrNotSupported):
+		return fmt.Errorf("get string value: %w", err)
+	}
+
+	intValue, err := source.Int()
+	if err == nil {
+		value.SetInt64(intValue)
+		return nil
+	}
+
+	// the value might still fit into an uint64
+	uintValue, err := source.Uint()
+	if err != nil {
+		return fmt.Errorf("get int value: %w", err)
+	}
+
+	value.SetUint64(uintValue)
+	return nil
+}
+
+// setBigFloat sets a [big.Float] from a string or raw number, falling back to the
+// float accessor of the source. If the target has no precision yet, the precision is
+// chosen large enough to hold all digits of the text.
+func setBigFloat(ctx context.Context, source Source, target reflect.Value) error {
+	value := target.Addr().Interface().(*big.Float)
+
+	text, err := numberTextOf(source)
+	switch {
+	case err == nil:
+		if value.Prec() == 0 {
+			value.SetPrec(bigFloatPrecOf(text))
+		}
+
+		if _, _, err := value.Parse(text, 0); err != nil {
+			return fmt.Errorf("parse big.Float %q: %w", text, err)
+		}
+
+		return nil
+
+	case !errors.Is(err, ErrNotSupported):
+		return fmt.Errorf("get string value: %w", err)
+	}
+
+	floatValue, err := source.Float()
+	if err != nil {
+		return fmt.Errorf("get float value: %w", err)
+	}
+
+	if math.IsNaN(floatValue) {
+		return fmt.Errorf("invalid big.Float value %v: %w", floatValue, ErrNotSupported)
+	}
+
+	value.SetFloat64(floatValue)
+	return nil
+}
+
+// bigFloatPrecOf returns a precision large enough to hold all digits of the number.
+func bigFloatPrecOf(text string) uint {
+	// each decimal digit takes about 3.3 bits
+	return max(64, uint(len(text))*4)
+}
+
+// setBigRat sets a [big.Rat] from a string or raw number, e.g. "1/3" or "0.125", falling
+// back to the int and float accessors of the source.
+func setBigRat(ctx context.Context, source Source, target reflect.Value) error {
+	value := target.Addr().Interface().(*big.Rat)
+
+	text, err := numberTextOf(source)
+	switch {
+	case err == nil:
+		if err := value.UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("parse big.Rat %q: %w", text, err)
+		}
+
+		return nil
+
+	case !errors.Is(err, ErrNotSupported):
+		return fmt.Errorf("get string value: %w", err)
+	}
+
+	intValue, err := source.Int()
+	if err == nil {
+		value.SetInt64(intValue)
+		return nil
+	}
+
+	floatValue, err := source.Float()
+	if err != nil {
+		return fmt.Errorf("get float value: %w", err)
+	}
+
+	if value.SetFloat64(floatValue) == nil {
+		return fmt.Errorf("invalid big.Rat value %v: %w", floatValue, ErrNotSupported)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBigNumbers(t *testing.T) {
+	type Amounts struct {
+		Balance  big.Int    `json:"balance"`
+		Supply   *big.Int   `json:"supply"`
+		Price    big.Float  `json:"price"`
+		Ratio    big.Rat    `json:"ratio"`
+		Fraction *big.Rat   `json:"fraction"`
+		Values   []*big.Int `json:"values"`
+	}
+
+	input := `{
+		"balance": 123456789012345678901234567890,
+		"supply": "0xff",
+		"price": 0.1000000000000000000000000001,
+		"ratio": 0.125,
+		"fraction": "1/3",
+		"values": [1, "18446744073709551616"]
+	}`
+
+	amounts, err := UnmarshalNew[Amounts](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	require.Equal(t, amounts.Balance.String(), "123456789012345678901234567890")
+	require.Equal(t, amounts.Supply.String(), "255")
+	require.Equal(t, amounts.Price.Text('f', 28), "0.1000000000000000000000000001")
+	require.Equal(t, amounts.Ratio.RatString(), "1/8")
+	require.Equal(t, amounts.Fraction.RatString(), "1/3")
+	require.Equal(t, amounts.Values[0].String(), "1")
+	require.Equal(t, amounts.Values[1].String(), "18446744073709551616")
+}
+
+func TestDecodeBigNumbersFromAccessors(t *testing.T) {
+	type Amounts struct {
+		Balance big.Int   `json:"balance"`
+		Large   big.Int   `json:"large"`
+		Price   big.Float `json:"price"`
+		Ratio   big.Rat   `json:"ratio"`
+	}
+
+	values := map[string]any{
+		"balance": -42,
+		"large":   uint64(1 << 63),
+		"price":   1.5,
+		"ratio":   0.25,
+	}
+
+	amounts, err := UnmarshalNew[Amounts](SourceOf(values))
+	require.NoError(t, err)
+
+	require.Equal(t, amounts.Balance.String(), "-42")
+	require.Equal(t, amounts.Large.String(), "9223372036854775808")
+	require.Equal(t, amounts.Price.String(), "1.5")
+	require.Equal(t, amounts.Ratio.RatString(), "1/4")
+
+	_, err = UnmarshalNew[big.Int](StringSource("1.5"))
+	require.Error(t, err)
+}
@@ -0,0 +1,759 @@
+package unravel
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"hash/crc32"
+	"io"
+	"iter"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// binaryOptions holds the options parsed from a `bin` struct tag.
+// Options are separated by comma, e.g. `bin:"varint"`.
+type binaryOptions struct {
+	// Decode an integer as LEB128 varint. Signed integers use zig-zag encoding.
+	Varint bool
+
+	// Decode a string or byte slice by first reading its length in the given
+	// encoding. One of u8, u16, u32, u64 or varint.
+	LengthPrefix string
+
+	// Decode a string from exactly this many bytes, trimming trailing padding.
+	StringLength int
+
+	// Decode a zero terminated string.
+	CString bool
+
+	// Seek to the given offset before decoding the field. Either a number or the
+	// name of a previously decoded integer field holding the offset.
+	Offset string
+
+	// Skip this many bytes before decoding the field.
+	Skip uint64
+
+	// Name of a previously decoded integer field that holds the number
+	// of elements of a slice.
+	CountOf string
+
+	// Name of a previously decoded field that selects the variant of
+	// a union struct to decode.
+	Switch string
+
+	// The value of the switch field that selects this variant of a union.
+	Case string
+
+	// Name of the checksum algorithm to verify the field against the bytes
+	// consumed by the previous fields of the struct.
+	Checksum string
+}
+
+func parseBinaryOptions(tag string) (binaryOptions, error) {
+	var opts binaryOptions
+
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+
+		switch key {
+		case "":
+			// empty tag or trailing comma
+			continue
+
+		case "varint":
+			opts.Varint = true
+
+		case "lenprefix":
+			switch value {
+			case "u8", "u16", "u32", "u64", "varint":
+				opts.LengthPrefix = value
+			default:
+				return binaryOptions{}, fmt.Errorf("invalid length prefix %q", value)
+			}
+
+		case "strlen":
+			length, err := strconv.Atoi(value)
+			if err != nil || length < 0 {
+				return binaryOptions{}, fmt.Errorf("invalid string length %q", value)
+			}
+
+			opts.StringLength = length
+
+		case "cstr":
+			opts.CString = true
+
+		case "skip":
+			skip, err := strconv.ParseUint(value, 0, 64)
+			if err != nil {
+				return binaryOptions{}, fmt.Errorf("invalid skip %q", value)
+			}
+
+			opts.Skip = skip
+
+		case "countof":
+			if value == "" {
+				return binaryOptions{}, fmt.Errorf("empty countof in bin tag")
+			}
+
+			opts.CountOf = value
+
+		case "switch":
+			if value == "" {
+				return binaryOptions{}, fmt.Errorf("empty switch in bin tag")
+			}
+
+			opts.Switch = value
+
+		case "case":
+			if value == "" {
+				return binaryOptions{}, fmt.Errorf("empty case in bin tag")
+			}
+
+			opts.Case = value
+
+		case "checksum":
+			if value == "" {
+				return binaryOptions{}, fmt.Errorf("empty checksum in bin tag")
+			}
+
+			opts.Checksum = value
+
+		case "offset":
+			if value == "" {
+				return binaryOptions{}, fmt.Errorf("empty offset in bin tag")
+			}
+
+			opts.Offset = value
+
+		default:
+			return binaryOptions{}, fmt.Errorf("unknown bin tag option %q", option)
+		}
+	}
+
+	// count the options that define how to decode the value itself
+	var valueOptions int
+	for _, isSet := range []bool{opts.Varint, opts.LengthPrefix != "", opts.StringLength > 0, opts.CString, opts.CountOf != "", opts.Switch != ""} {
+		if isSet {
+			valueOptions++
+		}
+	}
+
+	if valueOptions > 1 {
+		return binaryOptions{}, fmt.Errorf("conflicting options in bin tag %q", tag)
+	}
+
+	return opts, nil
+}
+
+// makeSetVarint returns a setter that decodes integer values as varints. If the source
+// implements [VarintSource], the varint is decoded by the source, otherwise the
+// varint is read byte by byte using [BinarySource.Uint8].
+func makeSetVarint(ty reflect.Type) (setter, error) {
+	switch ty.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		setter := func(ctx context.Context, source Source, target reflect.Value) error {
+			intValue, err := readVarint(source)
+			if err != nil {
+				return fmt.Errorf("get varint value: %w", err)
+			}
+
+			if target.OverflowInt(intValue) {
+				return &OverflowError{Value: intValue, TargetType: target.Type()}
+			}
+
+			target.SetInt(intValue)
+			return nil
+		}
+
+		return setter, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		setter := func(ctx context.Context, source Source, target reflect.Value) error {
+			intValue, err := readUvarint(source)
+			if err != nil {
+				return fmt.Errorf("get uvarint value: %w", err)
+			}
+
+			if target.OverflowUint(intValue) {
+				return &OverflowError{Value: intValue, TargetType: target.Type()}
+			}
+
+			target.SetUint(intValue)
+			return nil
+		}
+
+		return setter, nil
+
+	default:
+		return nil, fmt.Errorf("varint on non integer type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+}
+
+func readVarint(source Source) (int64, error) {
+	if varintSource, ok := source.(VarintSource); ok {
+		return varintSource.Varint()
+	}
+
+	binarySource, ok := source.(BinarySource)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return binary.ReadVarint(byteReader{binarySource})
+}
+
+func readUvarint(source Source) (uint64, error) {
+	if varintSource, ok := source.(VarintSource); ok {
+		return varintSource.Uvarint()
+	}
+
+	binarySource, ok := source.(BinarySource)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return binary.ReadUvarint(byteReader{binarySource})
+}
+
+// makeSetLengthPrefixed returns a setter that reads the length of a string or byte slice
+// using the given prefix encoding, followed by exactly that many bytes. The length is
+// checked against the [Limits] of the decoder before the bytes are read.
+func (d *Decoder) makeSetLengthPrefixed(ty reflect.Type, prefix string) (setter, error) {
+	isBytes := ty.Kind() == reflect.Slice && ty.Elem().Kind() == reflect.Uint8
+	if ty.Kind() != reflect.String && !isBytes {
+		return nil, fmt.Errorf("length prefix on type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		binarySource, ok := source.(BinarySource)
+		if !ok {
+			return fmt.Errorf("read length prefixed value: %w", ErrNotSupported)
+		}
+
+		length, err := readLengthPrefix(source, binarySource, prefix)
+		if err != nil {
+			return fmt.Errorf("read length prefix: %w", err)
+		}
+
+		switch {
+		case isBytes && d.limits.MaxSliceLen > 0 && length > uint64(d.limits.MaxSliceLen):
+			return fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, d.limits.MaxSliceLen)
+
+		case !isBytes && d.limits.MaxStringLen > 0 && length > uint64(d.limits.MaxStringLen):
+			return fmt.Errorf("%w: string of %d bytes is longer than %d bytes", ErrLimitExceeded, length, d.limits.MaxStringLen)
+		}
+
+		buf, err := readBytes(binarySource, length)
+		if err != nil {
+			return fmt.Errorf("read %d bytes: %w", length, err)
+		}
+
+		if isBytes {
+			target.SetBytes(buf)
+		} else {
+			target.SetString(string(buf))
+		}
+
+		return nil
+	}
+
+	return setter, nil
+}
+
+// makeSetFixedString returns a setter that reads a string of exactly length bytes.
+// The string ends at the first zero byte, trailing spaces are removed.
+func (d *Decoder) makeSetFixedString(ty reflect.Type, length int) (setter, error) {
+	if ty.Kind() != reflect.String {
+		return nil, fmt.Errorf("strlen on type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		binarySource, ok := source.(BinarySource)
+		if !ok {
+			return fmt.Errorf("read fixed length string: %w", ErrNotSupported)
+		}
+
+		buf, err := readBytes(binarySource, uint64(length))
+		if err != nil {
+			return fmt.Errorf("read %d bytes: %w", length, err)
+		}
+
+		if idx := bytes.IndexByte(buf, 0); idx >= 0 {
+			buf = buf[:idx]
+		}
+
+		// the number of bytes read is given by the tag, only the string is limited
+		buf = bytes.TrimRight(buf, " ")
+		if d.limits.MaxStringLen > 0 && len(buf) > d.limits.MaxStringLen {
+			return fmt.Errorf("%w: string of %d bytes is longer than %d bytes", ErrLimitExceeded, len(buf), d.limits.MaxStringLen)
+		}
+
+		target.SetString(string(buf))
+		return nil
+	}
+
+	return setter, nil
+}
+
+// makeSetCString returns a setter that reads a zero terminated string.
+// The terminating zero byte is consumed but not included in the string.
+func (d *Decoder) makeSetCString(ty reflect.Type) (setter, error) {
+	if ty.Kind() != reflect.String {
+		return nil, fmt.Errorf("cstr on type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		binarySource, ok := source.(BinarySource)
+		if !ok {
+			return fmt.Errorf("read zero terminated string: %w", ErrNotSupported)
+		}
+
+		var buf []byte
+		for {
+			value, err := binarySource.Uint8()
+			if err != nil {
+				return fmt.Errorf("read zero terminated string: %w", err)
+			}
+
+			if value == 0 {
+				break
+			}
+
+			if d.limits.MaxStringLen > 0 && len(buf) >= d.limits.MaxStringLen {
+				return fmt.Errorf("%w: string is longer than %d bytes", ErrLimitExceeded, d.limits.MaxStringLen)
+			}
+
+			buf = append(buf, value)
+		}
+
+		target.SetString(string(buf))
+		return nil
+	}
+
+	return setter, nil
+}
+
+func readLengthPrefix(source Source, binarySource BinarySource, prefix string) (uint64, error) {
+	switch prefix {
+	case "u8":
+		length, err := binarySource.Uint8()
+		return uint64(length), err
+	case "u16":
+		length, err := binarySource.Uint16()
+		return uint64(length), err
+	case "u32":
+		length, err := binarySource.Uint32()
+		return uint64(length), err
+	case "u64":
+		return binarySource.Uint64()
+	case "varint":
+		return readUvarint(source)
+	default:
+		panic("unknown length prefix " + prefix)
+	}
+}
+
+// readBytes reads exactly n bytes from the source. If the source implements
+// [ReadBytesSource], the bytes are read in one call. Otherwise, the bytes are
+// read one by one using [BinarySource.Uint8].
+func readBytes(source BinarySource, n uint64) ([]byte, error) {
+	if bytesSource, ok := source.(ReadBytesSource); ok {
+		return bytesSource.ReadBytes(n)
+	}
+
+	var buf []byte
+	for range n {
+		value, err := source.Uint8()
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, value)
+	}
+
+	return buf, nil
+}
+
+// makeSetCountOf returns a fieldSetter that decodes exactly as many slice elements as
+// given by the value of the integer field countField of the parent struct.
+func (d *Decoder) makeSetCountOf(inConstruction typeSet, structType reflect.Type, ty reflect.Type, countField string) (fieldSetter, error) {
+	if ty.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("countof on type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+
+	countOf, err := makeIntFieldGetter(structType, countField)
+	if err != nil {
+		return nil, fmt.Errorf("countof: %w", err)
+	}
+
+	elementSetter, err := d.setterOf(inConstruction, ty.Elem())
+	if err != nil {
+		return nil, fmt.Errorf("setter for element type %q: %w", ty, err)
+	}
+
+	setter := func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		count := countOf(structValue)
+		if count < 0 || uint64(count) > math.MaxInt {
+			// the count is not a valid length of the slice
+			return &OverflowError{Value: count, TargetType: ty}
+		}
+
+		if d.limits.MaxSliceLen > 0 && count > int64(d.limits.MaxSliceLen) {
+			return fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, d.limits.MaxSliceLen)
+		}
+
+		sourceIter, err := iterContext(ctx, source)
+		if err != nil {
+			return fmt.Errorf("as iter: %w", err)
+		}
+
+		next, stop := iter.Pull(sourceIter)
+		defer stop()
+
+		sliceValue := reflect.MakeSlice(ty, 0, 0)
+
+		for idx := range int(count) {
+			elementSource, ok := next()
+			if !ok {
+				return fmt.Errorf("expected %d elements, got %d: %w", count, idx, io.ErrUnexpectedEOF)
+			}
+
+			sliceValue = reflect.Append(sliceValue, reflect.Zero(ty.Elem()))
+			if err := elementSetter(ctx, elementSource, sliceValue.Index(idx)); err != nil {
+				return withPath(err, indexSegment(idx), ty.Elem())
+			}
+		}
+
+		fieldValue.Set(sliceValue)
+		return nil
+	}
+
+	return setter, nil
+}
+
+// makeSetSwitch returns a fieldSetter for a union struct. Every exported field of the
+// union struct is a variant tagged with `bin:"case=..."`. The variant whose case matches
+// the value of the field switchField in the parent struct is decoded from the source,
+// all other variants are left untouched. A variant tagged with `bin:"case=default"`
+// is decoded if no other case matches.
+func (d *Decoder) makeSetSwitch(inConstruction typeSet, structType reflect.Type, ty reflect.Type, switchField string) (fieldSetter, error) {
+	if ty.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("switch on type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+
+	discriminatorField, ok := structType.FieldByName(switchField)
+	if !ok {
+		return nil, fmt.Errorf("switch: field %q not found in %q", switchField, structType)
+	}
+
+	type Variant struct {
+		Index   []int
+		Matches func(discriminator reflect.Value) bool
+		Setter  setter
+	}
+
+	var variants []Variant
+	var defaultVariant *Variant
+
+	for idx := range ty.NumField() {
+		fi := ty.Field(idx)
+		if !fi.IsExported() {
+			continue
+		}
+
+		binOpts, err := parseBinaryOptions(fi.Tag.Get("bin"))
+		if err != nil {
+			return nil, fmt.Errorf("variant %q: %w", fi.Name, err)
+		}
+
+		if binOpts.Case == "" {
+			return nil, fmt.Errorf("variant %q has no case", fi.Name)
+		}
+
+		variantSetter, err := d.valueSetterOf(inConstruction, fi.Type, binOpts)
+		if err != nil {
+			return nil, fmt.Errorf("setter for variant %q: %w", fi.Name, err)
+		}
+
+		variant := Variant{Index: fi.Index, Setter: variantSetter}
+
+		if binOpts.Case == "default" {
+			defaultVariant = &variant
+			continue
+		}
+
+		variant.Matches, err = makeCaseMatcher(discriminatorField.Type, binOpts.Case)
+		if err != nil {
+			return nil, fmt.Errorf("variant %q: %w", fi.Name, err)
+		}
+
+		variants = append(variants, variant)
+	}
+
+	setter := func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		discriminator := structValue.FieldByIndex(discriminatorField.Index)
+
+		for _, variant := range variants {
+			if variant.Matches(discriminator) {
+				return variant.Setter(ctx, source, fieldValue.FieldByIndex(variant.Index))
+			}
+		}
+
+		if defaultVariant != nil {
+			return defaultVariant.Setter(ctx, source, fieldValue.FieldByIndex(defaultVariant.Index))
+		}
+
+		return fmt.Errorf("no variant for %s=%v: %w", switchField, discriminator, ErrNotSupported)
+	}
+
+	return setter, nil
+}
+
+// makeCaseMatcher parses the value of a case option according to the type of the
+// discriminator field and returns a function that compares it to a discriminator.
+func makeCaseMatcher(ty reflect.Type, caseValue string) (func(reflect.Value) bool, error) {
+	switch ty.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) bool { return v.String() == caseValue }, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := strconv.ParseInt(caseValue, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid case %q: %w", caseValue, err)
+		}
+
+		return func(v reflect.Value) bool { return v.Int() == intValue }, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		intValue, err := strconv.ParseUint(caseValue, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid case %q: %w", caseValue, err)
+		}
+
+		return func(v reflect.Value) bool { return v.Uint() == intValue }, nil
+
+	default:
+		return nil, fmt.Errorf("switch on field of type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+}
+
+// Checksum computes the checksum of the given data. Checksums are registered by name using
+// [Decoder.WithChecksum] and referenced from a struct field tagged with `bin:"checksum=name"`.
+// The field is verified against the checksum of all bytes consumed from the source by the
+// preceding fields of the same struct. This requires the source to implement [RecordingSource].
+//
+// The following algorithms are available by default:
+//   - crc32: CRC-32 using the IEEE polynomial, see [crc32.ChecksumIEEE]
+//   - crc32c: CRC-32 using the Castagnoli polynomial
+//   - adler32: Adler-32, see [adler32.Checksum]
+type Checksum func(data []byte) uint64
+
+var defaultChecksums = map[string]Checksum{
+	"crc32": func(data []byte) uint64 {
+		return uint64(crc32.ChecksumIEEE(data))
+	},
+
+	"crc32c": func(data []byte) uint64 {
+		return uint64(crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)))
+	},
+
+	"adler32": func(data []byte) uint64 {
+		return uint64(adler32.Checksum(data))
+	},
+}
+
+// ChecksumError is returned if a field tagged with `bin:"checksum=..."` does not
+// match the checksum computed over the bytes preceding it.
+type ChecksumError struct {
+	Algorithm string
+	Expected  uint64
+	Actual    uint64
+}
+
+func (c ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected 0x%x, got 0x%x", c.Algorithm, c.Expected, c.Actual)
+}
+
+// checksumField describes a struct field that holds a checksum.
+type checksumField struct {
+	FieldIdx  int
+	Algorithm string
+	Checksum  Checksum
+}
+
+// checksumFieldOf finds the field tagged with a checksum option.
+// Returns nil if no field holds a checksum.
+func (d *Decoder) checksumFieldOf(fields []field) (*checksumField, error) {
+	var result *checksumField
+
+	for idx, field := range fields {
+		binOpts, err := parseBinaryOptions(field.Tag.Get("bin"))
+		if err != nil {
+			return nil, err
+		}
+
+		if binOpts.Checksum == "" {
+			continue
+		}
+
+		if result != nil {
+			return nil, fmt.Errorf("field %q: only one checksum field per struct is supported", field.Name)
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, fmt.Errorf("checksum on type %q: %w", field.Type, NotSupportedError{Type: field.Type})
+		}
+
+		checksum, ok := d.checksums[binOpts.Checksum]
+		if !ok {
+			checksum, ok = defaultChecksums[binOpts.Checksum]
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("field %q: unknown checksum %q", field.Name, binOpts.Checksum)
+		}
+
+		result = &checksumField{FieldIdx: idx, Algorithm: binOpts.Checksum, Checksum: checksum}
+	}
+
+	return result, nil
+}
+
+// Verify compares the decoded checksum field to the checksum of the recorded bytes.
+func (c *checksumField) Verify(recorded []byte, fieldValue reflect.Value) error {
+	expected := fieldValue.Uint()
+
+	actual := c.Checksum(recorded)
+	if actual != expected {
+		return ChecksumError{Algorithm: c.Algorithm, Expected: expected, Actual: actual}
+	}
+
+	return nil
+}
+
+// A seekOffset computes the offset and whence for a call to [SeekerSource.Seek]
+// from the struct containing the field that is about to be decoded.
+type seekOffset func(structValue reflect.Value) (offset int64, whence int)
+
+// makeSeekOffset parses the value of an `offset` option. Numbers are absolute offsets
+// from the start of the input, numbers with an explicit sign are relative to the current
+// position. Any other value names an integer field of the struct holding an absolute offset.
+func makeSeekOffset(structType reflect.Type, offset string) (seekOffset, error) {
+	if offsetValue, err := strconv.ParseInt(offset, 0, 64); err == nil {
+		whence := io.SeekStart
+		if offset[0] == '+' || offset[0] == '-' {
+			whence = io.SeekCurrent
+		}
+
+		return func(reflect.Value) (int64, int) { return offsetValue, whence }, nil
+	}
+
+	offsetOf, err := makeIntFieldGetter(structType, offset)
+	if err != nil {
+		return nil, fmt.Errorf("offset: %w", err)
+	}
+
+	seek := func(structValue reflect.Value) (int64, int) {
+		return offsetOf(structValue), io.SeekStart
+	}
+
+	return seek, nil
+}
+
+// makeIntFieldGetter returns a function to read the integer field with the given
+// name from a struct of type structType.
+func makeIntFieldGetter(structType reflect.Type, name string) (func(structValue reflect.Value) int64, error) {
+	field, ok := structType.FieldByName(name)
+	if !ok {
+		return nil, fmt.Errorf("field %q not found in %q", name, structType)
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		getter := func(structValue reflect.Value) int64 {
+			return structValue.FieldByIndex(field.Index).Int()
+		}
+
+		return getter, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		getter := func(structValue reflect.Value) int64 {
+			return int64(structValue.FieldByIndex(field.Index).Uint())
+		}
+
+		return getter, nil
+
+	default:
+		return nil, fmt.Errorf("field %q is not an integer", name)
+	}
+}
+
+// withSeek wraps a fieldSetter to seek the source to an offset before decoding the field.
+func withSeek(seek seekOffset, setter fieldSetter) fieldSetter {
+	return func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		seekerSource, ok := source.(SeekerSource)
+		if !ok {
+			return fmt.Errorf("seek: %w", ErrNotSupported)
+		}
+
+		offset, whence := seek(structValue)
+		if _, err := seekerSource.Seek(offset, whence); err != nil {
+			return fmt.Errorf("seek to offset %d: %w", offset, err)
+		}
+
+		return setter(ctx, source, structValue, fieldValue)
+	}
+}
+
+// withSkip wraps a fieldSetter to discard count bytes before decoding the field.
+func withSkip(count uint64, setter fieldSetter) fieldSetter {
+	return func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		if err := skipBytes(source, count); err != nil {
+			return fmt.Errorf("skip %d bytes: %w", count, err)
+		}
+
+		return setter(ctx, source, structValue, fieldValue)
+	}
+}
+
+// skipBytes discards count bytes. Uses a relative seek if the source implements
+// [SeekerSource], otherwise the bytes are read and discarded. A [BinaryReader] reads
+// the bytes while recording, so that the skipped bytes are part of a checksum.
+func skipBytes(source Source, count uint64) error {
+	if seekerSource, ok := source.(SeekerSource); ok && count <= math.MaxInt64 {
+		_, err := seekerSource.Seek(int64(count), io.SeekCurrent)
+		if !errors.Is(err, ErrNotSupported) {
+			return err
+		}
+	}
+
+	binarySource, ok := source.(BinarySource)
+	if !ok {
+		return ErrNotSupported
+	}
+
+	for range count {
+		if _, err := binarySource.Uint8(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// byteReader adapts a [BinarySource] to the [io.ByteReader] interface.
+type byteReader struct {
+	BinarySource
+}
+
+func (b byteReader) ReadByte() (byte, error) {
+	return b.Uint8()
+}
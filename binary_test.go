@@ -0,0 +1,414 @@
+package unravel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"hash/crc32"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestBinaryVarint(t *testing.T) {
+	type Struct struct {
+		Length uint64 `bin:"varint"`
+		Delta  int32  `bin:"varint"`
+		Fixed  uint16
+	}
+
+	buf := binary.AppendUvarint(nil, 300)
+	buf = binary.AppendVarint(buf, -2)
+	buf = binary.LittleEndian.AppendUint16(buf, 0x0102)
+
+	parsed, err := UnmarshalNew[Struct](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Struct{Length: 300, Delta: -2, Fixed: 0x0102})
+}
+
+func TestBinaryVarintWithoutVarintSource(t *testing.T) {
+	type Struct struct {
+		Value int64 `bin:"varint"`
+	}
+
+	// hide the VarintSource implementation, only keep the BinarySource methods
+	source := struct {
+		Source
+		BinarySource
+	}{}
+
+	reader := BinaryReaderSource(bytes.NewReader(binary.AppendVarint(nil, -1234567)), binary.LittleEndian)
+	source.Source, source.BinarySource = reader, reader
+
+	parsed, err := UnmarshalNew[Struct](source)
+	require.NoError(t, err)
+	require.Equal(t, parsed, Struct{Value: -1234567})
+}
+
+func TestBinaryVarintOverflow(t *testing.T) {
+	type Struct struct {
+		Value uint8 `bin:"varint"`
+	}
+
+	buf := binary.AppendUvarint(nil, 256)
+
+	_, err := UnmarshalNew[Struct](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.ErrorIs(t, err, strconv.ErrRange)
+
+	var overflowErr *OverflowError
+	require.ErrorAs(t, err, &overflowErr)
+	require.Equal(t, overflowErr.Value, uint64(256))
+	require.Equal(t, overflowErr.TargetType, reflect.TypeFor[uint8]())
+
+	type Signed struct {
+		Value int8 `bin:"varint"`
+	}
+
+	buf = binary.AppendVarint(nil, -129)
+
+	_, err = UnmarshalNew[Signed](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.ErrorAs(t, err, &overflowErr)
+	require.Equal(t, overflowErr.Value, int64(-129))
+	require.Equal(t, overflowErr.TargetType, reflect.TypeFor[int8]())
+}
+
+func TestBinaryVarintUnsupportedType(t *testing.T) {
+	type Struct struct {
+		Value string `bin:"varint"`
+	}
+
+	_, err := UnmarshalNew[Struct](EmptySource{})
+
+	var notSupportedError NotSupportedError
+	require.ErrorAs(t, err, &notSupportedError)
+}
+
+func TestBinaryLengthPrefix(t *testing.T) {
+	type Struct struct {
+		Name    string `bin:"lenprefix=u16"`
+		Payload []byte `bin:"lenprefix=varint"`
+		Empty   string `bin:"lenprefix=u8"`
+		Trailer uint8
+	}
+
+	buf := binary.BigEndian.AppendUint16(nil, 5)
+	buf = append(buf, "hello"...)
+	buf = binary.AppendUvarint(buf, 3)
+	buf = append(buf, 1, 2, 3)
+	buf = append(buf, 0)
+	buf = append(buf, 0xff)
+
+	parsed, err := UnmarshalNew[Struct](BinaryReaderSource(bytes.NewReader(buf), binary.BigEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Struct{Name: "hello", Payload: []byte{1, 2, 3}, Empty: "", Trailer: 0xff})
+}
+
+func TestBinaryLengthPrefixTruncated(t *testing.T) {
+	type Struct struct {
+		Name string `bin:"lenprefix=u32"`
+	}
+
+	buf := binary.LittleEndian.AppendUint32(nil, 1<<30)
+	buf = append(buf, "short"...)
+
+	_, err := UnmarshalNew[Struct](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestBinaryLengthPrefixInvalid(t *testing.T) {
+	type Struct struct {
+		Name string `bin:"lenprefix=u24"`
+	}
+
+	_, err := UnmarshalNew[Struct](EmptySource{})
+	require.ErrorContains(t, err, `invalid length prefix "u24"`)
+}
+
+func TestBinaryOffset(t *testing.T) {
+	type Struct struct {
+		PixelOffset uint32
+		Padded      uint8   `bin:"offset=+2"`
+		Pixels      [3]byte `bin:"offset=PixelOffset"`
+		First       uint8   `bin:"offset=0x00"`
+	}
+
+	buf := binary.LittleEndian.AppendUint32(nil, 10)
+	buf = append(buf, 0, 0, 42, 0, 0, 0, 7, 8, 9)
+
+	parsed, err := UnmarshalNew[Struct](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Struct{PixelOffset: 10, Padded: 42, Pixels: [3]byte{7, 8, 9}, First: 10})
+}
+
+func TestBinaryOffsetNotSeekable(t *testing.T) {
+	type Struct struct {
+		Value uint8 `bin:"offset=4"`
+	}
+
+	source := BinaryReaderSource(bytes.NewBuffer([]byte{1, 2, 3, 4, 5}), binary.LittleEndian)
+
+	_, err := UnmarshalNew[Struct](source)
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestBinaryOffsetUnknownField(t *testing.T) {
+	type Struct struct {
+		Value uint8 `bin:"offset=Missing"`
+	}
+
+	_, err := UnmarshalNew[Struct](EmptySource{})
+	require.ErrorContains(t, err, `offset: field "Missing" not found`)
+}
+
+func TestBinaryFixedLengthAndZeroTerminatedStrings(t *testing.T) {
+	type Struct struct {
+		ChunkID string `bin:"strlen=4"`
+		Name    string `bin:"strlen=8"`
+		Volume  string `bin:"strlen=6"`
+		Comment string `bin:"cstr"`
+		Trailer uint8
+	}
+
+	var buf []byte
+	buf = append(buf, "RIFF"...)
+	buf = append(buf, "foo.txt\x00"...)
+	buf = append(buf, "CD    "...)
+	buf = append(buf, "hello world\x00"...)
+	buf = append(buf, 0xff)
+
+	parsed, err := UnmarshalNew[Struct](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Struct{
+		ChunkID: "RIFF",
+		Name:    "foo.txt",
+		Volume:  "CD",
+		Comment: "hello world",
+		Trailer: 0xff,
+	})
+}
+
+func TestBinaryConflictingOptions(t *testing.T) {
+	type Struct struct {
+		Name string `bin:"cstr,strlen=4"`
+	}
+
+	_, err := UnmarshalNew[Struct](EmptySource{})
+	require.ErrorContains(t, err, "conflicting options")
+}
+
+func TestBinarySkip(t *testing.T) {
+	type Struct struct {
+		Version  uint8
+		Flags    uint8 `bin:"skip=3"`
+		Checksum uint8 `bin:"offset=0,skip=0x05"`
+	}
+
+	buf := []byte{1, 0xaa, 0xaa, 0xaa, 2, 3}
+
+	// skip using Seek
+	parsed, err := UnmarshalNew[Struct](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Struct{Version: 1, Flags: 2, Checksum: 3})
+
+	type Sequential struct {
+		Version uint8
+		Flags   uint8 `bin:"skip=3"`
+	}
+
+	// skip by reading the bytes
+	sequential, err := UnmarshalNew[Sequential](BinaryReaderSource(bytes.NewBuffer(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, sequential, Sequential{Version: 1, Flags: 2})
+}
+
+func TestBinaryCountOf(t *testing.T) {
+	type Entry struct {
+		ID   uint16
+		Name string `bin:"lenprefix=u8"`
+	}
+
+	type Directory struct {
+		NumEntries uint8
+		Entries    []Entry `bin:"countof=NumEntries"`
+		Trailer    uint8
+	}
+
+	buf := []byte{2}
+	buf = append(buf, 1, 0, 3, 'f', 'o', 'o')
+	buf = append(buf, 2, 0, 3, 'b', 'a', 'r')
+	buf = append(buf, 0xff)
+
+	parsed, err := UnmarshalNew[Directory](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Directory{
+		NumEntries: 2,
+		Entries:    []Entry{{ID: 1, Name: "foo"}, {ID: 2, Name: "bar"}},
+		Trailer:    0xff,
+	})
+}
+
+func TestBinaryCountOfTruncated(t *testing.T) {
+	type Directory struct {
+		NumEntries uint8
+		Entries    []uint16 `bin:"countof=NumEntries"`
+	}
+
+	buf := []byte{3, 1, 0, 2, 0}
+
+	_, err := UnmarshalNew[Directory](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestBinaryCountOfNegative(t *testing.T) {
+	type Directory struct {
+		NumEntries int8
+		Entries    []uint16 `bin:"countof=NumEntries"`
+	}
+
+	_, err := UnmarshalNew[Directory](BinaryReaderSource(bytes.NewReader([]byte{0xff}), binary.LittleEndian))
+
+	var overflowErr *OverflowError
+	require.ErrorAs(t, err, &overflowErr)
+	require.Equal(t, overflowErr.Value, int64(-1))
+	require.Equal(t, overflowErr.TargetType, reflect.TypeFor[[]uint16]())
+}
+
+func TestBinarySwitch(t *testing.T) {
+	type Header struct {
+		Width  uint32
+		Height uint32
+	}
+
+	type Text struct {
+		Keyword string `bin:"cstr"`
+	}
+
+	type Chunk struct {
+		Length uint32
+		Type   string `bin:"strlen=4"`
+		Data   struct {
+			Header *Header `bin:"case=IHDR"`
+			Text   *Text   `bin:"case=tEXt"`
+			Raw    []byte  `bin:"case=default,lenprefix=u8"`
+		} `bin:"switch=Type"`
+	}
+
+	var buf []byte
+	buf = binary.BigEndian.AppendUint32(buf, 8)
+	buf = append(buf, "IHDR"...)
+	buf = binary.BigEndian.AppendUint32(buf, 3)
+	buf = binary.BigEndian.AppendUint32(buf, 5)
+
+	buf = binary.BigEndian.AppendUint32(buf, 4)
+	buf = append(buf, "tEXt"...)
+	buf = append(buf, "foo\x00"...)
+
+	buf = binary.BigEndian.AppendUint32(buf, 3)
+	buf = append(buf, "IEND"...)
+	buf = append(buf, 2, 0xaa, 0xbb)
+
+	parsed, err := UnmarshalNew[[]Chunk](BinaryReaderSource(bytes.NewReader(buf), binary.BigEndian))
+	require.NoError(t, err)
+	require.Len(t, parsed, 3)
+
+	require.Equal(t, parsed[0].Data.Header, &Header{Width: 3, Height: 5})
+	require.Nil(t, parsed[0].Data.Text)
+
+	require.Equal(t, parsed[1].Data.Text, &Text{Keyword: "foo"})
+	require.Nil(t, parsed[1].Data.Header)
+
+	require.Equal(t, parsed[2].Data.Raw, []byte{0xaa, 0xbb})
+}
+
+func TestBinarySwitchNoMatchingVariant(t *testing.T) {
+	type Message struct {
+		Kind    uint8
+		Payload struct {
+			Ping *uint8  `bin:"case=1"`
+			Pong *uint16 `bin:"case=0x02"`
+		} `bin:"switch=Kind"`
+	}
+
+	buf := []byte{3, 0}
+
+	_, err := UnmarshalNew[Message](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.ErrorIs(t, err, ErrNotSupported)
+	require.ErrorContains(t, err, "no variant for Kind=3")
+}
+
+func TestBinaryChecksum(t *testing.T) {
+	type Packet struct {
+		Kind    uint8
+		Payload string `bin:"lenprefix=u8"`
+		CRC     uint32 `bin:"checksum=crc32"`
+	}
+
+	encode := func(crc uint32) []byte {
+		buf := []byte{7, 5}
+		buf = append(buf, "hello"...)
+		return binary.LittleEndian.AppendUint32(buf, crc)
+	}
+
+	valid := encode(crc32.ChecksumIEEE([]byte("\x07\x05hello")))
+
+	parsed, err := UnmarshalNew[Packet](BinaryReaderSource(bytes.NewReader(valid), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed.Payload, "hello")
+
+	_, err = UnmarshalNew[Packet](BinaryReaderSource(bytes.NewReader(encode(1234)), binary.LittleEndian))
+
+	var checksumErr ChecksumError
+	require.ErrorAs(t, err, &checksumErr)
+	require.Equal(t, checksumErr.Algorithm, "crc32")
+	require.Equal(t, checksumErr.Expected, uint64(1234))
+}
+
+func TestBinaryChecksumSkip(t *testing.T) {
+	type Packet struct {
+		Kind    uint8
+		Payload uint16 `bin:"skip=2"`
+		CRC     uint32 `bin:"checksum=crc32"`
+	}
+
+	buf := []byte{7, 0xaa, 0xbb, 1, 2}
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+
+	expected := Packet{Kind: 7, Payload: 0x0201, CRC: crc32.ChecksumIEEE(buf[:5])}
+
+	// the skipped bytes are part of the checksum, whether the reader can seek or not
+	parsed, err := UnmarshalNew[Packet](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, expected)
+
+	parsed, err = UnmarshalNew[Packet](BinaryReaderSource(bytes.NewBuffer(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, expected)
+}
+
+func TestBinaryChecksumCustomAlgorithm(t *testing.T) {
+	type Frame struct {
+		Data [3]byte
+		Sum  uint8 `bin:"checksum=sum8"`
+	}
+
+	sum8 := func(data []byte) uint64 {
+		var sum uint8
+		for _, b := range data {
+			sum += b
+		}
+
+		return uint64(sum)
+	}
+
+	dec := NewDecoder().WithChecksum("sum8", sum8)
+
+	buf := []byte{1, 2, 3, 6}
+	parsed, err := UnmarshalNewWith[Frame](dec, BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Frame{Data: [3]byte{1, 2, 3}, Sum: 6})
+
+	// the default decoder does not know the algorithm
+	_, err = UnmarshalNew[Frame](BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian))
+	require.ErrorContains(t, err, `unknown checksum "sum8"`)
+}
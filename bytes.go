@@ -0,0 +1,111 @@
+package unravel
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var tyByte = reflect.TypeFor[byte]()
+
+// withBytes wraps the setter of a byte slice or byte array type. If the source
+// implements [BytesSource], the bytes are copied into the target at once. Byte arrays
+// are read at once from sources implementing [ReadBytesSource], e.g. a [BinaryReader].
+// Otherwise, the value is decoded using the given setter.
+func withBytes(ty reflect.Type, next setter) setter {
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		if bytesSource, ok := source.(BytesSource); ok {
+			bytes, err := bytesSource.Bytes()
+			switch {
+			case err == nil:
+				setBytes(ty, bytes, target)
+				return nil
+
+			case !errors.Is(err, ErrNotSupported):
+				return fmt.Errorf("get bytes: %w", err)
+			}
+		}
+
+		if readBytesSource, ok := source.(ReadBytesSource); ok && ty.Kind() == reflect.Array {
+			// a binary stream can not end early, all bytes of the array must be present
+			bytes, err := readBytesSource.ReadBytes(uint64(ty.Len()))
+			if err != nil {
+				return fmt.Errorf("read %d bytes: %w", ty.Len(), err)
+			}
+
+			setBytes(ty, bytes, target)
+			return nil
+		}
+
+		return next(ctx, source, target)
+	}
+}
+
+// setBytes copies the bytes into the target of the given byte slice or byte array type.
+func setBytes(ty reflect.Type, bytes []byte, target reflect.Value) {
+	if ty.Kind() == reflect.Array {
+		// same as for other arrays, surplus bytes are ignored
+		reflect.Copy(target, reflect.ValueOf(bytes))
+		return
+	}
+
+	// same as for other slices, the bytes are appended
+	target.Set(reflect.AppendSlice(target, reflect.ValueOf(bytes).Convert(ty)))
+}
+
+// bytesDecoders contains the encodings supported by the `bytes` struct tag.
+var bytesDecoders = map[string]func(string) ([]byte, error){
+	"base64":    base64.StdEncoding.DecodeString,
+	"rawbase64": base64.RawStdEncoding.DecodeString,
+	"base64url": base64.URLEncoding.DecodeString,
+	"hex":       hex.DecodeString,
+}
+
+// makeSetEncodedBytes returns a setter for a field tagged with `bytes:"encoding"`. The value
+// is read as string and decoded using the encoding, e.g. base64 or hex. If the source does not
+// provide a string, the value is decoded using the default setter of the type.
+func (d *Decoder) makeSetEncodedBytes(inConstruction typeSet, ty reflect.Type, encoding string) (setter, error) {
+	decode, ok := bytesDecoders[encoding]
+	if !ok {
+		return nil, fmt.Errorf("unknown bytes encoding %q", encoding)
+	}
+
+	isByteSlice := ty.Kind() == reflect.Slice && ty.Elem() == tyByte
+	isByteArray := ty.Kind() == reflect.Array && ty.Elem() == tyByte
+	if !isByteSlice && !isByteArray {
+		return nil, fmt.Errorf("bytes encoding %q on type %q: %w", encoding, ty, NotSupportedError{Type: ty})
+	}
+
+	next, err := d.setterOf(inConstruction, ty)
+	if err != nil {
+		return nil, err
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		text, err := source.String()
+		switch {
+		case errors.Is(err, ErrNotSupported):
+			return next(ctx, source, target)
+
+		case err != nil:
+			return fmt.Errorf("get string value: %w", err)
+		}
+
+		decoded, err := decode(text)
+		if err != nil {
+			return fmt.Errorf("decode %s: %w", encoding, err)
+		}
+
+		if isByteArray && len(decoded) != ty.Len() {
+			return fmt.Errorf("decode %s: got %d bytes, expected %d", encoding, len(decoded), ty.Len())
+		}
+
+		setBytes(ty, decoded, target)
+		return nil
+	}
+
+	return setter, nil
+}
@@ -0,0 +1,122 @@
+package unravel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+// blobSource provides bytes only using the BytesSource interface
+type blobSource struct {
+	EmptySource
+	Blob []byte
+}
+
+func (b blobSource) Bytes() ([]byte, error) {
+	return b.Blob, nil
+}
+
+func TestDecodeBytes(t *testing.T) {
+	type Blob []byte
+
+	blob := []byte{1, 2, 3, 4}
+
+	value, err := UnmarshalNew[[]byte](blobSource{Blob: blob})
+	require.NoError(t, err)
+	require.Equal(t, value, []byte{1, 2, 3, 4})
+
+	// the decoded value does not share memory with the source
+	blob[0] = 9
+	require.Equal(t, value[0], byte(1))
+
+	named, err := UnmarshalNew[Blob](blobSource{Blob: blob})
+	require.NoError(t, err)
+	require.Equal(t, named, Blob{9, 2, 3, 4})
+
+	array, err := UnmarshalNew[[2]byte](blobSource{Blob: blob})
+	require.NoError(t, err)
+	require.Equal(t, array, [2]byte{9, 2})
+}
+
+func TestDecodeBytesFallsBackToIter(t *testing.T) {
+	value, err := UnmarshalNew[[]byte](SourceOf([]int{1, 2}))
+	require.NoError(t, err)
+	require.Equal(t, value, []byte{1, 2})
+}
+
+func TestDecodeByteArrayFromBinaryReader(t *testing.T) {
+	type Record struct {
+		Version uint8
+		Hash    [16]byte
+		Flags   uint16
+	}
+
+	input := []byte{1}
+	for idx := range 16 {
+		input = append(input, byte(idx))
+	}
+
+	input = append(input, 0xca, 0xfe)
+
+	record, err := UnmarshalNew[Record](BinaryReaderSource(bytes.NewReader(input), binary.BigEndian))
+	require.NoError(t, err)
+	require.Equal(t, record, Record{
+		Version: 1,
+		Hash:    [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		Flags:   0xcafe,
+	})
+
+	// all bytes of the array must be present
+	_, err = UnmarshalNew[Record](BinaryReaderSource(bytes.NewReader(input[:8]), binary.BigEndian))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestDecodeEncodedBytes(t *testing.T) {
+	type Message struct {
+		Payload   []byte  `json:"payload" bytes:"base64"`
+		Signature [4]byte `json:"signature" bytes:"hex"`
+		Token     []byte  `json:"token" bytes:"base64url"`
+		Nonce     []byte  `json:"nonce" bytes:"rawbase64"`
+		Raw       []byte  `json:"raw" bytes:"hex"`
+	}
+
+	source := SourceOf(map[string]any{
+		"payload":   "aGVsbG8=",
+		"signature": "cafebabe",
+		"token":     "-_8=",
+		"nonce":     "AQI",
+		"raw":       []int{1, 2},
+	})
+
+	message, err := UnmarshalNew[Message](source)
+	require.NoError(t, err)
+	require.Equal(t, message, Message{
+		Payload:   []byte("hello"),
+		Signature: [4]byte{0xca, 0xfe, 0xba, 0xbe},
+		Token:     []byte{0xfb, 0xff},
+		Nonce:     []byte{1, 2},
+		Raw:       []byte{1, 2},
+	})
+
+	_, err = UnmarshalNew[Message](SourceOf(map[string]any{"signature": "cafe"}))
+	require.Error(t, err)
+
+	_, err = UnmarshalNew[Message](SourceOf(map[string]any{"payload": "not base64!"}))
+	require.Error(t, err)
+
+	type Invalid struct {
+		Value string `bytes:"base64"`
+	}
+
+	_, err = UnmarshalNew[Invalid](SourceOf(map[string]any{}))
+	require.ErrorAs(t, err, &NotSupportedError{})
+
+	type Unknown struct {
+		Value []byte `bytes:"base32"`
+	}
+
+	_, err = UnmarshalNew[Unknown](SourceOf(map[string]any{}))
+	require.Error(t, err)
+}
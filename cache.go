@@ -0,0 +1,47 @@
+package unravel
+
+import "reflect"
+
+// ResetCache discards the setters the [Decoder] has built for the types it decoded, see
+// [Decoder.Prepare]. The setters are built again when a value of a type is decoded next.
+// This releases the types, which are otherwise referenced by the decoder for its lifetime,
+// e.g. types created using [reflect.StructOf] or provided by a plugin. Decoding values
+// while the cache is reset is safe.
+func (d *Decoder) ResetCache() {
+	d.setterCache.Clear()
+	d.cacheSize.Store(0)
+
+	// the arenas belong to the discarded setters, see WithArena
+	d.releaseArenas()
+
+	d.arenas.mu.Lock()
+	d.arenas.arenas = nil
+	d.arenas.mu.Unlock()
+
+	// the decoders of deeper nesting levels are created again, see WithMaxDepth
+	d.deeper.Store(nil)
+}
+
+// WithCacheLimit returns a [Decoder] that holds the setters of at most limit types. Once
+// the limit is exceeded, all setters are discarded like using [Decoder.ResetCache], and
+// built again on demand. This bounds the memory used by long-lived decoders that decode
+// values of an open ended set of types. The limit should be well above the number of
+// types that are decoded regularly, including the types of all fields and elements.
+// A limit of zero or less removes the limit.
+func (d *Decoder) WithCacheLimit(limit int) *Decoder {
+	derived := d.clone()
+	derived.cacheLimit = max(limit, 0)
+	return derived
+}
+
+// storeSetter adds the setter of the type to the cache, resetting the cache if
+// it holds more setters than allowed by WithCacheLimit.
+func (d *Decoder) storeSetter(ty reflect.Type, setter setter) {
+	if _, loaded := d.setterCache.Swap(ty, setter); loaded {
+		return
+	}
+
+	if size := d.cacheSize.Add(1); d.cacheLimit > 0 && size > int64(d.cacheLimit) {
+		d.ResetCache()
+	}
+}
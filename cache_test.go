@@ -0,0 +1,61 @@
+package unravel
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestDecoderResetCache(t *testing.T) {
+	type Node struct {
+		Name     string `json:"name"`
+		Children []Node `json:"children"`
+	}
+
+	tyNode := reflect.TypeFor[Node]()
+
+	dec := NewDecoder()
+
+	setter, err := dec.setterOf(typeSet{}, tyNode)
+	require.NoError(t, err)
+
+	dec.ResetCache()
+
+	_, ok := dec.setterCache.Load(tyNode)
+	require.False(t, ok)
+
+	// setters built before the reset keep working
+	var node Node
+	err = setter(context.Background(), SourceOf(map[string]any{"name": "a", "children": []any{map[string]any{"name": "b"}}}), reflect.ValueOf(&node).Elem())
+	require.NoError(t, err)
+	require.Equal(t, node, Node{Name: "a", Children: []Node{{Name: "b"}}})
+
+	node, err = UnmarshalNewWith[Node](dec, SourceOf(map[string]any{"name": "c"}))
+	require.NoError(t, err)
+	require.Equal(t, node, Node{Name: "c"})
+
+	_, ok = dec.setterCache.Load(tyNode)
+	require.True(t, ok)
+}
+
+func TestDecoderWithCacheLimit(t *testing.T) {
+	type Row struct {
+		Name  string  `json:"name"`
+		Count int     `json:"count"`
+		Score float64 `json:"score"`
+	}
+
+	dec := NewDecoder().WithCacheLimit(3)
+
+	for range 10 {
+		row, err := UnmarshalNewWith[Row](dec, SourceOf(map[string]any{"name": "a", "count": 1, "score": 1.5}))
+		require.NoError(t, err)
+		require.Equal(t, row, Row{Name: "a", Count: 1, Score: 1.5})
+
+		_, err = UnmarshalNewWith[[]uint8](dec, SourceOf([]int{1, 2}))
+		require.NoError(t, err)
+
+		require.LessOrEqual(t, dec.cacheSize.Load(), int64(3))
+	}
+}
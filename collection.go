@@ -0,0 +1,85 @@
+package unravel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ElementAppender is the interface implemented by container types that receive the
+// elements of a list one by one, e.g. sets, ring buffers or lists enforcing invariants.
+// The [Decoder] calls AppendElement for each element yielded by [Source.Iter], without
+// decoding into an intermediate slice first.
+//
+// [Unmarshaler] takes precedence over ElementAppender.
+//
+// Example:
+//
+//	type Set[T comparable] map[T]struct{}
+//
+//	func (s *Set[T]) AppendElement(source unravel.Source) error {
+//	    value, err := unravel.UnmarshalNew[T](source)
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    if *s == nil {
+//	        *s = Set[T]{}
+//	    }
+//
+//	    (*s)[value] = struct{}{}
+//	    return nil
+//	}
+type ElementAppender interface {
+	AppendElement(source Source) error
+}
+
+// MapWriter is the interface implemented by container types that receive the entries
+// of a map one by one. The [Decoder] calls WriteEntry for each key/value pair yielded by
+// [Source.KeyValues], without decoding into an intermediate map first.
+//
+// [Unmarshaler] and [ElementAppender] take precedence over MapWriter.
+type MapWriter interface {
+	WriteEntry(key, value Source) error
+}
+
+var tyElementAppender = reflect.TypeFor[ElementAppender]()
+var tyMapWriter = reflect.TypeFor[MapWriter]()
+
+func setElementAppender(ctx context.Context, source Source, target reflect.Value) error {
+	sourceIter, err := iterContext(ctx, source)
+	if err != nil {
+		return fmt.Errorf("as iter: %w", err)
+	}
+
+	appender := target.Addr().Interface().(ElementAppender)
+
+	var idx int
+	for elementSource := range sourceIter {
+		if err := appender.AppendElement(elementSource); err != nil {
+			// the type of the elements is only known to the appender
+			return withPath(err, indexSegment(idx), nil)
+		}
+
+		idx++
+	}
+
+	return nil
+}
+
+func setMapWriter(ctx context.Context, source Source, target reflect.Value) error {
+	keyValues, err := keyValuesContext(ctx, source)
+	if err != nil {
+		return fmt.Errorf("iterate key/value pairs: %w", err)
+	}
+
+	writer := target.Addr().Interface().(MapWriter)
+
+	for keySource, valueSource := range keyValues {
+		if err := writer.WriteEntry(keySource, valueSource); err != nil {
+			return withPath(err, keySegmentOf(keySource), nil)
+		}
+	}
+
+	return nil
+}
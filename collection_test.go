@@ -0,0 +1,90 @@
+package unravel
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+type testSet[T comparable] map[T]struct{}
+
+func (s *testSet[T]) AppendElement(source Source) error {
+	value, err := UnmarshalNew[T](source)
+	if err != nil {
+		return err
+	}
+
+	if *s == nil {
+		*s = testSet[T]{}
+	}
+
+	(*s)[value] = struct{}{}
+	return nil
+}
+
+// boundedList accepts at most two elements
+type boundedList struct {
+	Values []string
+}
+
+func (b *boundedList) AppendElement(source Source) error {
+	if len(b.Values) == 2 {
+		return errors.New("too many values")
+	}
+
+	value, err := source.String()
+	if err != nil {
+		return err
+	}
+
+	b.Values = append(b.Values, value)
+	return nil
+}
+
+// lowerKeys lower cases all keys written to it
+type lowerKeys map[string]string
+
+func (l *lowerKeys) WriteEntry(key, value Source) error {
+	keyText, err := key.String()
+	if err != nil {
+		return err
+	}
+
+	valueText, err := value.String()
+	if err != nil {
+		return err
+	}
+
+	if *l == nil {
+		*l = lowerKeys{}
+	}
+
+	(*l)[strings.ToLower(keyText)] = valueText
+	return nil
+}
+
+func TestDecodeCollectionInterfaces(t *testing.T) {
+	type Request struct {
+		Tags    testSet[string] `json:"tags"`
+		Hosts   boundedList     `json:"hosts"`
+		Headers lowerKeys       `json:"headers"`
+	}
+
+	input := `{
+		"tags": ["a", "b", "a"],
+		"hosts": ["localhost", "example.com"],
+		"headers": {"Content-Type": "text/plain"}
+	}`
+
+	request, err := UnmarshalNew[Request](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, request, Request{
+		Tags:    testSet[string]{"a": {}, "b": {}},
+		Hosts:   boundedList{Values: []string{"localhost", "example.com"}},
+		Headers: lowerKeys{"content-type": "text/plain"},
+	})
+
+	_, err = UnmarshalNew[Request](SourceOf(map[string]any{"hosts": []string{"a", "b", "c"}}))
+	require.EqualError(t, err, "decode $.hosts[2]: too many values")
+}
@@ -0,0 +1,110 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+)
+
+// RegisterConstructor returns a [Decoder] that constructs values of type `T` from scalar
+// values using fn, e.g. a struct from a string like "tcp://localhost:8080". The function
+// receives the textual representation of the scalar: strings as is, numbers in their raw
+// or formatted representation and booleans as "true" or "false".
+//
+// If the [Source] holds an object or an array, as reported by [KindSource], or has no
+// textual representation, the value is decoded as usual, e.g. field by field for a struct.
+//
+// Example:
+//
+//	type Endpoint struct {
+//	    Scheme string `json:"scheme"`
+//	    Host   string `json:"host"`
+//	}
+//
+//	dec := unravel.RegisterConstructor(unravel.NewDecoder(), func(text string) (Endpoint, error) {
+//	    scheme, host, ok := strings.Cut(text, "://")
+//	    if !ok {
+//	        return Endpoint{}, fmt.Errorf("invalid endpoint %q", text)
+//	    }
+//
+//	    return Endpoint{Scheme: scheme, Host: host}, nil
+//	})
+//
+//	// decodes both "tcp://localhost" and {"scheme": "tcp", "host": "localhost"}
+func RegisterConstructor[T any](dec *Decoder, fn func(string) (T, error)) *Decoder {
+	construct := func(text string, target reflect.Value) error {
+		value, err := fn(text)
+		if err != nil {
+			return err
+		}
+
+		target.Set(reflect.ValueOf(&value).Elem())
+		return nil
+	}
+
+	derived := dec.clone()
+	derived.constructors = maps.Clone(dec.constructors)
+	if derived.constructors == nil {
+		derived.constructors = map[reflect.Type]func(string, reflect.Value) error{}
+	}
+
+	derived.constructors[reflect.TypeFor[T]()] = construct
+	return derived
+}
+
+// withConstructor wraps the setter of a type to construct the value from scalar values.
+func withConstructor(construct func(string, reflect.Value) error, next setter) setter {
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		text, err := scalarTextOf(ctx, source)
+		switch {
+		case errors.Is(err, ErrNotSupported):
+			return next(ctx, source, target)
+
+		case err != nil:
+			return err
+		}
+
+		if err := construct(text, target); err != nil {
+			return fmt.Errorf("construct %q from %q: %w", target.Type(), text, err)
+		}
+
+		return nil
+	}
+}
+
+// scalarTextOf returns the textual representation of a scalar value. Returns
+// [ErrNotSupported] if the source holds an object or an array.
+func scalarTextOf(ctx context.Context, source Source) (string, error) {
+	if kindSource, ok := source.(KindSource); ok {
+		switch kindSource.Kind() {
+		case KindObject, KindArray:
+			return "", ErrNotSupported
+		case KindNull:
+			return "", ErrNoValue
+		case KindBool:
+			boolValue, err := source.Bool()
+			if err != nil {
+				return "", fmt.Errorf("get bool value: %w", err)
+			}
+
+			return strconv.FormatBool(boolValue), nil
+		case KindNumber:
+			var number Number
+			if err := setNumber(ctx, source, reflect.ValueOf(&number).Elem()); err != nil {
+				return "", err
+			}
+
+			return number.String(), nil
+		}
+	}
+
+	text, err := source.String()
+	if err != nil && !errors.Is(err, ErrNotSupported) {
+		return "", fmt.Errorf("get string value: %w", err)
+	}
+
+	return text, err
+}
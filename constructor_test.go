@@ -0,0 +1,76 @@
+package unravel
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type endpoint struct {
+	Scheme string `json:"scheme"`
+	Host   string `json:"host"`
+	Port   int    `json:"port"`
+}
+
+func parseEndpoint(text string) (endpoint, error) {
+	scheme, rest, ok := strings.Cut(text, "://")
+	if !ok {
+		return endpoint{}, fmt.Errorf("missing scheme in %q", text)
+	}
+
+	host, portText, _ := strings.Cut(rest, ":")
+
+	port, err := strconv.Atoi(portText)
+	if err != nil {
+		return endpoint{}, err
+	}
+
+	return endpoint{Scheme: scheme, Host: host, Port: port}, nil
+}
+
+func TestRegisterConstructor(t *testing.T) {
+	type Quantity struct {
+		Amount float64 `json:"amount"`
+		Unit   string  `json:"unit"`
+	}
+
+	type Config struct {
+		Primary   endpoint   `json:"primary"`
+		Secondary endpoint   `json:"secondary"`
+		Fallbacks []endpoint `json:"fallbacks"`
+		Limit     Quantity   `json:"limit"`
+	}
+
+	dec := RegisterConstructor(NewDecoder(), parseEndpoint)
+	dec = RegisterConstructor(dec, func(text string) (Quantity, error) {
+		amount, err := strconv.ParseFloat(text, 64)
+		return Quantity{Amount: amount, Unit: "pcs"}, err
+	})
+
+	input := `{
+		"primary": "tcp://localhost:8080",
+		"secondary": {"scheme": "udp", "host": "example.com", "port": 53},
+		"fallbacks": ["tcp://a:1", "tcp://b:2"],
+		"limit": 1.5
+	}`
+
+	for _, source := range []Source{Raw{Bytes: []byte(input)}.Source(), JSONStreamSource(strings.NewReader(input))} {
+		config, err := UnmarshalNewWith[Config](dec, source)
+		require.NoError(t, err)
+		require.Equal(t, config, Config{
+			Primary:   endpoint{Scheme: "tcp", Host: "localhost", Port: 8080},
+			Secondary: endpoint{Scheme: "udp", Host: "example.com", Port: 53},
+			Fallbacks: []endpoint{{Scheme: "tcp", Host: "a", Port: 1}, {Scheme: "tcp", Host: "b", Port: 2}},
+			Limit:     Quantity{Amount: 1.5, Unit: "pcs"},
+		})
+	}
+
+	_, err := UnmarshalNewWith[Config](dec, SourceOf(map[string]any{"primary": "localhost"}))
+	require.ErrorContains(t, err, "missing scheme")
+
+	// the default decoder is not affected
+	_, err = UnmarshalNew[Config](SourceOf(map[string]any{"primary": "tcp://localhost:8080"}))
+	require.Error(t, err)
+}
@@ -0,0 +1,166 @@
+package unravel
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+var tySyncMap = reflect.TypeFor[sync.Map]()
+var tyList = reflect.TypeFor[list.List]()
+
+// setSyncMap decodes the entries of a map into a [sync.Map]. Keys are stored as strings,
+// values are materialized into their natural Go representation, see [KindSource]. Unless
+// the [MapPolicy] of the decoder is [MapMerge], existing entries are removed first.
+func (d *Decoder) setSyncMap(ctx context.Context, source Source, target reflect.Value) error {
+	keyValues, err := keyValuesContext(ctx, source)
+	if err != nil {
+		return fmt.Errorf("iterate key/value pairs: %w", err)
+	}
+
+	syncMap := target.Addr().Interface().(*sync.Map)
+
+	if d.mapPolicy != MapMerge {
+		syncMap.Clear()
+	}
+
+	var entryCount int
+
+	for keySource, valueSource := range keyValues {
+		entryCount++
+		if d.limits.MaxMapEntries > 0 && entryCount > d.limits.MaxMapEntries {
+			return fmt.Errorf("%w: more than %d map entries", ErrLimitExceeded, d.limits.MaxMapEntries)
+		}
+
+		key, err := keySource.String()
+		if err != nil {
+			return fmt.Errorf("get key: %w", err)
+		}
+
+		value, err := d.materialize(ctx, valueSource)
+		if err != nil {
+			return withPath(err, keySegment(key), tyAny)
+		}
+
+		syncMap.Store(key, value)
+	}
+
+	return nil
+}
+
+// setList decodes the elements of a list into a [list.List]. Elements are materialized
+// into their natural Go representation, see [KindSource]. Unless the [SlicePolicy] of
+// the decoder is [SliceAppend], existing elements are removed first.
+func (d *Decoder) setList(ctx context.Context, source Source, target reflect.Value) error {
+	sourceIter, err := iterContext(ctx, source)
+	if err != nil {
+		return fmt.Errorf("as iter: %w", err)
+	}
+
+	targetList := target.Addr().Interface().(*list.List)
+
+	if d.slicePolicy != SliceAppend {
+		targetList.Init()
+	}
+
+	var idx int
+	for elementSource := range sourceIter {
+		if d.limits.MaxSliceLen > 0 && idx >= d.limits.MaxSliceLen {
+			return fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, d.limits.MaxSliceLen)
+		}
+
+		value, err := d.materialize(ctx, elementSource)
+		if err != nil {
+			return withPath(err, indexSegment(idx), tyAny)
+		}
+
+		targetList.PushBack(value)
+		idx++
+	}
+
+	return nil
+}
+
+// emitSyncMap writes the entries of a [sync.Map] ordered by key. All keys must be strings.
+func (e *Encoder) emitSyncMap(sink Sink, value reflect.Value) error {
+	type entry struct {
+		Key   string
+		Value any
+	}
+
+	var entries []entry
+	var keyErr error
+
+	syncMap := addressableOf(value).Interface().(*sync.Map)
+
+	syncMap.Range(func(key, value any) bool {
+		keyString, ok := key.(string)
+		if !ok {
+			keyErr = fmt.Errorf("key of type %T: %w", key, ErrNotSupported)
+			return false
+		}
+
+		entries = append(entries, entry{Key: keyString, Value: value})
+		return true
+	})
+
+	if keyErr != nil {
+		return keyErr
+	}
+
+	// write entries in a stable order
+	slices.SortFunc(entries, func(a, b entry) int { return cmp.Compare(a.Key, b.Key) })
+
+	for _, entry := range entries {
+		if entry.Value == nil {
+			continue
+		}
+
+		entrySink, err := sink.Child(entry.Key)
+		if err != nil {
+			return fmt.Errorf("child %q: %w", entry.Key, err)
+		}
+
+		if err := e.emitInterface(entrySink, reflect.ValueOf(&entry.Value).Elem()); err != nil {
+			return fmt.Errorf("emit value of key %q: %w", entry.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// emitList writes the elements of a [list.List] in order.
+func (e *Encoder) emitList(sink Sink, value reflect.Value) error {
+	sourceList := addressableOf(value).Interface().(*list.List)
+
+	var idx int
+	for element := sourceList.Front(); element != nil; element = element.Next() {
+		elementSink, err := sink.Element()
+		if err != nil {
+			return fmt.Errorf("element idx=%d: %w", idx, err)
+		}
+
+		if err := e.emitInterface(elementSink, reflect.ValueOf(&element.Value).Elem()); err != nil {
+			return fmt.Errorf("emit element idx=%d: %w", idx, err)
+		}
+
+		idx++
+	}
+
+	return nil
+}
+
+// addressableOf returns a pointer to the value, copying the value if it is not addressable.
+func addressableOf(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value.Addr()
+	}
+
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	return pointer
+}
@@ -0,0 +1,130 @@
+package unravel
+
+import (
+	"container/list"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDecodeContainers(t *testing.T) {
+	type State struct {
+		Cache   sync.Map       `json:"cache"`
+		Queue   *list.List     `json:"queue"`
+		Addr    netip.Addr     `json:"addr"`
+		Prefix  netip.Prefix   `json:"prefix"`
+		Gateway netip.AddrPort `json:"gateway"`
+	}
+
+	input := `{
+		"cache": {"a": 1, "b": {"nested": true}},
+		"queue": ["first", 2],
+		"addr": "10.0.0.1",
+		"prefix": "10.0.0.0/8",
+		"gateway": "[::1]:8080"
+	}`
+
+	var state State
+	err := Unmarshal(JSONStreamSource(strings.NewReader(input)), &state)
+	require.NoError(t, err)
+
+	value, ok := state.Cache.Load("b")
+	require.True(t, ok)
+	require.Equal(t, value, map[string]any{"nested": true})
+
+	var queue []any
+	for element := state.Queue.Front(); element != nil; element = element.Next() {
+		queue = append(queue, element.Value)
+	}
+
+	require.Equal(t, queue, []any{"first", int64(2)})
+
+	require.Equal(t, state.Addr, netip.MustParseAddr("10.0.0.1"))
+	require.Equal(t, state.Prefix, netip.MustParsePrefix("10.0.0.0/8"))
+	require.Equal(t, state.Gateway, netip.MustParseAddrPort("[::1]:8080"))
+
+	// entries of the sync.Map are replaced
+	err = Unmarshal(SourceOf(map[string]any{"cache": map[string]int{"c": 3}, "queue": []int{3}}), &state)
+	require.NoError(t, err)
+
+	_, ok = state.Cache.Load("a")
+	require.False(t, ok)
+	require.Equal(t, state.Queue.Len(), 1)
+
+	// the list is appended to when merging into the existing pointer
+	err = NewDecoder().MergeInto().Unmarshal(SourceOf(map[string]any{"queue": []int{4}}), &state)
+	require.NoError(t, err)
+	require.Equal(t, state.Queue.Len(), 2)
+}
+
+func TestMarshalContainers(t *testing.T) {
+	type State struct {
+		Cache *sync.Map  `json:"cache"`
+		Queue *list.List `json:"queue"`
+	}
+
+	state := State{Cache: &sync.Map{}, Queue: list.New()}
+	state.Cache.Store("a", 1)
+	state.Queue.PushBack("x")
+	state.Queue.PushBack(true)
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+
+	err := Marshal(state, sink)
+	require.NoError(t, err)
+	require.Equal(t, sink.Values, map[string]any{
+		"$.cache.a": int64(1),
+		"$.queue.0": "x",
+		"$.queue.1": true,
+	})
+
+	state.Cache.Store(1, 1)
+
+	err = Marshal(state, sink)
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestDecodeContainersFieldPolicy(t *testing.T) {
+	type State struct {
+		Cache   sync.Map   `json:"cache" unravel:"merge"`
+		Queue   list.List  `json:"queue" unravel:"append"`
+		Pending *list.List `json:"pending" unravel:"append"`
+	}
+
+	var state State
+	err := Unmarshal(SourceOf(map[string]any{"cache": map[string]int{"a": 1}, "queue": []int{1}, "pending": []int{1}}), &state)
+	require.NoError(t, err)
+
+	// entries of the sync.Map are kept and elements are appended to the lists
+	err = NewDecoder().MergeInto().Unmarshal(SourceOf(map[string]any{"cache": map[string]int{"b": 2}, "queue": []int{2}, "pending": []int{2}}), &state)
+	require.NoError(t, err)
+
+	_, ok := state.Cache.Load("a")
+	require.True(t, ok)
+	_, ok = state.Cache.Load("b")
+	require.True(t, ok)
+
+	require.Equal(t, state.Queue.Len(), 2)
+	require.Equal(t, state.Pending.Len(), 2)
+
+	type Replaced struct {
+		Cache sync.Map  `json:"cache" unravel:"replace"`
+		Queue list.List `json:"queue" unravel:"replace"`
+	}
+
+	var replaced Replaced
+	replaced.Cache.Store("a", 1)
+	replaced.Queue.PushBack(1)
+
+	// the field tags win over the policies of the decoder
+	decoder := NewDecoder().WithMapPolicy(MapMerge).WithSlicePolicy(SliceAppend)
+	err = decoder.Unmarshal(SourceOf(map[string]any{"cache": map[string]int{"b": 2}, "queue": []int{2}}), &replaced)
+	require.NoError(t, err)
+
+	_, ok = replaced.Cache.Load("a")
+	require.False(t, ok)
+	require.Equal(t, replaced.Queue.Len(), 1)
+	require.Equal(t, replaced.Queue.Front().Value, int64(2))
+}
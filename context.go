@@ -0,0 +1,72 @@
+package unravel
+
+import (
+	"context"
+	"iter"
+)
+
+// ContextSource is an optional extension of the [Source] interface for sources that do
+// I/O while being decoded, e.g. sources backed by etcd, Vault or an SQL database, which
+// fetch values on demand. When decoding using [Decoder.UnmarshalContext], the [Decoder]
+// looks up children and iterates over the source using the methods below, passing the
+// context of the call, e.g. so that the source can pass it on to the requests it makes.
+// Sources returned by these methods are used as is, a child fetching its value lazily
+// should implement ContextSource itself.
+//
+// A method called with a canceled context should return the error of the context, which
+// is then returned by [Decoder.UnmarshalContext].
+type ContextSource interface {
+	Source
+
+	// GetContext works like [Source.Get] using the given context.
+	GetContext(ctx context.Context, key string) (Source, error)
+
+	// KeyValuesContext works like [Source.KeyValues] using the given context.
+	KeyValuesContext(ctx context.Context) (iter.Seq2[Source, Source], error)
+
+	// IterContext works like [Source.Iter] using the given context.
+	IterContext(ctx context.Context) (iter.Seq[Source], error)
+}
+
+// UnmarshalContext works like [Decoder.Unmarshal], but passes ctx to the accessors of
+// sources implementing [ContextSource]. The context is checked before each field of a
+// struct and each element of a slice, array or map, so decoding stops with the error of
+// the context once it is canceled, even for sources that do not implement ContextSource.
+// If ctx is already done, its error is returned without decoding anything.
+func (d *Decoder) UnmarshalContext(ctx context.Context, source Source, target any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return d.unmarshal(ctx, source, target)
+}
+
+// getContext returns the child of the given key, passing ctx to sources
+// implementing [ContextSource].
+func getContext(ctx context.Context, source Source, key string) (Source, error) {
+	if contextSource, ok := source.(ContextSource); ok {
+		return contextSource.GetContext(ctx, key)
+	}
+
+	return source.Get(key)
+}
+
+// keyValuesContext returns the entries of the source, passing ctx to sources
+// implementing [ContextSource].
+func keyValuesContext(ctx context.Context, source Source) (iter.Seq2[Source, Source], error) {
+	if contextSource, ok := source.(ContextSource); ok {
+		return contextSource.KeyValuesContext(ctx)
+	}
+
+	return source.KeyValues()
+}
+
+// iterContext returns the elements of the source, passing ctx to sources
+// implementing [ContextSource].
+func iterContext(ctx context.Context, source Source) (iter.Seq[Source], error) {
+	if contextSource, ok := source.(ContextSource); ok {
+		return contextSource.IterContext(ctx)
+	}
+
+	return source.Iter()
+}
@@ -0,0 +1,171 @@
+package unravel
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"iter"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// remoteSource fetches the values of its keys on demand, like a source backed by a
+// key/value store. onGet is called before each lookup.
+type remoteSource struct {
+	EmptySource
+	values map[string]string
+	onGet  func(ctx context.Context, key string)
+}
+
+var _ ContextSource = remoteSource{}
+
+func (r remoteSource) Get(key string) (Source, error) {
+	return r.GetContext(context.Background(), key)
+}
+
+func (r remoteSource) GetContext(ctx context.Context, key string) (Source, error) {
+	if r.onGet != nil {
+		r.onGet(ctx, key)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	value, ok := r.values[key]
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	return StringSource(value), nil
+}
+
+func (r remoteSource) KeyValuesContext(ctx context.Context) (iter.Seq2[Source, Source], error) {
+	return nil, ErrNotSupported
+}
+
+func (r remoteSource) IterContext(ctx context.Context) (iter.Seq[Source], error) {
+	return nil, ErrNotSupported
+}
+
+func TestDecoderUnmarshalContext(t *testing.T) {
+	type Config struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	type key struct{}
+
+	ctx := context.WithValue(context.Background(), key{}, "request")
+
+	// the context is passed to the source
+	var received []any
+	source := remoteSource{
+		values: map[string]string{"host": "localhost", "port": "8080"},
+		onGet:  func(ctx context.Context, _ string) { received = append(received, ctx.Value(key{})) },
+	}
+
+	var config Config
+	err := NewDecoder().UnmarshalContext(ctx, source, &config)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{Host: "localhost", Port: 8080})
+	require.Equal(t, received, []any{"request", "request"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// nothing is fetched once the context is done
+	var fetched []string
+	source.onGet = func(_ context.Context, key string) { fetched = append(fetched, key) }
+
+	err = UnmarshalContext(ctx, source, &config)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, fetched)
+
+	// canceled while decoding
+	ctx, cancel = context.WithCancel(context.Background())
+	source.onGet = func(_ context.Context, key string) {
+		if key == "port" {
+			cancel()
+		}
+	}
+
+	err = NewDecoder().UnmarshalContext(ctx, source, &config)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorContains(t, err, "$.port")
+}
+
+func TestDecoderUnmarshalContextCanceled(t *testing.T) {
+	type Item int
+
+	// returns a decoder that cancels the context after decoding the given number of items
+	decoderOf := func(cancelAfter int64) (*Decoder, context.Context, *atomic.Int64) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var decoded atomic.Int64
+
+		dec := NewDecoder().RegisterSetter(reflect.TypeFor[Item](), func(source Source, target reflect.Value) error {
+			if decoded.Add(1) == cancelAfter {
+				cancel()
+			}
+
+			value, err := source.Int()
+			target.SetInt(value)
+			return err
+		})
+
+		return dec, ctx, &decoded
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		type Items struct {
+			A Item `json:"a"`
+			B Item `json:"b"`
+			C Item `json:"c"`
+		}
+
+		dec, ctx, decoded := decoderOf(1)
+
+		var items Items
+		err := dec.UnmarshalContext(ctx, SourceOf(map[string]int{"a": 1, "b": 2, "c": 3}), &items)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, decoded.Load(), int64(1))
+		require.Equal(t, items, Items{A: 1})
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		dec, ctx, decoded := decoderOf(2)
+
+		var items []Item
+		err := dec.UnmarshalContext(ctx, SourceOf([]int{1, 2, 3, 4}), &items)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, decoded.Load(), int64(2))
+	})
+
+	t.Run("array", func(t *testing.T) {
+		dec, ctx, decoded := decoderOf(2)
+
+		var items [4]Item
+		err := dec.UnmarshalContext(ctx, SourceOf([]int{1, 2, 3, 4}), &items)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, decoded.Load(), int64(2))
+	})
+
+	t.Run("map", func(t *testing.T) {
+		dec, ctx, decoded := decoderOf(2)
+
+		var items map[string]Item
+		err := dec.UnmarshalContext(ctx, SourceOf(map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}), &items)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, decoded.Load(), int64(2))
+	})
+
+	t.Run("parallel slice", func(t *testing.T) {
+		dec, ctx, _ := decoderOf(1)
+
+		items := []Item{}
+		err := dec.WithParallelSlices(2).UnmarshalContext(ctx, SourceOf([]int{1, 2, 3, 4}), &items)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, items)
+	})
+}
@@ -1,29 +1,76 @@
 package unravel
 
 import (
+	"context"
 	"encoding"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"golang.org/x/exp/constraints"
 	"iter"
+	"maps"
 	"math"
 	"reflect"
-	"strconv"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
 var ErrNoValue = errors.New("no value")
 var ErrNotSupported = errors.New("not supported")
+var ErrAmbiguousField = errors.New("ambiguous field")
 
+// NotSupportedError is returned if a [Decoder] can not decode values of a type.
 type NotSupportedError struct {
+	// The type that is not supported
 	Type reflect.Type
+
+	// The struct type declaring the field of the unsupported type, if any
+	Struct reflect.Type
+
+	// The full path from the outermost struct to the field, e.g. "Server.Pool.Done"
+	Field string
 }
 
 func (n NotSupportedError) Error() string {
+	if n.Struct != nil {
+		return fmt.Sprintf("type %q of field %q in %q is not supported", n.Type, n.Field, n.Struct)
+	}
+
 	return fmt.Sprintf("type %q is not supported", n.Type)
 }
 
+// Is reports whether the target is a NotSupportedError for the same type. A target
+// without a location matches the type regardless of the field it was encountered in.
+func (n NotSupportedError) Is(target error) bool {
+	other, ok := target.(NotSupportedError)
+	if !ok || other.Type != n.Type {
+		return false
+	}
+
+	return other.Struct == nil || other == n
+}
+
+// fieldSetterError adds the struct field to the error of building its setter. A
+// [NotSupportedError] within err is returned with the location of the field, the
+// innermost struct sets the struct type, outer structs extend the path of the field.
+func fieldSetterError(err error, structType reflect.Type, fieldName string) error {
+	var notSupportedErr NotSupportedError
+	if !errors.As(err, &notSupportedErr) {
+		return fmt.Errorf("setter for field %q: %w", fieldName, err)
+	}
+
+	if notSupportedErr.Struct == nil {
+		notSupportedErr.Struct = structType
+	}
+
+	notSupportedErr.Field = joinFieldPath(fieldName, notSupportedErr.Field)
+	return notSupportedErr
+}
+
 // Unmarshal takes a [Source] and decodes it into the provided target, which must be a pointer
 // to the desired destination value. The function leverages the structure of the target type to
 // guide the decoding process.
@@ -37,12 +84,48 @@ func (n NotSupportedError) Error() string {
 // Private fields are ignored, and only exported fields are considered during decoding. Conflicts
 // are handled the same way as [encoding/json.Unmarshal] would.
 //
-// If a target value implements [encoding.TextUnmarshaler], the value will be read as string from
-// the [Source] and the [encoding.TextUnmarshaler.UnmarshalText] will be called.
+// If a target value implements [Unmarshaler], its [Unmarshaler.UnmarshalSource] method is called
+// with the [Source] of the value. Otherwise, if a target value implements [encoding.TextUnmarshaler],
+// the value will be read as string from the [Source] and the [encoding.TextUnmarshaler.UnmarshalText]
+// will be called.
+//
+// Fields tagged with `enum:"a,b,c"` must decode to one of the listed values, otherwise
+// decoding fails with an [EnumError]. This works for string and integer fields.
+//
+// Byte slices and byte arrays tagged with `bytes:"base64"` are decoded from strings using
+// the given encoding. Supported are base64, rawbase64 (without padding), base64url and hex.
+//
+// Struct fields tagged with `unravel:"tuple"` are decoded from a list of values, assigning
+// the elements to the fields of the struct in declaration order, e.g. [52.5, 13.4] to a
+// struct with the fields Lat and Lon.
+//
+// Fields tagged with `unravel:"alt=Name|user_name"` are looked up using the alternative keys
+// in the given order, if the [Source] has no value for the name of the field.
+//
+// Fields tagged with `idx:"2"` are decoded from the element at the given position, if the
+// [Source] implements [IndexSource], e.g. the columns of a row or the groups of a [RegexpSource].
+//
+// A name containing dots, e.g. `json:"payment.card.last4"`, addresses a nested value, if the
+// [Source] has no value for the name itself. This flattens nested data into a single struct.
+//
+// Fields tagged with `transform:"trim,lower"` have their string value cleaned up by the
+// listed transforms before decoding, see [Decoder.RegisterTransform].
+//
+// Structs implementing [Validator] are validated after all of their fields were decoded.
+//
+// If a value can not be decoded, a [*DecodeError] is returned that holds the path of the
+// value within the [Source], e.g. `$.items[3].price`.
 //
 // By default, [Unmarshal] uses `json` struct tags to map serialized data to fields in the
 // target struct, but this can be changed by using a [Decoder] and calling [Decoder.WithTag].
 //
+// The `unravel` struct tag configures decoding independent of other serialization formats.
+// It starts with an optional name that takes precedence over the `json` tag, followed by
+// options, e.g. `unravel:"created,required,layout=2006-01-02"`. Besides the options described
+// above, `required` requires a value for the field, `default=5` decodes the given text if the
+// [Source] has no value for the field, and `layout=...` parses a [time.Time] using the layout.
+// A map field tagged with `unravel:",remain"` collects all keys not consumed by other fields.
+//
 // Example:
 //
 //	var myStruct struct {
@@ -62,6 +145,12 @@ func Unmarshal(source Source, target any) error {
 	return dec.Unmarshal(source, target)
 }
 
+// UnmarshalContext works like [Unmarshal] but stops decoding once ctx is done, see
+// [Decoder.UnmarshalContext].
+func UnmarshalContext(ctx context.Context, source Source, target any) error {
+	return dec.UnmarshalContext(ctx, source, target)
+}
+
 // UnmarshalNew calls [Unmarshal] with an empty instance of `T`.
 func UnmarshalNew[T any](source Source) (T, error) {
 	return UnmarshalNewWith[T](&dec, source)
@@ -74,13 +163,56 @@ func UnmarshalNewWith[T any](dec *Decoder, source Source) (T, error) {
 	return target, err
 }
 
-// A setter sets a [reflect.Value] to a value extracted from the given [Source]
-type setter func(Source, reflect.Value) error
+// A setter sets a [reflect.Value] to a value extracted from the given [Source]. The
+// context is the one passed to [Decoder.UnmarshalContext], or [context.Background].
+type setter func(context.Context, Source, reflect.Value) error
+
+// A fieldSetter sets a struct field. In addition to the fields value, it receives
+// the struct containing the field, giving access to previously decoded fields.
+type fieldSetter func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error
 
 // A set of types
 type typeSet map[reflect.Type]struct{}
 
+// Unmarshaler is the interface implemented by types that can decode themselves
+// from a [Source]. It takes precedence over [encoding.TextUnmarshaler] and the
+// default decoding of the types kind, giving the type full control over how it
+// consumes its [Source].
+//
+// Example:
+//
+//	type Range struct {
+//	    Min, Max int
+//	}
+//
+//	func (r *Range) UnmarshalSource(source unravel.Source) error {
+//	    text, err := source.String()
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    _, err = fmt.Sscanf(text, "%d-%d", &r.Min, &r.Max)
+//	    return err
+//	}
+type Unmarshaler interface {
+	UnmarshalSource(source Source) error
+}
+
+var tyUnmarshaler = reflect.TypeFor[Unmarshaler]()
+
+// Validator is the interface implemented by structs that validate themselves. After
+// all fields of a struct are decoded, the [Decoder] calls Validate and fails with
+// the returned error. The error is wrapped with the path of the struct within the
+// decoded value.
+type Validator interface {
+	Validate() error
+}
+
+var tyValidator = reflect.TypeFor[Validator]()
 var tyTextUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+var tyJSONUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+var tyTime = reflect.TypeFor[time.Time]()
+var tyDuration = reflect.TypeFor[time.Duration]()
 
 // The default [Decoder] instance.
 var dec Decoder
@@ -88,32 +220,253 @@ var dec Decoder
 // Decoder can be used to customize unmarshalling.
 // A decoder is threadsafe once created.
 type Decoder struct {
-	// The struct tag that is used
-	structTag string
+	// The struct tags that are used, in order of priority
+	structTags []string
 
 	// Cache for setters, indexed by [reflect.Type]
 	setterCache sync.Map
 
+	// Number of setters in the cache, and the maximum, see WithCacheLimit.
+	cacheSize  atomic.Int64
+	cacheLimit int
+
 	// Require values for struct fields. Set to true to fail with ErrNoValue
 	// if a call to [unravel.Source.Get] returns [ErrNoValue].
 	requireValues bool
+
+	// Checksum algorithms registered using WithChecksum, indexed by name.
+	checksums map[string]Checksum
+
+	// Setters registered using RegisterSetter, indexed by [reflect.Type].
+	customSetters map[reflect.Type]setter
+
+	// Constructors registered using RegisterConstructor, indexed by [reflect.Type].
+	constructors map[reflect.Type]func(string, reflect.Value) error
+
+	// Concrete types registered using RegisterImplementation, indexed by interface type.
+	implementations map[reflect.Type]reflect.Type
+
+	// Variants registered using RegisterVariant, indexed by interface type and name.
+	variants map[reflect.Type]map[string]reflect.Type
+
+	// Hooks registered using RegisterKindHook, in order of registration.
+	kindHooks map[reflect.Kind][]KindHook
+
+	// Hooks registered using WithHooks, in order of registration.
+	decodeHooks []DecodeHook
+
+	// Layouts used to parse time.Time values. Defaults to RFC 3339 if empty.
+	timeLayouts []string
+
+	// Location of parsed time.Time values without zone information. Defaults to UTC if nil.
+	location *time.Location
+
+	// Validates the decoded value, see WithValidation.
+	validate func(value any) error
+
+	// Decode into existing pointers instead of replacing them, see MergeInto.
+	merge bool
+
+	// How existing slices are treated, see WithSlicePolicy.
+	slicePolicy SlicePolicy
+
+	// How existing maps are treated, see WithMapPolicy.
+	mapPolicy MapPolicy
+
+	// Decode a single value into a slice with one element, see SingleValueAsSlice.
+	singleValueAsSlice bool
+
+	// Maps the names of fields without a name in a struct tag, see WithNameMapper.
+	nameMapper func(string) string
+
+	// Fail on fields hidden by a naming conflict, see StrictFields.
+	strictFields bool
+
+	// Transforms registered using RegisterTransform, by name.
+	transforms map[string]Transform
+
+	// Normalizes keys before comparing them, see WithKeyNormalizer.
+	keyNormalizer func(string) string
+
+	// Handle empty strings like missing values, see EmptyStringAsNoValue.
+	emptyStringAsNoValue bool
+
+	// Trim whitespace from strings, see TrimSpace.
+	trimSpace bool
+
+	// Format of numbers given as strings, see WithNumberFormat.
+	numberFormat *NumberFormat
+
+	// Continue decoding after a value failed, see CollectErrors.
+	collectErrors bool
+
+	// Receives non-fatal events, see WithWarnings.
+	warn func(Warning)
+
+	// Skip fields with values of an unsupported type, see BestEffort.
+	bestEffort bool
+
+	// Skip fields of an unsupported type, see SkipUnsupportedFields.
+	skipUnsupportedFields bool
+
+	// How often recursive types may be nested, see WithMaxDepth.
+	maxDepth int
+
+	// Limits on the size of decoded values, see WithLimits.
+	limits Limits
+
+	// Number of goroutines decoding the elements of a slice, see WithParallelSlices.
+	parallelSlices int
+
+	// Receives events of the decoder, see WithMetrics.
+	metrics Metrics
+
+	// Number of values allocated at once for pointers, and the arenas
+	// allocating them, see WithArena.
+	arenaChunkSize int
+	arenas         arenaSet
+
+	// The nesting level of recursive types this decoder decodes at, and the
+	// decoder for the next level. Only used if maxDepth is set.
+	depth  int
+	deeper atomic.Pointer[Decoder]
 }
 
+// DecodeHook is called before a value of the target type is decoded. It can inspect the
+// [Source] and return a replacement [Source] that is then passed to the next hook and
+// finally to the setter of the target type. A hook that does not apply to a value must
+// return the source unchanged.
+//
+// To replace the value with an existing Go value, return it wrapped using [SourceOf].
+//
+// Example:
+//
+//	// accept durations like "30s" for time.Duration
+//	func durationHook(source unravel.Source, target reflect.Type) (unravel.Source, error) {
+//	    if target != reflect.TypeFor[time.Duration]() {
+//	        return source, nil
+//	    }
+//
+//	    text, err := source.String()
+//	    if err != nil {
+//	        // not a string, keep the source as is
+//	        return source, nil
+//	    }
+//
+//	    duration, err := time.ParseDuration(text)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//
+//	    return unravel.SourceOf(duration), nil
+//	}
+type DecodeHook func(source Source, target reflect.Type) (Source, error)
+
+// KindHook customizes the decoding of all values of a [reflect.Kind]. The hook receives
+// the [Source] and the value to set, as well as the setter that would be used without
+// the hook. It can transform the source before calling next, handle errors returned by
+// next, or decode the value itself without calling next at all.
+type KindHook func(source Source, target reflect.Value, next func(Source, reflect.Value) error) error
+
 func NewDecoder() *Decoder {
 	return &Decoder{
-		structTag: "json",
+		structTags: []string{"json"},
 	}
 }
 
 func (d *Decoder) WithTag(structTag string) *Decoder {
-	if d.structTag == structTag {
+	return d.WithTags(structTag)
+}
+
+// WithTags returns a [Decoder] that reads the names of fields from the given struct tags.
+// For each field, the first tag that is present and gives a name wins, e.g. using
+// WithTags("env", "json") the name in the `env` tag takes precedence over the `json` tag.
+func (d *Decoder) WithTags(structTags ...string) *Decoder {
+	if slices.Equal(d.structTags, structTags) {
 		return d
 	}
 
-	return &Decoder{
-		structTag:     structTag,
-		requireValues: d.requireValues,
+	derived := d.cloneWithSetters()
+	derived.structTags = slices.Clone(structTags)
+	return derived
+}
+
+// WithNameMapper returns a [Decoder] that maps the names of fields before looking them
+// up in the [Source], e.g. from CamelCase to snake_case using [SnakeCase]. Only fields
+// without a name in a struct tag are mapped.
+func (d *Decoder) WithNameMapper(mapper func(string) string) *Decoder {
+	derived := d.cloneWithSetters()
+	derived.nameMapper = mapper
+	return derived
+}
+
+// WithKeyNormalizer returns a [Decoder] that compares the names of fields with the keys of
+// the [Source] after normalizing both, e.g. using [NormalizeKey] the field DBHost matches
+// the keys "db_host", "DB-HOST" and "DBHOST". This lets a single struct definition decode
+// environment variables, flags and headers. Keys matching exactly are always preferred.
+func (d *Decoder) WithKeyNormalizer(normalize func(string) string) *Decoder {
+	derived := d.cloneWithSetters()
+	derived.keyNormalizer = normalize
+	return derived
+}
+
+// structTagsOrDefault returns the struct tags of the decoder, defaulting to `json`.
+func (d *Decoder) structTagsOrDefault() []string {
+	if len(d.structTags) == 0 {
+		return []string{"json"}
+	}
+
+	return d.structTags
+}
+
+// StrictFields returns a [Decoder] that fails with [ErrAmbiguousField] if the fields of
+// a struct have a naming conflict, e.g. two embedded structs both having a field "id".
+// By default, like in encoding/json, all of the conflicting fields are silently ignored.
+func (d *Decoder) StrictFields() *Decoder {
+	if d.strictFields {
+		return d
+	}
+
+	derived := d.cloneWithSetters()
+	derived.strictFields = true
+	return derived
+}
+
+// fieldsOf returns the fields of a struct type to decode. If the decoder has
+// strict fields enabled, naming conflicts result in an error.
+// The names of fields without an explicit name are mapped using the name mapper.
+func (d *Decoder) fieldsOf(ty reflect.Type) ([]field, error) {
+	resolved := resolveFields(ty, d.structTagsOrDefault())
+
+	if d.warn != nil {
+		for _, conflict := range resolved.Conflicts {
+			d.warn(Warning{Kind: WarningFieldConflict, Type: ty, Field: conflict.Name, Message: conflict.String()})
+		}
+	}
+
+	if d.strictFields && len(resolved.Conflicts) > 0 {
+		var descriptions []string
+		for _, conflict := range resolved.Conflicts {
+			descriptions = append(descriptions, conflict.String())
+		}
+
+		return nil, fmt.Errorf("%w in %q: %s", ErrAmbiguousField, ty, strings.Join(descriptions, "; "))
+	}
+
+	return d.mapNames(resolved.Fields), nil
+}
+
+// mapNames applies the name mapper to all fields without an explicit name.
+func (d *Decoder) mapNames(fields []field) []field {
+	if d.nameMapper != nil {
+		for idx, field := range fields {
+			if !field.Explicit {
+				fields[idx].Name = d.nameMapper(field.Name)
+			}
+		}
 	}
+
+	return fields
 }
 
 func (d *Decoder) RequireValues() *Decoder {
@@ -121,177 +474,1190 @@ func (d *Decoder) RequireValues() *Decoder {
 		return d
 	}
 
-	return &Decoder{
-		structTag:     d.structTag,
-		requireValues: true,
+	derived := d.cloneWithSetters()
+	derived.requireValues = true
+	return derived
+}
+
+// EmptyStringAsNoValue returns a [Decoder] that handles fields with an empty string value
+// like fields without a value: the field is left untouched, its default value is applied
+// and a required field fails with [ErrNoValue]. This is useful for sources that can not
+// distinguish between absent and empty values, e.g. environment variables and form fields.
+//
+// Only values known to be strings are checked: values of a [StringSource] and of sources
+// implementing [KindSource] that report [KindString].
+func (d *Decoder) EmptyStringAsNoValue() *Decoder {
+	if d.emptyStringAsNoValue {
+		return d
 	}
+
+	derived := d.cloneWithSetters()
+	derived.emptyStringAsNoValue = true
+	return derived
 }
 
-func (d *Decoder) Unmarshal(source Source, target any) error {
-	targetValue := reflect.ValueOf(target).Elem()
+// MergeInto returns a [Decoder] that merges the values of a [Source] into an existing
+// value, e.g. to decode layered configuration: first the defaults, then a file and
+// finally the environment. Fields without a value in the [Source] always keep their
+// current value. In addition, a merging [Decoder] decodes into the values of non-nil
+// pointers in place instead of replacing them with new values, and merges into existing
+// maps using [MapMerge].
+//
+// Example:
+//
+//	config := Config{Port: 8080, TLS: &TLSConfig{MinVersion: "1.2"}}
+//
+//	dec := unravel.NewDecoder().MergeInto()
+//	if err := dec.Unmarshal(fileSource, &config); err != nil {
+//	    return err
+//	}
+//
+//	if err := dec.Unmarshal(envSource, &config); err != nil {
+//	    return err
+//	}
+func (d *Decoder) MergeInto() *Decoder {
+	if d.merge && d.mapPolicy == MapMerge {
+		return d
+	}
 
-	// build the setter for the targets type
-	setter, err := d.setterOf(typeSet{}, targetValue.Type())
-	if err != nil {
-		return err
+	derived := d.clone()
+	derived.merge = true
+	derived.mapPolicy = MapMerge
+	return derived
+}
+
+// WithChecksum returns a [Decoder] that knows the checksum algorithm with the given name.
+// Fields tagged with `bin:"checksum=name"` are verified using this algorithm.
+// See [Checksum] for the algorithms that are available by default.
+func (d *Decoder) WithChecksum(name string, checksum Checksum) *Decoder {
+	derived := d.clone()
+	derived.checksums = maps.Clone(d.checksums)
+	if derived.checksums == nil {
+		derived.checksums = map[string]Checksum{}
 	}
 
-	return setter(source, targetValue)
+	derived.checksums[name] = checksum
+	return derived
 }
 
-func (d *Decoder) setterOf(inConstruction typeSet, ty reflect.Type) (setter, error) {
-	if cached, ok := d.setterCache.Load(ty); ok {
-		return cached.(setter), nil
+// RegisterSetter returns a [Decoder] that decodes values of the given type using fn,
+// instead of the default decoding for the type. This allows to customize decoding of
+// types that can not implement [Unmarshaler], e.g. types of other packages.
+//
+// The function receives the [Source] of the value and the value to set, which is
+// addressable and of the registered type.
+func (d *Decoder) RegisterSetter(ty reflect.Type, fn func(Source, reflect.Value) error) *Decoder {
+	derived := d.clone()
+	derived.customSetters = maps.Clone(d.customSetters)
+	if derived.customSetters == nil {
+		derived.customSetters = map[reflect.Type]setter{}
 	}
 
-	if _, ok := inConstruction[ty]; ok {
-		// detected a cycle. return a setter that does a cache lookup when executed.
-		// we assume that the actual setter will be in the cache once this setter is executed.
-		lazySetter := func(source Source, target reflect.Value) error {
-			cached, _ := d.setterCache.Load(ty)
-			return cached.(setter)(source, target)
+	derived.customSetters[ty] = func(ctx context.Context, source Source, target reflect.Value) error {
+		return fn(source, target)
+	}
+
+	return derived
+}
+
+// RegisterTypeFunc works like [Decoder.RegisterSetter] but uses a typed function
+// to decode values of type `T`.
+//
+// The function acts as a factory for `T`: it is used wherever `T` appears in the target,
+// e.g. as a field, behind a pointer, as element of a slice or array, as key or value of a
+// map or within an [Optional]. This allows decoding types that can not be built by setting
+// fields of their zero value, e.g. types with unexported invariants or handles that must
+// be opened using a constructor.
+//
+// Example:
+//
+//	dec := unravel.RegisterTypeFunc(unravel.NewDecoder(), func(source unravel.Source) (time.Time, error) {
+//	    seconds, err := source.Int()
+//	    return time.Unix(seconds, 0), err
+//	})
+func RegisterTypeFunc[T any](dec *Decoder, fn func(Source) (T, error)) *Decoder {
+	setter := func(source Source, target reflect.Value) error {
+		value, err := fn(source)
+		if err != nil {
+			return err
 		}
 
-		return lazySetter, nil
+		target.Set(reflect.ValueOf(&value).Elem())
+		return nil
 	}
 
-	inConstruction[ty] = struct{}{}
+	return dec.RegisterSetter(reflect.TypeFor[T](), setter)
+}
 
-	setter, err := d.makeSetterOf(inConstruction, ty)
+// RegisterImplementation returns a [Decoder] that decodes values of the interface type iface
+// into a new value of the concrete type impl, which is then assigned to the interface.
+// impl must implement iface, it is usually a pointer to a struct. An explicit null value,
+// see [NullableSource], sets the interface to nil.
+//
+// RegisterImplementation panics if iface is not an interface or impl does not implement it.
+func (d *Decoder) RegisterImplementation(iface, impl reflect.Type) *Decoder {
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("type %q is not an interface", iface))
+	}
+
+	if !impl.Implements(iface) {
+		panic(fmt.Sprintf("type %q does not implement %q", impl, iface))
+	}
+
+	derived := d.clone()
+	derived.implementations = maps.Clone(d.implementations)
+	if derived.implementations == nil {
+		derived.implementations = map[reflect.Type]reflect.Type{}
+	}
+
+	derived.implementations[iface] = impl
+	return derived
+}
+
+// RegisterInterfaceImpl works like [Decoder.RegisterImplementation], decoding values of
+// the interface type `I` into the concrete type `T`.
+//
+// Example:
+//
+//	type Config struct {
+//	    Storage StorageBackend `json:"storage"`
+//	}
+//
+//	dec := unravel.RegisterInterfaceImpl[StorageBackend, *S3Storage](unravel.NewDecoder())
+func RegisterInterfaceImpl[I, T any](dec *Decoder) *Decoder {
+	return dec.RegisterImplementation(reflect.TypeFor[I](), reflect.TypeFor[T]())
+}
+
+// makeSetImplementation returns a setter for an interface type that decodes into
+// a new value of the given concrete type.
+func (d *Decoder) makeSetImplementation(inConstruction typeSet, impl reflect.Type) (setter, error) {
+	implSetter, err := d.setterOf(inConstruction, impl)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("setter for implementation %q: %w", impl, err)
+	}
+
+	// the value is copied into the interface by Set
+	scratch := newScratchValues(impl)
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		if isNull(source) {
+			target.SetZero()
+			return nil
+		}
+
+		value := scratch.Get()
+		defer scratch.Put(value)
+
+		if err := implSetter(ctx, source, value); err != nil {
+			return err
+		}
+
+		target.Set(value)
+		return nil
+	}
+
+	return setter, nil
+}
+
+// RegisterKindHook returns a [Decoder] that decodes all values of the given [reflect.Kind]
+// through the hook. If multiple hooks are registered for the same kind, the hook registered
+// last is called first. Setters registered for a specific type using [Decoder.RegisterSetter]
+// are not affected by kind hooks.
+//
+// Example:
+//
+//	// trim all strings
+//	dec := unravel.NewDecoder().RegisterKindHook(reflect.String, func(source unravel.Source, target reflect.Value, next func(unravel.Source, reflect.Value) error) error {
+//	    if err := next(source, target); err != nil {
+//	        return err
+//	    }
+//
+//	    target.SetString(strings.TrimSpace(target.String()))
+//	    return nil
+//	})
+func (d *Decoder) RegisterKindHook(kind reflect.Kind, hook KindHook) *Decoder {
+	derived := d.clone()
+	derived.kindHooks = maps.Clone(d.kindHooks)
+	if derived.kindHooks == nil {
+		derived.kindHooks = map[reflect.Kind][]KindHook{}
+	}
+
+	derived.kindHooks[kind] = append(slices.Clone(d.kindHooks[kind]), hook)
+	return derived
+}
+
+// withKindHooks wraps the setter with the hooks registered for the given kind.
+func (d *Decoder) withKindHooks(kind reflect.Kind, setter setter) setter {
+	for _, hook := range d.kindHooks[kind] {
+		next := setter
+		setter = func(ctx context.Context, source Source, target reflect.Value) error {
+			return hook(source, target, func(source Source, target reflect.Value) error {
+				return next(ctx, source, target)
+			})
+		}
+	}
+
+	return setter
+}
+
+// WithHooks returns a [Decoder] that calls the given hooks before decoding each value.
+// Hooks are called in order, each hook receiving the [Source] returned by the previous one.
+// Hooks added by previous calls to WithHooks are called first.
+func (d *Decoder) WithHooks(hooks ...DecodeHook) *Decoder {
+	derived := d.clone()
+	derived.decodeHooks = slices.Concat(d.decodeHooks, hooks)
+	return derived
+}
+
+// withDecodeHooks wraps the setter to run the decode hooks before setting the value.
+func (d *Decoder) withDecodeHooks(ty reflect.Type, setter setter) setter {
+	if len(d.decodeHooks) == 0 {
+		return setter
+	}
+
+	hooks := d.decodeHooks
+
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		for _, hook := range hooks {
+			replacement, err := hook(source, ty)
+			if err != nil {
+				return fmt.Errorf("decode hook for %q: %w", ty, err)
+			}
+
+			source = replacement
+		}
+
+		return setter(ctx, source, target)
+	}
+}
+
+// WithTimeLayouts returns a [Decoder] that parses [time.Time] values using the given
+// layouts, see [time.Parse]. The layouts are tried in order, the first layout that
+// parses the value wins. By default, only [time.RFC3339] is used.
+func (d *Decoder) WithTimeLayouts(layouts ...string) *Decoder {
+	derived := d.clone()
+	derived.timeLayouts = slices.Clone(layouts)
+	return derived
+}
+
+// WithLocation returns a [Decoder] that interprets [time.Time] values without zone
+// information in the given location instead of UTC. Values that include a zone
+// offset, like [time.RFC3339] timestamps, are not affected.
+func (d *Decoder) WithLocation(loc *time.Location) *Decoder {
+	derived := d.clone()
+	derived.location = loc
+	return derived
+}
+
+// WithValidation returns a [Decoder] that calls validate with the target passed to
+// [Decoder.Unmarshal] after the value was decoded successfully. This allows to plug in
+// an external validation library, e.g. the `Struct` method of a validator from
+// github.com/go-playground/validator that evaluates `validate` struct tags.
+// In contrast to [Validator], validate is called once for the complete value.
+func (d *Decoder) WithValidation(validate func(value any) error) *Decoder {
+	derived := d.clone()
+	derived.validate = validate
+	return derived
+}
+
+// clone returns a copy of this decoder with an empty setter cache.
+func (d *Decoder) clone() *Decoder {
+	return &Decoder{
+		structTags:      d.structTags,
+		requireValues:   d.requireValues,
+		checksums:       d.checksums,
+		customSetters:   d.customSetters,
+		constructors:    d.constructors,
+		implementations: d.implementations,
+		variants:        d.variants,
+		kindHooks:       d.kindHooks,
+		decodeHooks:     d.decodeHooks,
+		timeLayouts:     d.timeLayouts,
+		location:        d.location,
+		validate:        d.validate,
+		merge:           d.merge,
+		slicePolicy:     d.slicePolicy,
+		mapPolicy:       d.mapPolicy,
+
+		singleValueAsSlice: d.singleValueAsSlice,
+		nameMapper:         d.nameMapper,
+		strictFields:       d.strictFields,
+		transforms:         d.transforms,
+		keyNormalizer:      d.keyNormalizer,
+
+		emptyStringAsNoValue:  d.emptyStringAsNoValue,
+		trimSpace:             d.trimSpace,
+		numberFormat:          d.numberFormat,
+		collectErrors:         d.collectErrors,
+		warn:                  d.warn,
+		bestEffort:            d.bestEffort,
+		skipUnsupportedFields: d.skipUnsupportedFields,
+		maxDepth:              d.maxDepth,
+		limits:                d.limits,
+		parallelSlices:        d.parallelSlices,
+		cacheLimit:            d.cacheLimit,
+		metrics:               d.metrics,
+		arenaChunkSize:        d.arenaChunkSize,
+	}
+}
+
+// cloneWithSetters works like clone, but keeps the cached setters of all types that
+// do not contain struct fields. Use it for options that only change how the fields
+// of structs are decoded, so that deriving a decoder does not discard its setters.
+// Setters of pointers allocate from the arenas of the decoder that built them, see
+// WithArena, so they are not kept if the decoder uses arenas.
+func (d *Decoder) cloneWithSetters() *Decoder {
+	derived := d.clone()
+
+	d.setterCache.Range(func(key, value any) bool {
+		ty := key.(reflect.Type)
+		if containsFields(ty, typeSet{}) {
+			return true
+		}
+
+		if d.arenaChunkSize > 1 && containsPointers(ty, typeSet{}) {
+			return true
+		}
+
+		derived.storeSetter(ty, value.(setter))
+
+		return true
+	})
+
+	return derived
+}
+
+// containsFields reports whether decoding a value of the type might decode the fields of
+// a struct. Interfaces might be decoded into a struct, see RegisterImplementation.
+func containsFields(ty reflect.Type, visited typeSet) bool {
+	if _, ok := visited[ty]; ok {
+		return false
+	}
+
+	visited[ty] = struct{}{}
+
+	switch ty.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return containsFields(ty.Elem(), visited)
+
+	case reflect.Map:
+		return containsFields(ty.Key(), visited) || containsFields(ty.Elem(), visited)
+
+	default:
+		return false
+	}
+}
+
+// containsPointers reports whether decoding a value of the type might allocate the
+// value of a pointer. Types containing struct fields are not considered.
+func containsPointers(ty reflect.Type, visited typeSet) bool {
+	if _, ok := visited[ty]; ok {
+		return false
 	}
 
-	d.setterCache.Store(ty, setter)
+	visited[ty] = struct{}{}
+
+	switch ty.Kind() {
+	case reflect.Pointer:
+		return true
+
+	case reflect.Slice, reflect.Array:
+		return containsPointers(ty.Elem(), visited)
+
+	case reflect.Map:
+		return containsPointers(ty.Key(), visited) || containsPointers(ty.Elem(), visited)
+
+	default:
+		return false
+	}
+}
+
+// Prepare builds the setters of the given types ahead of time, which otherwise happens
+// when a value of a type is first decoded. All types that can not be decoded, e.g. due to
+// an unsupported field type or an invalid struct tag, are reported in the returned error.
+// Call Prepare during startup to fail fast, instead of on the first decode of a broken type.
+func (d *Decoder) Prepare(types ...reflect.Type) error {
+	var errs []error
+
+	for _, ty := range types {
+		if _, err := d.setterOf(typeSet{}, ty); err != nil {
+			errs = append(errs, fmt.Errorf("prepare %q: %w", ty, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *Decoder) Unmarshal(source Source, target any) error {
+	return d.unmarshal(context.Background(), source, target)
+}
+
+// unmarshal implements Unmarshal and UnmarshalContext. The context is passed down to
+// the setters, which check it between fields and elements.
+func (d *Decoder) unmarshal(ctx context.Context, source Source, target any) error {
+	if ok, err := d.unmarshalDirect(ctx, source, target); ok {
+		if err != nil && d.metrics != nil {
+			d.metrics.DecodeFailed(reflect.TypeOf(target).Elem(), err)
+		}
+
+		return err
+	}
+
+	return d.unmarshalValue(ctx, source, reflect.ValueOf(target).Elem())
+}
+
+// UnmarshalValue works like [Decoder.Unmarshal] but decodes into the given [reflect.Value],
+// which must be settable, e.g. obtained using [reflect.Value.Elem] on a pointer or using
+// [reflect.New]. It allows code that already works with [reflect.Value] to decode without
+// converting the value to `any` and back. The function passed to [Decoder.WithValidation]
+// receives a pointer to the value.
+func (d *Decoder) UnmarshalValue(source Source, target reflect.Value) error {
+	return d.unmarshalValue(context.Background(), source, target)
+}
+
+func (d *Decoder) unmarshalValue(ctx context.Context, source Source, target reflect.Value) error {
+	if !target.IsValid() {
+		return errors.New("target is not valid")
+	}
+
+	if !target.CanSet() {
+		return fmt.Errorf("target of type %q is not settable", target.Type())
+	}
+
+	// build the setter for the targets type
+	setter, err := d.setterOf(typeSet{}, target.Type())
+	if err != nil {
+		return err
+	}
+
+	if d.arenaChunkSize > 1 {
+		// do not keep the values of this call alive, see WithArena
+		defer d.releaseArenas()
+	}
+
+	switch target.Kind() {
+	case reflect.Slice:
+		prepareSlice(d.slicePolicy, target)
+	case reflect.Map:
+		prepareMap(d.mapPolicy, target)
+	}
+
+	if err := setter(ctx, source, target); err != nil {
+		err = withPath(err, "$", target.Type())
+
+		if d.metrics != nil {
+			d.metrics.DecodeFailed(target.Type(), err)
+		}
+
+		return err
+	}
+
+	if d.validate != nil {
+		if err := d.validate(target.Addr().Interface()); err != nil {
+			err = fmt.Errorf("validate: %w", err)
+
+			if d.metrics != nil {
+				d.metrics.DecodeFailed(target.Type(), err)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Decoder) setterOf(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	if cached, ok := d.setterCache.Load(ty); ok {
+		if d.metrics != nil {
+			d.metrics.CacheHit(ty)
+		}
+
+		return cached.(setter), nil
+	}
+
+	if _, ok := inConstruction[ty]; ok && d.maxDepth > 0 {
+		// detected a cycle. decode the nested value one level deeper
+		return d.makeSetNested(ty), nil
+	}
+
+	if _, ok := inConstruction[ty]; ok {
+		// detected a cycle. return a setter that does a cache lookup when executed.
+		// we assume that the actual setter will be in the cache once this setter is executed.
+		lazySetter := func(ctx context.Context, source Source, target reflect.Value) error {
+			cached, ok := d.setterCache.Load(ty)
+			if !ok {
+				// the cache has been reset since, see ResetCache
+				setter, err := d.setterOf(typeSet{}, ty)
+				if err != nil {
+					return err
+				}
+
+				return setter(ctx, source, target)
+			}
+
+			return cached.(setter)(ctx, source, target)
+		}
+
+		return lazySetter, nil
+	}
+
+	inConstruction[ty] = struct{}{}
+
+	var buildStart time.Time
+	if d.metrics != nil {
+		d.metrics.CacheMiss(ty)
+		buildStart = time.Now()
+	}
+
+	setter, err := d.makeSetterOf(inConstruction, ty)
+	if err != nil {
+		// the type might be skipped and referenced again, see SkipUnsupportedFields.
+		delete(inConstruction, ty)
+		return nil, err
+	}
+
+	if _, custom := d.customSetters[ty]; !custom {
+		if d.numberFormat != nil && isNumberKind(ty.Kind()) {
+			setter = withNumberFormat(*d.numberFormat, setter)
+		}
+
+		if d.trimSpace && isScalarKind(ty.Kind()) {
+			setter = withTrimSpace(setter)
+		}
+
+		setter = d.withKindHooks(ty.Kind(), setter)
+	}
+
+	setter = d.withDecodeHooks(ty, setter)
+
+	d.storeSetter(ty, setter)
+
+	if d.metrics != nil {
+		d.metrics.SetterBuilt(ty, time.Since(buildStart))
+	}
+
+	return setter, nil
+}
+
+func (d *Decoder) makeSetterOf(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	if custom, ok := d.customSetters[ty]; ok {
+		return custom, nil
+	}
+
+	switch ty {
+	case tyTime:
+		return d.makeSetTime(), nil
+	case tyDuration:
+		return setDuration, nil
+	case tyBigInt:
+		return setBigInt, nil
+	case tyBigFloat:
+		return setBigFloat, nil
+	case tyBigRat:
+		return setBigRat, nil
+	case tyRaw:
+		return d.setRaw, nil
+	case tyNumber:
+		return setNumber, nil
+	case tySyncMap:
+		return d.setSyncMap, nil
+	case tyList:
+		return d.setList, nil
+	}
+
+	if isOptionalType(ty) {
+		return d.makeSetOptional(inConstruction, ty)
+	}
+
+	if isSQLNullType(ty) {
+		return d.makeSetSQLNull(inConstruction, ty)
+	}
+
+	if isOrderedMapType(ty) {
+		return d.makeSetOrderedMap(inConstruction, ty)
+	}
+
+	if reflect.PointerTo(ty).Implements(tyUnmarshaler) {
+		return setUnmarshaler, nil
+	}
+
+	if reflect.PointerTo(ty).Implements(tyElementAppender) {
+		return setElementAppender, nil
+	}
+
+	if reflect.PointerTo(ty).Implements(tyMapWriter) {
+		return setMapWriter, nil
+	}
+
+	if reflect.PointerTo(ty).Implements(tyTextUnmarshaler) {
+		return setTextUnmarshaler, nil
+	}
+
+	if reflect.PointerTo(ty).Implements(tyJSONUnmarshaler) {
+		return d.makeSetJSONUnmarshaler(inConstruction, ty)
+	}
+
+	if construct, ok := d.constructors[ty]; ok {
+		next, err := d.makeKindSetterOf(inConstruction, ty)
+		if err != nil {
+			return nil, err
+		}
+
+		return withConstructor(construct, next), nil
+	}
+
+	return d.makeKindSetterOf(inConstruction, ty)
+}
+
+// makeKindSetterOf returns the setter for a type based on its kind,
+// ignoring any interfaces implemented by the type.
+func (d *Decoder) makeKindSetterOf(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	switch ty.Kind() {
+	case reflect.Bool:
+		return setBool, nil
+
+	case reflect.Int:
+		switch unsafe.Sizeof(int(int8(0))) {
+		case 4:
+			return makeSetInt(BinarySource.Int32, math.MinInt, math.MaxInt), nil
+		case 8:
+			return makeSetInt(BinarySource.Int64, math.MinInt, math.MaxInt), nil
+		default:
+			panic("int must be 4 or 8 byte")
+		}
+
+	case reflect.Int8:
+		return makeSetInt(BinarySource.Int8, math.MinInt8, math.MaxInt8), nil
+
+	case reflect.Int16:
+		return makeSetInt(BinarySource.Int16, math.MinInt16, math.MaxInt16), nil
+
+	case reflect.Int32:
+		return makeSetInt(BinarySource.Int32, math.MinInt32, math.MaxInt32), nil
+
+	case reflect.Int64:
+		return makeSetInt(BinarySource.Int64, math.MinInt64, math.MaxInt64), nil
+
+	case reflect.Uint:
+		switch unsafe.Sizeof(uint(0)) {
+		case 4:
+			return makeSetUint(BinarySource.Uint32, math.MaxUint), nil
+		case 8:
+			return makeSetUint(BinarySource.Uint64, math.MaxUint), nil
+		default:
+			panic("uint must be 4 or 8 byte")
+		}
+
+	case reflect.Uint8:
+		return makeSetUint(BinarySource.Uint8, math.MaxUint8), nil
+
+	case reflect.Uint16:
+		return makeSetUint(BinarySource.Uint16, math.MaxUint16), nil
+
+	case reflect.Uint32:
+		return makeSetUint(BinarySource.Uint32, math.MaxUint32), nil
+
+	case reflect.Uint64:
+		return makeSetUint(BinarySource.Uint64, math.MaxUint64), nil
+
+	case reflect.Float32:
+		return makeSetFloat(BinarySource.Float32), nil
+
+	case reflect.Float64:
+		return makeSetFloat(BinarySource.Float64), nil
+
+	case reflect.String:
+		if d.limits.MaxStringLen > 0 {
+			return makeSetStringLimited(d.limits.MaxStringLen), nil
+		}
+
+		return setString, nil
+
+	case reflect.Pointer:
+		return d.makeSetPointer(inConstruction, ty)
+
+	case reflect.Struct:
+		return d.makeSetStruct(inConstruction, ty)
+
+	case reflect.Slice:
+		return d.makeSetSlice(inConstruction, ty)
+
+	case reflect.Array:
+		return d.makeSetArray(inConstruction, ty)
+
+	case reflect.Map:
+		return d.makeSetMap(inConstruction, ty)
+
+	case reflect.Interface:
+		if impl, ok := d.implementations[ty]; ok {
+			return d.makeSetImplementation(inConstruction, impl)
+		}
+
+		if ty.NumMethod() > 0 {
+			return nil, NotSupportedError{Type: ty}
+		}
+
+		return d.setAny, nil
+
+	default:
+		return nil, NotSupportedError{Type: ty}
+	}
+}
+
+func (d *Decoder) makeSetStruct(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	var setters []fieldSetter
+
+	fields, err := d.fieldsOf(ty)
+	if err != nil {
+		return nil, err
+	}
+
+	// looks up the source of each field
+	var lookups []func(context.Context, Source) (Source, error)
+
+	// fields that must have a value
+	var required []bool
+
+	// holds the previous value of each field, see BestEffort
+	var previousScratch []*scratchValues
+
+	// the keys consumed by fields, and the field collecting all other keys, if any
+	knownKeys := map[string]bool{}
+	remainIdx := -1
+
+	// the fields that are decoded, without fields skipped due to an unsupported type
+	var supported []field
+
+	for _, field := range fields {
+		de, err := d.fieldSetterOf(inConstruction, ty, field)
+		if err != nil {
+			if d.skipUnsupportedFields && errors.As(err, &NotSupportedError{}) {
+				if d.warn != nil {
+					d.warn(Warning{Kind: WarningUnsupported, Type: ty, Field: field.Name, Message: err.Error()})
+				}
+
+				continue
+			}
+
+			return nil, fieldSetterError(err, ty, field.Name)
+		}
+
+		idx := len(supported)
+		supported = append(supported, field)
+
+		setters = append(setters, de)
+
+		fieldOpts, err := parseFieldOptions(field.Tag.Get("unravel"))
+		if err != nil {
+			return nil, fmt.Errorf("setter for field %q: %w", field.Name, err)
+		}
+
+		lookup, err := d.fieldLookupOf(field, fieldOpts)
+		if err != nil {
+			return nil, fmt.Errorf("setter for field %q: %w", field.Name, err)
+		}
+
+		if fieldOpts.Remain {
+			if remainIdx >= 0 {
+				return nil, fmt.Errorf("field %q: only one field of %q may collect the remaining keys", field.Name, ty)
+			}
+
+			if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("field %q: remaining keys require a map with string keys: %w", field.Name, NotSupportedError{Type: field.Type})
+			}
+
+			remainIdx = idx
+		} else {
+			addKnownKeys(knownKeys, field, fieldOpts)
+		}
+
+		lookups = append(lookups, lookup)
+
+		// optional fields are only required if explicitly tagged
+		required = append(required, fieldOpts.Required || d.requireValues && !isOptionalType(field.Type))
+
+		if d.bestEffort {
+			previousScratch = append(previousScratch, newScratchValues(field.Type))
+		}
+	}
+
+	fields = supported
+
+	if remainIdx >= 0 && d.keyNormalizer != nil {
+		knownKeys = normalizeKnownKeys(knownKeys, d.keyNormalizer)
+	}
+
+	if remainIdx >= 0 {
+		lookups[remainIdx] = func(ctx context.Context, source Source) (Source, error) {
+			return remainSource{Source: source, ctx: ctx, known: knownKeys, normalize: d.keyNormalizer}, nil
+		}
+	}
+
+	checksum, err := d.checksumFieldOf(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	validate := reflect.PointerTo(ty).Implements(tyValidator)
+
+	// decodes a single field. The returned error does not yet include the fields path
+	setField := func(ctx context.Context, source Source, target reflect.Value, idx int, recorded []byte) error {
+		field := fields[idx]
+
+		fieldSource, err := lookups[idx](ctx, source)
+		switch {
+		case errors.Is(err, ErrNoValue):
+			if required[idx] {
+				return err
+			}
+
+			// It is okay to not get a value at all,
+			// in that case we just skip the field
+			if d.warn != nil {
+				d.warn(Warning{Kind: WarningNoValue, Type: ty, Field: field.Name, Message: "no value"})
+			}
+
+			return nil
+		case err != nil:
+			return fmt.Errorf("lookup child: %w", err)
+		}
+
+		fieldValue, err := fieldByIndexAlloc(target, field.Index)
+		if err != nil {
+			return err
+		}
+
+		// keep the previous value to restore it if the field is skipped
+		var previous reflect.Value
+		if d.bestEffort {
+			previous = previousScratch[idx].Get()
+			defer previousScratch[idx].Put(previous)
+
+			previous.Set(fieldValue)
+		}
+
+		err = setters[idx](ctx, fieldSource, target, fieldValue)
+
+		switch {
+		case errors.Is(err, ErrNoValue) && isNull(fieldSource):
+			// an explicit null value is handled like a missing value,
+			// unless the fields type can represent null itself.
+			if required[idx] {
+				return err
+			}
+
+			if d.warn != nil {
+				d.warn(Warning{Kind: WarningNoValue, Type: ty, Field: field.Name, Message: "null value"})
+			}
+
+			return nil
+
+		case errors.Is(err, ErrNotSupported) && d.bestEffort:
+			fieldValue.Set(previous)
+
+			if d.warn != nil {
+				d.warn(Warning{Kind: WarningSkipped, Type: ty, Field: field.Name, Message: err.Error()})
+			}
+
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		if checksum != nil && idx == checksum.FieldIdx {
+			if err := checksum.Verify(recorded, fieldValue); err != nil {
+				return fmt.Errorf("verify: %w", err)
+			}
+		}
+
+		if d.metrics != nil {
+			d.metrics.FieldDecoded(ty, field.Name)
+		}
+
+		return nil
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		var stopRecording func() []byte
+
+		if checksum != nil {
+			recordingSource, ok := source.(RecordingSource)
+			if !ok {
+				return fmt.Errorf("record bytes for checksum: %w", ErrNotSupported)
+			}
+
+			stopRecording = recordingSource.Record()
+			defer stopRecording()
+		}
+
+		var collected DecodeErrors
+
+		for idx, field := range fields {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var recorded []byte
+			if checksum != nil && idx == checksum.FieldIdx {
+				// the checksum covers all bytes up to the checksum field
+				recorded = stopRecording()
+			}
+
+			if err := setField(ctx, source, target, idx, recorded); err != nil {
+				addFieldContext(err, ty, field.Name)
+
+				err = withPath(err, keySegment(field.Name), field.Type)
+				if err := d.collectError(&collected, err); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(collected) > 0 {
+			// do not validate a partially decoded struct
+			return collected
+		}
+
+		if validate {
+			if err := target.Addr().Interface().(Validator).Validate(); err != nil {
+				return fmt.Errorf("validate %q: %w", target.Type(), err)
+			}
+		}
+
+		return nil
+	}
+
+	return setter, nil
+}
+
+// hasOwnPolicies reports whether the field needs a setter built with the slice or map
+// policy given in its struct tag. A map merges into the existing map only if its
+// setter was built by a decoder with the merge policy, while the setters of
+// [sync.Map], [container/list.List] and [OrderedMap] always read the policy from the
+// decoder.
+func (d *Decoder) hasOwnPolicies(ty reflect.Type, fieldOpts fieldOptions) bool {
+	mapPolicyDiffers := fieldOpts.MapPolicy != nil && *fieldOpts.MapPolicy != d.mapPolicy
+	slicePolicyDiffers := fieldOpts.SlicePolicy != nil && *fieldOpts.SlicePolicy != d.slicePolicy
+
+	// the policy also applies to a container behind a pointer
+	elemType := ty
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	switch {
+	case elemType == tySyncMap || isOrderedMapType(elemType):
+		return mapPolicyDiffers
+
+	case elemType == tyList:
+		return slicePolicyDiffers
+
+	case ty.Kind() == reflect.Map:
+		return mapPolicyDiffers && *fieldOpts.MapPolicy == MapMerge
+
+	default:
+		return false
+	}
+}
+
+// fieldSetterOf returns the setter for a struct field. Options given in the fields
+// struct tag may replace or extend the default setter of the fields type.
+func (d *Decoder) fieldSetterOf(inConstruction typeSet, structType reflect.Type, field field) (fieldSetter, error) {
+	binOpts, err := parseBinaryOptions(field.Tag.Get("bin"))
+	if err != nil {
+		return nil, err
+	}
+
+	fieldOpts, err := parseFieldOptions(field.Tag.Get("unravel"))
+	if err != nil {
+		return nil, err
+	}
+
+	var setter fieldSetter
+
+	switch {
+	case binOpts.CountOf != "":
+		// the number of elements depends on another field of the struct
+		setter, err = d.makeSetCountOf(inConstruction, structType, field.Type, binOpts.CountOf)
+		if err != nil {
+			return nil, err
+		}
+
+	case binOpts.Switch != "":
+		// the variant to decode depends on another field of the struct
+		setter, err = d.makeSetSwitch(inConstruction, structType, field.Type, binOpts.Switch)
+		if err != nil {
+			return nil, err
+		}
+
+	case field.Tag.Get("union") != "":
+		// the variant to decode is named by a key within the value
+		valueSetter, err := d.makeSetUnion(inConstruction, field.Type, field.Tag.Get("union"))
+		if err != nil {
+			return nil, err
+		}
 
-	return setter, nil
-}
+		setter = func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+			return valueSetter(ctx, source, fieldValue)
+		}
 
-func (d *Decoder) makeSetterOf(inConstruction typeSet, ty reflect.Type) (setter, error) {
-	if reflect.PointerTo(ty).Implements(tyTextUnmarshaler) {
-		return setTextUnmarshaler, nil
-	}
+	case field.Tag.Get("bytes") != "":
+		valueSetter, err := d.makeSetEncodedBytes(inConstruction, field.Type, field.Tag.Get("bytes"))
+		if err != nil {
+			return nil, err
+		}
 
-	switch ty.Kind() {
-	case reflect.Bool:
-		return setBool, nil
+		setter = func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+			return valueSetter(ctx, source, fieldValue)
+		}
 
-	case reflect.Int:
-		switch unsafe.Sizeof(int(int8(0))) {
-		case 4:
-			return makeSetInt(BinarySource.Int32, math.MinInt, math.MaxInt), nil
-		case 8:
-			return makeSetInt(BinarySource.Int64, math.MinInt, math.MaxInt), nil
-		default:
-			panic("int must be 4 or 8 byte")
+	case fieldOpts.Tuple:
+		valueSetter, err := d.makeSetTuple(inConstruction, field.Type)
+		if err != nil {
+			return nil, err
 		}
 
-	case reflect.Int8:
-		return makeSetInt(BinarySource.Int8, math.MinInt8, math.MaxInt8), nil
+		setter = func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+			return valueSetter(ctx, source, fieldValue)
+		}
 
-	case reflect.Int16:
-		return makeSetInt(BinarySource.Int16, math.MinInt16, math.MaxInt16), nil
+	case fieldOpts.Layout != "":
+		if field.Type != tyTime {
+			return nil, fmt.Errorf("layout requires time.Time, got %q", field.Type)
+		}
 
-	case reflect.Int32:
-		return makeSetInt(BinarySource.Int32, math.MinInt32, math.MaxInt32), nil
+		valueSetter := d.makeSetTimeLayouts([]string{fieldOpts.Layout})
 
-	case reflect.Int64:
-		return makeSetInt(BinarySource.Int64, math.MinInt64, math.MaxInt64), nil
+		setter = func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+			return valueSetter(ctx, source, fieldValue)
+		}
 
-	case reflect.Uint:
-		switch unsafe.Sizeof(uint(0)) {
-		case 4:
-			return makeSetUint(BinarySource.Uint32, math.MaxUint), nil
-		case 8:
-			return makeSetUint(BinarySource.Uint64, math.MaxUint), nil
-		default:
-			panic("uint must be 4 or 8 byte")
+	case fieldOpts.EpochUnit != 0:
+		valueSetter, err := makeSetEpoch(field.Type, fieldOpts.EpochUnit)
+		if err != nil {
+			return nil, err
 		}
 
-	case reflect.Uint8:
-		return makeSetUint(BinarySource.Uint8, math.MaxUint8), nil
+		setter = func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+			return valueSetter(ctx, source, fieldValue)
+		}
 
-	case reflect.Uint16:
-		return makeSetUint(BinarySource.Uint16, math.MaxUint16), nil
+	case d.hasOwnPolicies(field.Type, fieldOpts):
+		// the setter of the field reads the policy from the decoder. It is built by a
+		// decoder of its own, which builds the setters of all nested types with the
+		// policies of the field.
+		policyDecoder := d
+		if fieldOpts.MapPolicy != nil {
+			policyDecoder = policyDecoder.WithMapPolicy(*fieldOpts.MapPolicy)
+		}
 
-	case reflect.Uint32:
-		return makeSetUint(BinarySource.Uint32, math.MaxUint32), nil
+		if fieldOpts.SlicePolicy != nil {
+			policyDecoder = policyDecoder.WithSlicePolicy(*fieldOpts.SlicePolicy)
+		}
 
-	case reflect.Uint64:
-		return makeSetUint(BinarySource.Uint64, math.MaxUint64), nil
+		valueSetter, err := policyDecoder.valueSetterOf(typeSet{}, field.Type, binOpts)
+		if err != nil {
+			return nil, err
+		}
 
-	case reflect.Float32, reflect.Float64:
-		return setFloat, nil
+		setter = func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+			return valueSetter(ctx, source, fieldValue)
+		}
 
-	case reflect.String:
-		return setString, nil
+	default:
+		valueSetter, err := d.valueSetterOf(inConstruction, field.Type, binOpts)
+		if err != nil {
+			return nil, err
+		}
 
-	case reflect.Pointer:
-		return d.makeSetPointer(inConstruction, ty)
+		setter = func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+			return valueSetter(ctx, source, fieldValue)
+		}
+	}
 
-	case reflect.Struct:
-		return d.makeSetStruct(inConstruction, ty)
+	slicePolicy := d.slicePolicy
+	if fieldOpts.SlicePolicy != nil {
+		slicePolicy = *fieldOpts.SlicePolicy
+	}
 
-	case reflect.Slice:
-		return d.makeSetSlice(inConstruction, ty)
+	if field.Type.Kind() == reflect.Slice && slicePolicy != SliceAppend {
+		setter = withSlicePolicy(slicePolicy, setter)
+	}
 
-	case reflect.Array:
-		return d.makeSetArray(inConstruction, ty)
+	mapPolicy := d.mapPolicy
+	if fieldOpts.MapPolicy != nil {
+		mapPolicy = *fieldOpts.MapPolicy
+	}
 
-	case reflect.Map:
-		return d.makeSetMap(inConstruction, ty)
+	if field.Type.Kind() == reflect.Map && mapPolicy != MapMerge {
+		setter = withMapPolicy(mapPolicy, setter)
+	}
 
-	default:
-		return nil, NotSupportedError{Type: ty}
+	if hasStringOption(field, d.structTagsOrDefault()) {
+		setter = withStringOption(setter)
 	}
-}
 
-func (d *Decoder) makeSetStruct(inConstruction typeSet, ty reflect.Type) (setter, error) {
-	var setters []setter
+	if transformTag, ok := field.Tag.Lookup("transform"); ok {
+		transform, err := d.makeTransform(transformTag)
+		if err != nil {
+			return nil, err
+		}
 
-	structTag := d.structTag
-	if structTag == "" {
-		structTag = "json"
+		setter = withTransform(transform, setter)
 	}
 
-	fields := fieldsToSerialize(ty, structTag)
-
-	for _, field := range fields {
-		de, err := d.setterOf(inConstruction, field.Type)
+	if enumTag, ok := field.Tag.Lookup("enum"); ok {
+		check, err := makeEnumCheck(field.Type, enumTag)
 		if err != nil {
-			return nil, fmt.Errorf("setter for field %q: %w", field.Name, err)
+			return nil, err
 		}
 
-		setters = append(setters, de)
+		setter = withEnumCheck(check, setter)
 	}
 
-	setter := func(source Source, target reflect.Value) error {
-		for idx, field := range fields {
-			fieldSource, err := source.Get(field.Name)
-			switch {
-			case errors.Is(err, ErrNoValue):
-				if d.requireValues {
-					return fmt.Errorf("field %q: %w", field.Name, err)
-				}
-				// It is okay to not get a value at all,
-				// in that case we just skip the field
-				continue
-			case err != nil:
-				return fmt.Errorf("lookup child %q: %w", field.Name, err)
-			}
+	// skip is applied after seeking to an offset
+	if binOpts.Skip > 0 {
+		setter = withSkip(binOpts.Skip, setter)
+	}
 
-			fieldValue := target.FieldByIndex(field.Index)
-			if err := setters[idx](fieldSource, fieldValue); err != nil {
-				return fmt.Errorf("set field %q on %q: %w", field.Name, target.Type(), err)
-			}
+	if binOpts.Offset != "" {
+		seek, err := makeSeekOffset(structType, binOpts.Offset)
+		if err != nil {
+			return nil, err
 		}
 
-		return nil
+		setter = withSeek(seek, setter)
 	}
 
 	return setter, nil
 }
 
+// valueSetterOf returns the setter for a value of the given type, taking binary
+// decoding options into account.
+func (d *Decoder) valueSetterOf(inConstruction typeSet, ty reflect.Type, binOpts binaryOptions) (setter, error) {
+	switch {
+	case binOpts.Varint:
+		return makeSetVarint(ty)
+
+	case binOpts.LengthPrefix != "":
+		return d.makeSetLengthPrefixed(ty, binOpts.LengthPrefix)
+
+	case binOpts.StringLength > 0:
+		return d.makeSetFixedString(ty, binOpts.StringLength)
+
+	case binOpts.CString:
+		return d.makeSetCString(ty)
+
+	default:
+		return d.setterOf(inConstruction, ty)
+	}
+}
+
 func (d *Decoder) makeSetMap(inConstruction typeSet, ty reflect.Type) (setter, error) {
 	keySetter, err := d.setterOf(inConstruction, ty.Key())
 	if err != nil {
@@ -306,30 +1672,133 @@ func (d *Decoder) makeSetMap(inConstruction typeSet, ty reflect.Type) (setter, e
 	keyType := ty.Key()
 	valueType := ty.Elem()
 
-	setter := func(source Source, target reflect.Value) error {
-		keyValues, err := source.KeyValues()
-		if err != nil {
-			return fmt.Errorf("iterate key/value pairs: %w", err)
+	keyScratch := newScratchValues(keyType)
+	valueScratch := newScratchValues(valueType)
+
+	// decodes keys yielded by a StringKeyValuesSource, without the setter of the key type.
+	// nil if the key type needs its setter.
+	var setStringKey func(key string, keyTarget reflect.Value) error
+
+	switch {
+	case !d.hasPlainSetter(keyType):
+
+	case decodesAsString(keyType):
+		setStringKey = func(key string, keyTarget reflect.Value) error {
+			keyTarget.SetString(key)
+			return nil
+		}
+
+	case decodesAsText(keyType):
+		setStringKey = func(key string, keyTarget reflect.Value) error {
+			return keyTarget.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+		}
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		var err error
+
+		var stringKeyValues iter.Seq2[string, Source]
+
+		if stringSource, ok := source.(StringKeyValuesSource); ok && setStringKey != nil {
+			stringKeyValues, err = stringSource.StringKeyValues()
+			if err != nil && !errors.Is(err, ErrNotSupported) {
+				return typeErrorOf(source, target.Type(), fmt.Errorf("iterate key/value pairs: %w", err))
+			}
+		}
+
+		var keyValues iter.Seq2[Source, Source]
+
+		if stringKeyValues == nil {
+			keyValues, err = keyValuesContext(ctx, source)
+			if err != nil {
+				return typeErrorOf(source, target.Type(), fmt.Errorf("iterate key/value pairs: %w", err))
+			}
+		}
+
+		// add the entries to an existing map, see MapPolicy
+		mapTarget := target
+		if mapTarget.IsNil() || d.mapPolicy != MapMerge {
+			mapTarget = reflect.MakeMap(ty)
 		}
 
-		mapTarget := reflect.MakeMap(ty)
+		var collected DecodeErrors
 
-		for keySource, valueSource := range keyValues {
-			keyTarget := reflect.New(keyType).Elem()
-			if err := keySetter(keySource, keyTarget); err != nil {
-				return fmt.Errorf("set key: %w", err)
+		var entryCount int
+
+		// the key and value of each entry are decoded into the same values,
+		// which are copied into the map by SetMapIndex
+		keyTarget := keyScratch.Get()
+		defer keyScratch.Put(keyTarget)
+
+		valueTarget := valueScratch.Get()
+		defer valueScratch.Put(valueTarget)
+
+		// decodes the value of an entry, once its key has been decoded into keyTarget
+		setEntry := func(valueSource Source) error {
+			entryCount++
+			if d.limits.MaxMapEntries > 0 && entryCount > d.limits.MaxMapEntries {
+				return fmt.Errorf("%w: more than %d map entries", ErrLimitExceeded, d.limits.MaxMapEntries)
+			}
+
+			valueTarget.SetZero()
+
+			// map values are not addressable, decode into a copy of an existing value
+			if existing := mapTarget.MapIndex(keyTarget); existing.IsValid() {
+				valueTarget.Set(existing)
 			}
 
-			valueTarget := reflect.New(valueType).Elem()
-			if err := valueSetter(valueSource, valueTarget); err != nil {
-				return fmt.Errorf("set key: %w", err)
+			err := valueSetter(ctx, valueSource, valueTarget)
+			switch {
+			case errors.Is(err, ErrNoValue) && isNull(valueSource):
+				// skip explicit null values that the value type can not represent
+				return nil
+
+			case err != nil:
+				return d.collectError(&collected, withPath(err, mapKeySegment(keyTarget), valueType))
 			}
 
 			mapTarget.SetMapIndex(keyTarget, valueTarget)
+			return nil
+		}
+
+		if stringKeyValues != nil {
+			for key, valueSource := range stringKeyValues {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				keyTarget.SetZero()
+				if err := setStringKey(key, keyTarget); err != nil {
+					return withPath(fmt.Errorf("set key: %w", err), keySegment(key), keyType)
+				}
+
+				if err := setEntry(valueSource); err != nil {
+					return err
+				}
+			}
+		} else {
+			for keySource, valueSource := range keyValues {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				keyTarget.SetZero()
+				if err := keySetter(ctx, keySource, keyTarget); err != nil {
+					return withPath(fmt.Errorf("set key: %w", err), keySegmentOf(keySource), keyType)
+				}
+
+				if err := setEntry(valueSource); err != nil {
+					return err
+				}
+			}
 		}
 
 		target.Set(mapTarget)
 
+		if len(collected) > 0 {
+			return collected
+		}
+
 		return nil
 	}
 
@@ -342,30 +1811,91 @@ func (d *Decoder) makeSetSlice(inConstruction typeSet, ty reflect.Type) (setter,
 		return nil, fmt.Errorf("setter for element type %q: %w", ty, err)
 	}
 
-	// a empty element
-	placeholderValue := reflect.New(ty.Elem()).Elem()
+	setter := d.makeSetSliceOf(ty, elementSetter)
 
-	setter := func(source Source, target reflect.Value) error {
-		sourceIter, err := source.Iter()
+	if d.singleValueAsSlice {
+		setter = withSingleValueAsSlice(setter)
+	}
+
+	if ty.Elem() == tyByte {
+		setter = withBytes(ty, setter)
+	}
+
+	return setter, nil
+}
+
+// makeSetSliceOf returns a setter for a slice type that decodes
+// each element using the given element setter.
+func (d *Decoder) makeSetSliceOf(ty reflect.Type, elementSetter setter) setter {
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		if d.parallelSlices <= 1 {
+			if indexSource, length, ok := indexedSourceOf(source); ok {
+				return d.setSliceIndexed(ctx, ty, elementSetter, indexSource, length, target)
+			}
+		}
+
+		sourceIter, err := iterContext(ctx, source)
 		if err != nil {
-			return fmt.Errorf("as iter: %w", err)
+			return typeErrorOf(source, target.Type(), fmt.Errorf("as iter: %w", err))
+		}
+
+		if d.parallelSlices > 1 {
+			return d.setSliceParallel(ctx, ty, elementSetter, sourceIter, target)
+		}
+
+		var collected DecodeErrors
+
+		// the length of the slice before decoding, see SlicePolicy
+		initialLen := target.Len()
+
+		if lenSource, ok := source.(LenSource); ok {
+			if length, ok := lenSource.Len(); ok && length > 0 {
+				if d.limits.MaxSliceLen > 0 {
+					// do not trust the length of an untrusted source
+					length = min(length, d.limits.MaxSliceLen)
+				}
+
+				if target.Cap()-initialLen < length {
+					preallocated := reflect.MakeSlice(target.Type(), initialLen, initialLen+length)
+					reflect.Copy(preallocated, target)
+					target.Set(preallocated)
+				}
+			}
 		}
 
 		for elementSource := range sourceIter {
-			// add an empty element to grow the list
-			target.Set(reflect.Append(target, placeholderValue))
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if d.limits.MaxSliceLen > 0 && target.Len()-initialLen >= d.limits.MaxSliceLen {
+				return fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, d.limits.MaxSliceLen)
+			}
+
+			// add an empty element to grow the list. Unlike reflect.Append,
+			// this does not allocate unless the capacity is exhausted
+			idx := target.Len()
+			target.Grow(1)
+			target.SetLen(idx + 1)
 
-			idx := target.Len() - 1
 			elementValue := target.Index(idx)
-			if err := elementSetter(elementSource, elementValue); err != nil {
-				return fmt.Errorf("set element idx=%d: %w", idx, err)
+			elementValue.SetZero()
+
+			if err := elementSetter(ctx, elementSource, elementValue); err != nil {
+				if err := d.collectError(&collected, withPath(err, indexSegment(idx), ty.Elem())); err != nil {
+					return err
+				}
 			}
 		}
 
+		if len(collected) > 0 {
+			return collected
+		}
+
 		return nil
 	}
 
-	return setter, nil
+	return setter
 }
 
 func (d *Decoder) makeSetArray(inConstruction typeSet, ty reflect.Type) (setter, error) {
@@ -377,30 +1907,59 @@ func (d *Decoder) makeSetArray(inConstruction typeSet, ty reflect.Type) (setter,
 	// number of elements in the array
 	elementCount := ty.Len()
 
-	setter := func(source Source, target reflect.Value) error {
-		sourceIter, err := source.Iter()
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		if indexSource, length, ok := indexedSourceOf(source); ok {
+			return d.setArrayIndexed(ctx, ty, elementSetter, indexSource, length, target)
+		}
+
+		sourceIter, err := iterContext(ctx, source)
 		if err != nil {
-			return fmt.Errorf("as iter: %w", err)
+			return typeErrorOf(source, target.Type(), fmt.Errorf("as iter: %w", err))
 		}
 
-		next, stop := iter.Pull(sourceIter)
-		defer stop()
+		var collected DecodeErrors
+
+		// ranging over the elements avoids the overhead of iter.Pull. Elements after the
+		// last one are only looked at to report a warning.
+		var idx int
+
+		for elementSource := range sourceIter {
+			if idx == elementCount {
+				if d.warn != nil {
+					d.warn(Warning{Kind: WarningTruncated, Type: ty, Message: fmt.Sprintf("dropped elements after the first %d", elementCount)})
+				}
 
-		for idx := 0; idx < elementCount; idx++ {
-			elementSource, ok := next()
-			if !ok {
 				break
 			}
 
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			elementValue := target.Index(idx)
-			if err := elementSetter(elementSource, elementValue); err != nil {
-				return fmt.Errorf("set element idx=%d: %w", idx, err)
+			if err := elementSetter(ctx, elementSource, elementValue); err != nil {
+				if err := d.collectError(&collected, withPath(err, indexSegment(idx), ty.Elem())); err != nil {
+					return err
+				}
+			}
+
+			idx++
+			if idx == elementCount && d.warn == nil {
+				break
 			}
 		}
 
+		if len(collected) > 0 {
+			return collected
+		}
+
 		return nil
 	}
 
+	if ty.Elem() == tyByte {
+		setter = withBytes(ty, setter)
+	}
+
 	return setter, nil
 }
 
@@ -412,10 +1971,40 @@ func (d *Decoder) makeSetPointer(inConstruction typeSet, ty reflect.Type) (sette
 		return nil, err
 	}
 
-	setter := func(source Source, target reflect.Value) error {
+	merge := d.merge
+
+	// allocates values of the pointeeType in chunks, see WithArena
+	var pointeeArena *arena
+	if d.arenaChunkSize > 1 && pointeeType.Size() > 0 {
+		pointeeArena = d.newArena(pointeeType)
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		if isNull(source) {
+			// an explicit null resets the pointer
+			target.SetZero()
+			return nil
+		}
+
+		if merge && !target.IsNil() {
+			// decode into the existing value
+			return pointeeSetter(ctx, source, target.Elem())
+		}
+
 		// newValue is now a pointer to an instance of the pointeeType
-		newValue := reflect.New(pointeeType)
-		if err := pointeeSetter(source, newValue.Elem()); err != nil {
+		var newValue reflect.Value
+		if pointeeArena != nil {
+			newValue = pointeeArena.New()
+		} else {
+			newValue = reflect.New(pointeeType)
+		}
+
+		if err := pointeeSetter(ctx, source, newValue.Elem()); err != nil {
+			if pointeeArena != nil {
+				// the chunk keeps the value alive, release what it references
+				newValue.Elem().SetZero()
+			}
+
 			return err
 		}
 
@@ -428,10 +2017,16 @@ func (d *Decoder) makeSetPointer(inConstruction typeSet, ty reflect.Type) (sette
 	return setter, err
 }
 
-func setBool(source Source, target reflect.Value) error {
+// isNull reports whether the source holds an explicit null value, see [NullableSource].
+func isNull(source Source) bool {
+	nullable, ok := source.(NullableSource)
+	return ok && nullable.IsNull()
+}
+
+func setBool(ctx context.Context, source Source, target reflect.Value) error {
 	boolValue, err := source.Bool()
 	if err != nil {
-		return fmt.Errorf("get bool value: %w", err)
+		return typeErrorOf(source, target.Type(), fmt.Errorf("get bool value: %w", err))
 	}
 
 	target.SetBool(boolValue)
@@ -442,11 +2037,18 @@ func makeSetInt[T constraints.Integer](
 	parse func(BinarySource) (T, error),
 	minValue, maxValue int64,
 ) setter {
-	return func(source Source, target reflect.Value) error {
+	// resolved once, instead of formatting %T on each error
+	parsedType := reflect.TypeFor[T]().String()
+
+	return func(ctx context.Context, source Source, target reflect.Value) error {
 		if intSource, ok := source.(BinarySource); ok {
 			parsedValue, err := parse(intSource)
 			if err != nil {
-				return fmt.Errorf("get %T value: %w", parsedValue, err)
+				if overflowErr := overflowErrorOf(target.Type(), err); overflowErr != nil {
+					return overflowErr
+				}
+
+				return typeErrorOf(source, target.Type(), fmt.Errorf("get %s value: %w", parsedType, err))
 			}
 
 			target.SetInt(int64(parsedValue))
@@ -456,17 +2058,11 @@ func makeSetInt[T constraints.Integer](
 		// no int source, need to fallback to Source.Int
 		intValue, err := source.Int()
 		if err != nil {
-			return fmt.Errorf("get int value: %w", err)
+			return typeErrorOf(source, target.Type(), fmt.Errorf("get int value: %w", err))
 		}
 
-		var tZero T
-
-		if intValue < minValue {
-			return fmt.Errorf("invalid %T value %d: %w", tZero, intValue, strconv.ErrRange)
-		}
-
-		if intValue > maxValue {
-			return fmt.Errorf("invalid %T value: %d: %w", tZero, intValue, strconv.ErrRange)
+		if intValue < minValue || intValue > maxValue {
+			return &OverflowError{Value: intValue, TargetType: target.Type()}
 		}
 
 		target.SetInt(intValue)
@@ -478,11 +2074,17 @@ func makeSetUint[T constraints.Unsigned](
 	parse func(BinarySource) (T, error),
 	maxValue uint64,
 ) setter {
-	return func(source Source, target reflect.Value) error {
+	parsedType := reflect.TypeFor[T]().String()
+
+	return func(ctx context.Context, source Source, target reflect.Value) error {
 		if intSource, ok := source.(BinarySource); ok {
 			parsedValue, err := parse(intSource)
 			if err != nil {
-				return fmt.Errorf("get %T value: %w", parsedValue, err)
+				if overflowErr := overflowErrorOf(target.Type(), err); overflowErr != nil {
+					return overflowErr
+				}
+
+				return typeErrorOf(source, target.Type(), fmt.Errorf("get %s value: %w", parsedType, err))
 			}
 
 			target.SetUint(uint64(parsedValue))
@@ -492,13 +2094,11 @@ func makeSetUint[T constraints.Unsigned](
 		// no int source, need to fallback to Source.Int
 		intValue, err := source.Uint()
 		if err != nil {
-			return fmt.Errorf("get uint value: %w", err)
+			return typeErrorOf(source, target.Type(), fmt.Errorf("get uint value: %w", err))
 		}
 
-		var tZero T
-
 		if intValue > maxValue {
-			return fmt.Errorf("invalid %T value: %d: %w", tZero, intValue, strconv.ErrRange)
+			return &OverflowError{Value: intValue, TargetType: target.Type()}
 		}
 
 		target.SetUint(intValue)
@@ -506,20 +2106,35 @@ func makeSetUint[T constraints.Unsigned](
 	}
 }
 
-func setFloat(source Source, target reflect.Value) error {
-	floatValue, err := source.Float()
-	if err != nil {
-		return fmt.Errorf("get float value: %w", err)
-	}
+func makeSetFloat[T constraints.Float](parse func(BinarySource) (T, error)) setter {
+	parsedType := reflect.TypeFor[T]().String()
 
-	target.SetFloat(floatValue)
-	return nil
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		if floatSource, ok := source.(BinarySource); ok {
+			parsedValue, err := parse(floatSource)
+			if err != nil {
+				return typeErrorOf(source, target.Type(), fmt.Errorf("get %s value: %w", parsedType, err))
+			}
+
+			target.SetFloat(float64(parsedValue))
+			return nil
+		}
+
+		// no float source, need to fallback to Source.Float
+		floatValue, err := source.Float()
+		if err != nil {
+			return typeErrorOf(source, target.Type(), fmt.Errorf("get float value: %w", err))
+		}
+
+		target.SetFloat(floatValue)
+		return nil
+	}
 }
 
-func setString(source Source, target reflect.Value) error {
+func setString(ctx context.Context, source Source, target reflect.Value) error {
 	stringSource, err := source.String()
 	if err != nil {
-		return fmt.Errorf("get string value: %w", err)
+		return typeErrorOf(source, target.Type(), fmt.Errorf("get string value: %w", err))
 	}
 
 	target.SetString(stringSource)
@@ -527,10 +2142,46 @@ func setString(source Source, target reflect.Value) error {
 	return nil
 }
 
-func setTextUnmarshaler(source Source, target reflect.Value) error {
+func setUnmarshaler(ctx context.Context, source Source, target reflect.Value) error {
+	m := target.Addr().Interface().(Unmarshaler)
+	return m.UnmarshalSource(source)
+}
+
+// makeSetJSONUnmarshaler returns a setter for a type implementing [json.Unmarshaler].
+// If the source provides its raw JSON representation via [RawSource], the value is
+// decoded using [json.Unmarshaler.UnmarshalJSON]. Otherwise, the type is decoded
+// based on its kind.
+func (d *Decoder) makeSetJSONUnmarshaler(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	// the fallback might not be available, e.g. for unsupported kinds
+	fallback, fallbackErr := d.makeKindSetterOf(inConstruction, ty)
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		if rawSource, ok := source.(RawSource); ok {
+			raw, err := rawSource.Raw()
+			switch {
+			case err == nil:
+				m := target.Addr().Interface().(json.Unmarshaler)
+				return m.UnmarshalJSON(raw)
+
+			case !errors.Is(err, ErrNotSupported):
+				return fmt.Errorf("get raw value: %w", err)
+			}
+		}
+
+		if fallbackErr != nil {
+			return fallbackErr
+		}
+
+		return fallback(ctx, source, target)
+	}
+
+	return setter, nil
+}
+
+func setTextUnmarshaler(ctx context.Context, source Source, target reflect.Value) error {
 	text, err := source.String()
 	if err != nil {
-		return fmt.Errorf("get string value: %w", err)
+		return typeErrorOf(source, target.Type(), fmt.Errorf("get string value: %w", err))
 	}
 
 	m := target.Addr().Interface().(encoding.TextUnmarshaler)
@@ -2,12 +2,18 @@ package unravel
 
 import (
 	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/require"
+	"io"
 	"iter"
 	"net"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestUnmarshalStruct(t *testing.T) {
@@ -92,6 +98,46 @@ func TestUnmarshalStructWithMap(t *testing.T) {
 	})
 }
 
+func TestUnmarshalMapOfAny(t *testing.T) {
+	type Struct struct {
+		Name   string            `json:"name"`
+		Extra  map[string]any    `json:"extra"`
+		Counts map[string]int    `json:"counts"`
+		Refs   map[string]*int   `json:"refs"`
+		Groups map[string][]any  `json:"groups"`
+		Labels map[int]string    `json:"labels"`
+		Meta   map[string]string `json:"meta"`
+	}
+
+	input := `{
+		"name": "Alex",
+		"extra": {"nested": {"a": [1, {"b": null}]}, "none": null},
+		"counts": {"a": 1, "b": null},
+		"refs": {"a": 1, "b": null},
+		"groups": {"x": [true, "y"]},
+		"labels": {"1": "one"},
+		"meta": {"k": "v"}
+	}`
+
+	value, err := UnmarshalNew[Struct](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	one := 1
+
+	require.Equal(t, value, Struct{
+		Name: "Alex",
+		Extra: map[string]any{
+			"nested": map[string]any{"a": []any{int64(1), map[string]any{"b": nil}}},
+			"none":   nil,
+		},
+		Counts: map[string]int{"a": 1},
+		Refs:   map[string]*int{"a": &one, "b": nil},
+		Groups: map[string][]any{"x": {true, "y"}},
+		Labels: map[int]string{1: "one"},
+		Meta:   map[string]string{"k": "v"},
+	})
+}
+
 func TestNaming_JsonTagExplicit(t *testing.T) {
 	type Struct struct {
 		A string
@@ -250,18 +296,22 @@ func TestNaming_EmbeddingWithExplicitNameWins(t *testing.T) {
 	require.Equal(t, stud, Struct{First: First{A: "FirstA"}})
 }
 
-func TestNaming_NoEmbeddingWithPointer(t *testing.T) {
+func TestNaming_EmbeddingWithPointer(t *testing.T) {
 	type First struct{ A string }
 
 	type Struct struct {
 		*First
 	}
 
-	source := dummySource{}
+	source := dummySource{
+		Values: map[string]any{
+			".A": "A",
+		},
+	}
 
 	stud, err := UnmarshalNew[Struct](source)
 	require.Equal(t, err, nil)
-	require.Equal(t, stud, Struct{})
+	require.Equal(t, stud, Struct{First: &First{A: "A"}})
 }
 
 func TestNaming_MultipleEmbeddedTypes(t *testing.T) {
@@ -299,13 +349,82 @@ func TestNaming_MultipleEmbeddedTypes(t *testing.T) {
 }
 
 func TestUnsupportedType(t *testing.T) {
-	type Struct struct{ A any }
+	type Struct struct{ A chan int }
 
 	_, err := UnmarshalNew[Struct](dummySource{})
 
 	var notSupportedError NotSupportedError
 	require.ErrorAs(t, err, &notSupportedError)
-	require.Equal(t, notSupportedError.Type, reflect.TypeFor[any]())
+	require.Equal(t, notSupportedError.Type, reflect.TypeFor[chan int]())
+	require.Equal(t, notSupportedError.Struct, reflect.TypeFor[Struct]())
+	require.Equal(t, notSupportedError.Field, "A")
+}
+
+func TestDecoderPrepare(t *testing.T) {
+	type Valid struct{ Name string }
+	type Invalid struct{ Done chan int }
+	type Pool struct{ Run func() }
+
+	dec := NewDecoder()
+
+	require.NoError(t, dec.Prepare(reflect.TypeFor[Valid](), reflect.TypeFor[[]Valid]()))
+
+	err := dec.Prepare(reflect.TypeFor[Valid](), reflect.TypeFor[Invalid](), reflect.TypeFor[*Pool]())
+	require.ErrorIs(t, err, NotSupportedError{Type: reflect.TypeFor[chan int]()})
+	require.ErrorIs(t, err, NotSupportedError{Type: reflect.TypeFor[func()]()})
+	require.ErrorContains(t, err, `prepare "unravel.Invalid"`)
+
+	// the prepared setter is used
+	value, err := UnmarshalNewWith[Valid](dec, SourceOf(map[string]any{"Name": "alex"}))
+	require.NoError(t, err)
+	require.Equal(t, value, Valid{Name: "alex"})
+}
+
+func TestDecoderDeriveKeepsSetters(t *testing.T) {
+	type Struct struct {
+		Name string `json:"name" yaml:"title"`
+	}
+
+	tyStruct := reflect.TypeFor[Struct]()
+	tyInts := reflect.TypeFor[map[string][]int]()
+
+	dec := NewDecoder()
+	require.NoError(t, dec.Prepare(tyStruct, tyInts))
+
+	derived := dec.WithTag("yaml")
+
+	_, ok := derived.setterCache.Load(tyInts)
+	require.True(t, ok)
+
+	// the setter of the struct depends on the struct tag
+	_, ok = derived.setterCache.Load(tyStruct)
+	require.False(t, ok)
+
+	value, err := UnmarshalNewWith[Struct](derived, SourceOf(map[string]any{"title": "derived"}))
+	require.NoError(t, err)
+	require.Equal(t, value.Name, "derived")
+
+	// other options affect all setters
+	_, ok = dec.WithLimits(Limits{MaxSliceLen: 1}).setterCache.Load(tyInts)
+	require.False(t, ok)
+}
+
+func TestUnsupportedTypeNested(t *testing.T) {
+	type Pool struct{ Done chan int }
+	type Server struct{ Pools []Pool }
+	type Config struct{ Server *Server }
+
+	_, err := UnmarshalNew[Config](dummySource{})
+
+	var notSupportedError NotSupportedError
+	require.ErrorAs(t, err, &notSupportedError)
+	require.Equal(t, notSupportedError.Struct, reflect.TypeFor[Pool]())
+	require.Equal(t, notSupportedError.Field, "Server.Pools.Done")
+	require.EqualError(t, err, `type "chan int" of field "Server.Pools.Done" in "unravel.Pool" is not supported`)
+
+	// the type matches regardless of the location
+	require.ErrorIs(t, err, NotSupportedError{Type: reflect.TypeFor[chan int]()})
+	require.NotErrorIs(t, err, NotSupportedError{Type: reflect.TypeFor[chan int](), Struct: reflect.TypeFor[Config]()})
 }
 
 func TestTypeUint(t *testing.T) {
@@ -340,6 +459,29 @@ func TestDecoderWithStructTag(t *testing.T) {
 	require.Equal(t, parsed, Struct{Foo: "Url"})
 }
 
+func TestDecoderWithStructTags(t *testing.T) {
+	type Config struct {
+		Host    string `env:"HOST" json:"host"`
+		Port    int    `json:"port"`
+		Debug   bool   `env:",required" json:"debug"`
+		Secret  string `env:"-" json:"secret"`
+		Verbose bool
+	}
+
+	source := SourceOf(map[string]any{
+		"HOST":    "localhost",
+		"host":    "ignored",
+		"port":    8080,
+		"debug":   true,
+		"secret":  "ignored",
+		"Verbose": true,
+	})
+
+	config, err := UnmarshalNewWith[Config](NewDecoder().WithTags("env", "json"), source)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{Host: "localhost", Port: 8080, Debug: true, Verbose: true})
+}
+
 func TestDecoderRequireValues(t *testing.T) {
 	type Struct struct {
 		Foo string
@@ -353,6 +495,440 @@ func TestDecoderRequireValues(t *testing.T) {
 	require.ErrorIs(t, err, ErrNoValue)
 }
 
+func TestDecoderEmptyStringAsNoValue(t *testing.T) {
+	type Config struct {
+		Host  string  `json:"host"`
+		Port  int     `json:"port" unravel:"default=8080"`
+		Token *string `json:"token"`
+		Name  string  `json:"name"`
+	}
+
+	source := SourceOf(map[string]string{"host": "", "port": "", "token": "", "name": "app"})
+
+	dec := NewDecoder().EmptyStringAsNoValue()
+
+	config := Config{Host: "localhost"}
+	err := dec.Unmarshal(source, &config)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{Host: "localhost", Port: 8080, Name: "app"})
+
+	_, err = UnmarshalNewWith[Config](dec.RequireValues(), source)
+	require.ErrorIs(t, err, ErrNoValue)
+
+	// by default, the empty string is decoded
+	config = Config{Host: "localhost"}
+	err = Unmarshal(SourceOf(map[string]string{"host": ""}), &config)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{Port: 8080})
+}
+
+func TestDecoderEmptyStringAsNoValueConsumingSource(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  int
+	}
+
+	// values of unknown kind are not read before decoding the field
+	source := &tokenSource{tokens: []string{"alex", "42"}}
+
+	person, err := UnmarshalNewWith[Person](NewDecoder().EmptyStringAsNoValue(), source)
+	require.NoError(t, err)
+	require.Equal(t, person, Person{Name: "alex", Age: 42})
+}
+
+// tokenSource reads the values of all fields from a sequence of tokens, like a binary stream.
+// Every accessor consumes a token.
+type tokenSource struct {
+	EmptySource
+	tokens []string
+}
+
+func (s *tokenSource) Get(key string) (Source, error) {
+	return s, nil
+}
+
+func (s *tokenSource) String() (string, error) {
+	if len(s.tokens) == 0 {
+		return "", io.ErrUnexpectedEOF
+	}
+
+	token := s.tokens[0]
+	s.tokens = s.tokens[1:]
+
+	return token, nil
+}
+
+func (s *tokenSource) Int() (int64, error) {
+	token, err := s.String()
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(token, 10, 64)
+}
+
+func TestDecoderMergeInto(t *testing.T) {
+	type TLS struct {
+		MinVersion string `json:"minVersion"`
+		Cert       string `json:"cert"`
+	}
+
+	type Limit struct {
+		Rate  int `json:"rate"`
+		Burst int `json:"burst"`
+	}
+
+	type Config struct {
+		Host   string            `json:"host"`
+		Port   int               `json:"port"`
+		TLS    *TLS              `json:"tls"`
+		Labels map[string]string `json:"labels"`
+		Limits map[string]Limit  `json:"limits"`
+	}
+
+	defaults := Config{
+		Host:   "localhost",
+		Port:   8080,
+		TLS:    &TLS{MinVersion: "1.2"},
+		Labels: map[string]string{"env": "dev"},
+		Limits: map[string]Limit{"api": {Rate: 10, Burst: 20}},
+	}
+
+	tls := defaults.TLS
+
+	source := SourceOf(map[string]any{
+		"port":   9090,
+		"tls":    map[string]any{"cert": "server.pem"},
+		"labels": map[string]string{"team": "core"},
+		"limits": map[string]any{"api": map[string]any{"rate": 5}},
+	})
+
+	config := defaults
+	err := NewDecoder().MergeInto().Unmarshal(source, &config)
+	require.NoError(t, err)
+
+	require.Equal(t, config, Config{
+		Host:   "localhost",
+		Port:   9090,
+		TLS:    &TLS{MinVersion: "1.2", Cert: "server.pem"},
+		Labels: map[string]string{"env": "dev", "team": "core"},
+		Limits: map[string]Limit{"api": {Rate: 5, Burst: 20}},
+	})
+
+	// the existing value was decoded into in place
+	require.Same(t, config.TLS, tls)
+
+	// without merging, pointers and maps are replaced
+	config = Config{TLS: &TLS{MinVersion: "1.2"}, Labels: map[string]string{"env": "dev"}}
+	err = Unmarshal(source, &config)
+	require.NoError(t, err)
+	require.Equal(t, config.TLS, &TLS{Cert: "server.pem"})
+	require.Equal(t, config.Labels, map[string]string{"team": "core"})
+}
+
+func TestDecoderRegisterSetter(t *testing.T) {
+	type Struct struct {
+		CreatedAt time.Time   `json:"createdAt"`
+		Times     []time.Time `json:"times"`
+		IP        net.IP      `json:"ip"`
+	}
+
+	dec := RegisterTypeFunc(NewDecoder(), func(source Source) (time.Time, error) {
+		seconds, err := source.Int()
+		return time.Unix(seconds, 0).UTC(), err
+	})
+
+	dec = dec.RegisterSetter(reflect.TypeFor[net.IP](), func(source Source, target reflect.Value) error {
+		target.Set(reflect.ValueOf(net.IPv4(10, 0, 0, 1)))
+		return nil
+	})
+
+	source := SourceOf(map[string]any{
+		"createdAt": 60,
+		"times":     []int{1, 2},
+		"ip":        "ignored",
+	})
+
+	value, err := UnmarshalNewWith[Struct](dec, source)
+	require.NoError(t, err)
+	require.Equal(t, value, Struct{
+		CreatedAt: time.Unix(60, 0).UTC(),
+		Times:     []time.Time{time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC()},
+		IP:        net.IPv4(10, 0, 0, 1),
+	})
+
+	// the original decoder is not modified
+	_, err = UnmarshalNewWith[Struct](NewDecoder(), source)
+	require.Error(t, err)
+}
+
+type storageBackend interface {
+	Location() string
+}
+
+type s3Storage struct {
+	Bucket string `json:"bucket"`
+}
+
+func (s *s3Storage) Location() string {
+	return "s3://" + s.Bucket
+}
+
+func TestRegisterInterfaceImpl(t *testing.T) {
+	type Config struct {
+		Storage  storageBackend   `json:"storage"`
+		Backups  []storageBackend `json:"backups"`
+		Fallback storageBackend   `json:"fallback"`
+	}
+
+	dec := RegisterInterfaceImpl[storageBackend, *s3Storage](NewDecoder())
+
+	input := `{
+		"storage": {"bucket": "data"},
+		"backups": [{"bucket": "one"}, {"bucket": "two"}],
+		"fallback": null
+	}`
+
+	config := Config{Fallback: &s3Storage{Bucket: "old"}}
+
+	err := dec.Unmarshal(JSONStreamSource(strings.NewReader(input)), &config)
+	require.NoError(t, err)
+
+	require.Equal(t, config.Storage, storageBackend(&s3Storage{Bucket: "data"}))
+	require.Equal(t, config.Storage.Location(), "s3://data")
+	require.Len(t, config.Backups, 2)
+	require.Equal(t, config.Backups[1].Location(), "s3://two")
+	require.Nil(t, config.Fallback)
+
+	// without registration, the interface can not be decoded
+	_, err = UnmarshalNew[Config](JSONStreamSource(strings.NewReader(input)))
+
+	var notSupportedError NotSupportedError
+	require.ErrorAs(t, err, &notSupportedError)
+	require.Equal(t, notSupportedError.Type, reflect.TypeFor[storageBackend]())
+
+	require.Panics(t, func() {
+		RegisterInterfaceImpl[storageBackend, s3Storage](NewDecoder())
+	})
+}
+
+func TestDecoderRegisterKindHook(t *testing.T) {
+	type Struct struct {
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags"`
+		Count int      `json:"count"`
+		Small int8     `json:"small"`
+	}
+
+	// trim all strings
+	dec := NewDecoder().RegisterKindHook(reflect.String, func(source Source, target reflect.Value, next func(Source, reflect.Value) error) error {
+		if err := next(source, target); err != nil {
+			return err
+		}
+
+		target.SetString(strings.TrimSpace(target.String()))
+		return nil
+	})
+
+	// accept ints given as string
+	dec = dec.RegisterKindHook(reflect.Int, func(source Source, target reflect.Value, next func(Source, reflect.Value) error) error {
+		err := next(source, target)
+		if !errors.Is(err, ErrNotSupported) {
+			return err
+		}
+
+		text, err := source.String()
+		if err != nil {
+			return err
+		}
+
+		return next(StringSource(text), target)
+	})
+
+	values := map[string]any{
+		"name":  "  Alex ",
+		"tags":  []string{" a", "b "},
+		"count": "42",
+	}
+
+	value, err := UnmarshalNewWith[Struct](dec, SourceOf(values))
+	require.NoError(t, err)
+	require.Equal(t, value, Struct{Name: "Alex", Tags: []string{"a", "b"}, Count: 42})
+
+	// hooks only apply to the registered kind
+	values["small"] = "1"
+
+	_, err = UnmarshalNewWith[Struct](dec, SourceOf(values))
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestDecoderWithHooks(t *testing.T) {
+	type Struct struct {
+		Timeout time.Duration   `json:"timeout"`
+		Retries []time.Duration `json:"retries"`
+		Host    net.IP          `json:"host"`
+		Name    string          `json:"name"`
+	}
+
+	durationHook := func(source Source, target reflect.Type) (Source, error) {
+		if target != reflect.TypeFor[time.Duration]() {
+			return source, nil
+		}
+
+		if _, err := source.Int(); err == nil {
+			// already in nanoseconds
+			return source, nil
+		}
+
+		text, err := source.String()
+		if err != nil {
+			return source, nil
+		}
+
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return nil, err
+		}
+
+		return SourceOf(duration), nil
+	}
+
+	var hostsSeen []string
+
+	hostHook := func(source Source, target reflect.Type) (Source, error) {
+		if target != reflect.TypeFor[net.IP]() {
+			return source, nil
+		}
+
+		text, _ := source.String()
+		hostsSeen = append(hostsSeen, text)
+
+		if text == "localhost" {
+			return StringSource("127.0.0.1"), nil
+		}
+
+		return source, nil
+	}
+
+	dec := NewDecoder().WithHooks(durationHook).WithHooks(hostHook)
+
+	source := SourceOf(map[string]any{
+		"timeout": "1m30s",
+		"retries": []any{"1s", 2},
+		"host":    "localhost",
+		"name":    "test",
+	})
+
+	value, err := UnmarshalNewWith[Struct](dec, source)
+	require.NoError(t, err)
+	require.Equal(t, value, Struct{
+		Timeout: 90 * time.Second,
+		Retries: []time.Duration{time.Second, 2},
+		Host:    net.IPv4(127, 0, 0, 1),
+		Name:    "test",
+	})
+
+	require.Equal(t, hostsSeen, []string{"localhost"})
+
+	_, err = UnmarshalNewWith[Struct](dec, SourceOf(map[string]any{"timeout": "soon"}))
+	require.ErrorContains(t, err, "soon")
+}
+
+type port struct {
+	Number int `json:"number"`
+}
+
+func (p port) Validate() error {
+	if p.Number <= 0 || p.Number > 65535 {
+		return fmt.Errorf("invalid port %d", p.Number)
+	}
+
+	return nil
+}
+
+type server struct {
+	Host  string `json:"host"`
+	Ports []port `json:"ports"`
+}
+
+func (s *server) Validate() error {
+	if s.Host == "" {
+		return errors.New("host is required")
+	}
+
+	return nil
+}
+
+func TestDecoderValidator(t *testing.T) {
+	valid := SourceOf(map[string]any{
+		"host":  "localhost",
+		"ports": []any{map[string]any{"number": 80}},
+	})
+
+	value, err := UnmarshalNew[server](valid)
+	require.NoError(t, err)
+	require.Equal(t, value, server{Host: "localhost", Ports: []port{{Number: 80}}})
+
+	_, err = UnmarshalNew[server](SourceOf(map[string]any{"ports": []any{}}))
+	require.ErrorContains(t, err, "host is required")
+
+	_, err = UnmarshalNew[server](SourceOf(map[string]any{
+		"host":  "localhost",
+		"ports": []any{map[string]any{"number": 80}, map[string]any{"number": 0}},
+	}))
+	require.ErrorContains(t, err, "invalid port 0")
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, decodeErr.Path, "$.ports[1]")
+	require.Equal(t, decodeErr.TargetType, reflect.TypeFor[port]())
+}
+
+func TestDecoderWithValidation(t *testing.T) {
+	var validated []any
+
+	dec := NewDecoder().WithValidation(func(value any) error {
+		validated = append(validated, value)
+		return errors.New("rejected")
+	})
+
+	var target port
+	err := dec.Unmarshal(SourceOf(map[string]int{"number": 80}), &target)
+	require.ErrorContains(t, err, "rejected")
+	require.Equal(t, validated, []any{&target})
+}
+
+func TestDecoderUnmarshalValue(t *testing.T) {
+	type Struct struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+
+	var validated any
+	dec := NewDecoder().WithValidation(func(value any) error {
+		validated = value
+		return nil
+	})
+
+	target := reflect.New(reflect.TypeFor[Struct]()).Elem()
+
+	err := dec.UnmarshalValue(SourceOf(map[string]any{"name": "api", "port": 8080}), target)
+	require.NoError(t, err)
+	require.Equal(t, target.Interface(), Struct{Name: "api", Port: 8080})
+	require.Equal(t, validated, target.Addr().Interface())
+
+	// fields of a struct can be decoded directly
+	err = dec.UnmarshalValue(StringSource("9090"), target.Field(1))
+	require.NoError(t, err)
+	require.Equal(t, target.Interface(), Struct{Name: "api", Port: 9090})
+
+	err = dec.UnmarshalValue(StringSource("9090"), reflect.ValueOf(8080))
+	require.Error(t, err)
+
+	err = dec.UnmarshalValue(StringSource("9090"), reflect.Value{})
+	require.Error(t, err)
+}
+
 func TestDecoderTextUnmarshalerInterface(t *testing.T) {
 	type Struct struct {
 		Foo encoding.TextUnmarshaler
@@ -402,6 +978,49 @@ func TestTextUnmarshaler(t *testing.T) {
 	})
 }
 
+type Range struct {
+	Min, Max int
+}
+
+func (r *Range) UnmarshalSource(source Source) error {
+	text, err := source.String()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Sscanf(text, "%d-%d", &r.Min, &r.Max)
+	return err
+}
+
+// UnmarshalText must not be called, as UnmarshalSource takes precedence
+func (r *Range) UnmarshalText(text []byte) error {
+	return errors.New("UnmarshalText called")
+}
+
+func TestUnmarshaler(t *testing.T) {
+	source := dummySource{
+		Values: map[string]any{
+			".Range":  "3-7",
+			".Ranges": []string{"1-2", "5-9"},
+		},
+	}
+
+	type Struct struct {
+		Range  Range
+		Ranges []*Range
+	}
+
+	value, err := UnmarshalNew[Struct](source)
+	require.NoError(t, err)
+	require.Equal(t, value, Struct{
+		Range:  Range{Min: 3, Max: 7},
+		Ranges: []*Range{{Min: 1, Max: 2}, {Min: 5, Max: 9}},
+	})
+
+	_, err = UnmarshalNew[Range](StringSource("invalid"))
+	require.Error(t, err)
+}
+
 func TestUnmarshalGitCommit(t *testing.T) {
 	type GitCommit struct {
 		Sha1   string
@@ -572,3 +1191,333 @@ func (d dummySource) Get(key string) (Source, error) {
 
 	return dummySource{Values: d.Values, Path: path}, nil
 }
+
+// rawJSON implements json.Unmarshaler only and keeps the raw JSON
+type rawJSON string
+
+func (r *rawJSON) UnmarshalJSON(raw []byte) error {
+	*r = rawJSON(raw)
+	return nil
+}
+
+// level implements json.Unmarshaler but can also be decoded as an int
+type level int
+
+func (l *level) UnmarshalJSON(raw []byte) error {
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return err
+	}
+
+	*l = level(len(name))
+	return nil
+}
+
+func TestJSONUnmarshaler(t *testing.T) {
+	type Struct struct {
+		Object rawJSON   `json:"object"`
+		Number rawJSON   `json:"number"`
+		Level  level     `json:"level"`
+		List   []rawJSON `json:"list"`
+	}
+
+	input := `{"object": {"a": [1, {"b": null}], "c": "d"}, "number": 1.5, "level": "high", "list": [true, []]}`
+
+	expected := Struct{
+		Object: `{"a":[1,{"b":null}],"c":"d"}`,
+		Number: `1.5`,
+		Level:  4,
+		List:   []rawJSON{"true", "[]"},
+	}
+
+	value, err := UnmarshalNew[Struct](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, value, expected)
+
+	values, err := UnmarshalNew[[]Struct](NDJSONSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, values, []Struct{expected})
+
+	// falls back to decoding by kind without raw json
+	fallback, err := UnmarshalNew[level](StringSource("3"))
+	require.NoError(t, err)
+	require.Equal(t, fallback, level(3))
+}
+
+// connection can only be created using its constructor
+type connection struct {
+	addr   string
+	opened bool
+}
+
+func openConnection(addr string) (connection, error) {
+	return connection{addr: addr, opened: true}, nil
+}
+
+func TestRegisterTypeFuncAsFactory(t *testing.T) {
+	type Cluster struct {
+		Primary  connection            `json:"primary"`
+		Backup   *connection           `json:"backup"`
+		Replicas []connection          `json:"replicas"`
+		Pair     [2]connection         `json:"pair"`
+		ByName   map[string]connection `json:"byName"`
+		Weights  map[connection]int    `json:"weights"`
+		Fallback Optional[connection]  `json:"fallback"`
+	}
+
+	dec := RegisterTypeFunc(NewDecoder(), func(source Source) (connection, error) {
+		addr, err := source.String()
+		if err != nil {
+			return connection{}, err
+		}
+
+		return openConnection(addr)
+	})
+
+	source := SourceOf(map[string]any{
+		"primary":  "a:1",
+		"backup":   "b:1",
+		"replicas": []string{"c:1"},
+		"pair":     []string{"d:1", "d:2"},
+		"byName":   map[string]string{"e": "e:1"},
+		"weights":  map[string]int{"f:1": 3},
+		"fallback": "g:1",
+	})
+
+	cluster, err := UnmarshalNewWith[Cluster](dec, source)
+	require.NoError(t, err)
+
+	conn := func(addr string) connection {
+		return connection{addr: addr, opened: true}
+	}
+
+	backup := conn("b:1")
+
+	require.Equal(t, cluster, Cluster{
+		Primary:  conn("a:1"),
+		Backup:   &backup,
+		Replicas: []connection{conn("c:1")},
+		Pair:     [2]connection{conn("d:1"), conn("d:2")},
+		ByName:   map[string]connection{"e": conn("e:1")},
+		Weights:  map[connection]int{conn("f:1"): 3},
+		Fallback: Some(conn("g:1")),
+	})
+}
+
+func TestNaming_EmbeddedPointer(t *testing.T) {
+	type Base struct {
+		ID string `json:"id"`
+	}
+
+	type Node struct {
+		*Node
+		*Base
+		Name string `json:"name"`
+	}
+
+	source := SourceOf(map[string]any{"id": "1", "name": "root"})
+
+	node, err := UnmarshalNew[Node](source)
+	require.NoError(t, err)
+	require.Equal(t, node, Node{Base: &Base{ID: "1"}, Name: "root"})
+
+	// the embedded pointer is only allocated if a promoted field has a value
+	node, err = UnmarshalNew[Node](SourceOf(map[string]any{"name": "root"}))
+	require.NoError(t, err)
+	require.Nil(t, node.Base)
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+	require.NoError(t, Marshal(Node{Base: &Base{ID: "2"}}, sink))
+	require.Equal(t, sink.Values, map[string]any{"$.id": "2", "$.name": ""})
+}
+
+func TestNaming_EmbeddedSameTypeConflict(t *testing.T) {
+	type Base struct{ A string }
+	type First struct{ Base }
+	type Second struct{ Base }
+
+	type Struct struct {
+		First
+		Second
+	}
+
+	stud, err := UnmarshalNew[Struct](SourceOf(map[string]any{"A": "A"}))
+	require.NoError(t, err)
+	require.Equal(t, stud, Struct{
+		// naming conflict, nothing deserializes
+	})
+}
+
+type unexportedBase struct {
+	ID      string `json:"id"`
+	private string
+}
+
+func TestNaming_EmbeddedUnexportedType(t *testing.T) {
+	type Struct struct {
+		unexportedBase
+		Name string `json:"name"`
+	}
+
+	stud, err := UnmarshalNew[Struct](SourceOf(map[string]any{"id": "1", "name": "a", "private": "x"}))
+	require.NoError(t, err)
+	require.Equal(t, stud, Struct{unexportedBase: unexportedBase{ID: "1"}, Name: "a"})
+
+	// promoted fields are visible through a reflect source too
+	copied, err := UnmarshalNew[Struct](SourceOf(stud))
+	require.NoError(t, err)
+	require.Equal(t, copied, stud)
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+	require.NoError(t, Marshal(stud, sink))
+	require.Equal(t, sink.Values, map[string]any{"$.id": "1", "$.name": "a"})
+}
+
+func TestNaming_EmbeddedUnexportedPointer(t *testing.T) {
+	type Struct struct {
+		*unexportedBase
+	}
+
+	// an existing value can be decoded into
+	stud := Struct{unexportedBase: &unexportedBase{}}
+	err := Unmarshal(SourceOf(map[string]any{"id": "1"}), &stud)
+	require.NoError(t, err)
+	require.Equal(t, stud.ID, "1")
+
+	// but a nil pointer to an unexported type can not be allocated
+	_, err = UnmarshalNew[Struct](SourceOf(map[string]any{"id": "1"}))
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestDecodeFieldAliases(t *testing.T) {
+	type User struct {
+		Name  string `json:"name" unravel:"alt=Name|user_name"`
+		Email string `json:"email"`
+	}
+
+	sources := []Source{
+		SourceOf(map[string]any{"name": "Alex"}),
+		SourceOf(map[string]any{"Name": "Alex"}),
+		SourceOf(map[string]any{"user_name": "Alex"}),
+
+		// the fields name wins over its aliases
+		SourceOf(map[string]any{"name": "Alex", "user_name": "Other"}),
+	}
+
+	for _, source := range sources {
+		user, err := UnmarshalNew[User](source)
+		require.NoError(t, err)
+		require.Equal(t, user, User{Name: "Alex"})
+	}
+
+	_, err := UnmarshalNewWith[User](NewDecoder().RequireValues(), SourceOf(map[string]any{"email": "a@b"}))
+	require.ErrorIs(t, err, ErrNoValue)
+
+	type Invalid struct {
+		Name string `unravel:"alt="`
+	}
+
+	_, err = UnmarshalNew[Invalid](SourceOf(map[string]any{}))
+	require.Error(t, err)
+}
+
+func TestDecodeNestedPath(t *testing.T) {
+	type Order struct {
+		ID    string `json:"id"`
+		Last4 string `json:"payment.card.last4"`
+		Brand string `json:"payment.card.brand"`
+		City  string `json:"shipping.city" unravel:"alt=billing.city"`
+	}
+
+	input := `{
+		"id": "1",
+		"payment": {"card": {"last4": "4242", "brand": null}},
+		"billing": {"city": "Berlin"}
+	}`
+
+	order, err := UnmarshalNew[Order](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, order, Order{ID: "1", Last4: "4242", City: "Berlin"})
+
+	// a key containing dots takes precedence over the path
+	order, err = UnmarshalNew[Order](SourceOf(map[string]any{"payment.card.last4": "1111"}))
+	require.NoError(t, err)
+	require.Equal(t, order, Order{Last4: "1111"})
+}
+
+func TestDecodeIndexTag(t *testing.T) {
+	type Row struct {
+		Name  string  `idx:"0"`
+		Age   int     `idx:"1"`
+		Score float64 `idx:"3"`
+	}
+
+	row, err := UnmarshalNew[Row](SourceOf([]any{"Alex", 21, "ignored", 1.5}))
+	require.NoError(t, err)
+	require.Equal(t, row, Row{Name: "Alex", Age: 21, Score: 1.5})
+
+	row, err = UnmarshalNew[Row](Raw{Bytes: []byte(`["Alex", 21]`)}.Source())
+	require.NoError(t, err)
+	require.Equal(t, row, Row{Name: "Alex", Age: 21})
+
+	_, err = UnmarshalNewWith[Row](NewDecoder().RequireValues(), SourceOf([]any{"Alex", 21}))
+	require.ErrorIs(t, err, ErrNoValue)
+
+	// positional access requires an IndexSource
+	_, err = UnmarshalNew[Row](dummySource{})
+	require.ErrorIs(t, err, ErrNotSupported)
+
+	type Invalid struct {
+		Value string `idx:"-1"`
+	}
+
+	_, err = UnmarshalNew[Invalid](SourceOf([]string{}))
+	require.Error(t, err)
+}
+
+func TestNaming_JSONTagEdgeCases(t *testing.T) {
+	type Struct struct {
+		Dash    string `json:"-,"`
+		Skipped string `json:"-"`
+		Zero    int    `json:",omitzero"`
+		Named   int    `json:"named,omitzero"`
+	}
+
+	source := SourceOf(map[string]any{"-": "dash", "Skipped": "x", "Zero": 1, "named": 2})
+
+	stud, err := UnmarshalNew[Struct](source)
+	require.NoError(t, err)
+	require.Equal(t, stud, Struct{Dash: "dash", Zero: 1, Named: 2})
+}
+
+func TestDecoderStrictFields(t *testing.T) {
+	type First struct{ A, B string }
+	type Second struct{ A string }
+
+	type Struct struct {
+		First
+		Second
+		B string
+	}
+
+	source := SourceOf(map[string]any{"A": "A", "B": "B"})
+
+	// conflicting fields are ignored by default
+	stud, err := UnmarshalNew[Struct](source)
+	require.NoError(t, err)
+	require.Equal(t, stud, Struct{B: "B"})
+
+	_, err = UnmarshalNewWith[Struct](NewDecoder().StrictFields(), source)
+	require.ErrorIs(t, err, ErrAmbiguousField)
+	require.ErrorContains(t, err, `"A" is used by First.A, Second.A`)
+
+	// a field on a lower nesting level is no conflict
+	type Resolved struct {
+		First
+		A string
+	}
+
+	resolved, err := UnmarshalNewWith[Resolved](NewDecoder().StrictFields(), source)
+	require.NoError(t, err)
+	require.Equal(t, resolved, Resolved{A: "A", First: First{B: "B"}})
+}
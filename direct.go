@@ -0,0 +1,146 @@
+package unravel
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// hasPlainSetter reports whether values of the type are decoded by the setter of their
+// kind, without any of the hooks, setters or options of the decoder applying to them.
+func (d *Decoder) hasPlainSetter(ty reflect.Type) bool {
+	if _, ok := d.customSetters[ty]; ok {
+		return false
+	}
+
+	if _, ok := d.constructors[ty]; ok {
+		return false
+	}
+
+	if len(d.kindHooks[ty.Kind()]) > 0 || len(d.decodeHooks) > 0 {
+		return false
+	}
+
+	switch {
+	case d.trimSpace && isScalarKind(ty.Kind()):
+		return false
+
+	case d.numberFormat != nil && isNumberKind(ty.Kind()):
+		return false
+
+	case d.limits.MaxStringLen > 0 && ty.Kind() == reflect.String:
+		return false
+	}
+
+	return true
+}
+
+// decodesAsString reports whether the setter of the type is the setter of the string kind,
+// i.e. the type implements none of the interfaces taking precedence over its kind.
+func decodesAsString(ty reflect.Type) bool {
+	if ty.Kind() != reflect.String || ty == tyNumber {
+		return false
+	}
+
+	ptrType := reflect.PointerTo(ty)
+
+	return !ptrType.Implements(tyUnmarshaler) &&
+		!ptrType.Implements(tyElementAppender) &&
+		!ptrType.Implements(tyMapWriter) &&
+		!ptrType.Implements(tyTextUnmarshaler) &&
+		!ptrType.Implements(tyJSONUnmarshaler)
+}
+
+// decodesAsText reports whether the setter of the type is setTextUnmarshaler,
+// i.e. the type implements no interface taking precedence over encoding.TextUnmarshaler.
+func decodesAsText(ty reflect.Type) bool {
+	switch ty {
+	case tyTime, tyDuration, tyBigInt, tyBigFloat, tyBigRat, tyRaw, tyNumber, tySyncMap, tyList:
+		return false
+	}
+
+	if isOptionalType(ty) || isSQLNullType(ty) || isOrderedMapType(ty) {
+		return false
+	}
+
+	ptrType := reflect.PointerTo(ty)
+
+	return ptrType.Implements(tyTextUnmarshaler) &&
+		!ptrType.Implements(tyUnmarshaler) &&
+		!ptrType.Implements(tyElementAppender) &&
+		!ptrType.Implements(tyMapWriter)
+}
+
+// predeclaredSetters holds the setter of each predeclared type of a scalar kind.
+// These types have no methods, so their values are always decoded based on their kind.
+var predeclaredSetters = makePredeclaredSetters()
+
+func makePredeclaredSetters() map[reflect.Type]setter {
+	types := []reflect.Type{
+		reflect.TypeFor[bool](), reflect.TypeFor[string](),
+		reflect.TypeFor[int](), reflect.TypeFor[int8](), reflect.TypeFor[int16](), reflect.TypeFor[int32](), reflect.TypeFor[int64](),
+		reflect.TypeFor[uint](), reflect.TypeFor[uint8](), reflect.TypeFor[uint16](), reflect.TypeFor[uint32](), reflect.TypeFor[uint64](),
+		reflect.TypeFor[float32](), reflect.TypeFor[float64](),
+	}
+
+	// the setters of scalar kinds do not depend on the options of the decoder,
+	// except for the limits, see hasPlainSetter
+	var d Decoder
+
+	setters := map[reflect.Type]setter{}
+	for _, ty := range types {
+		setter, err := d.makeKindSetterOf(typeSet{}, ty)
+		if err != nil {
+			panic(err)
+		}
+
+		setters[ty] = setter
+	}
+
+	return setters
+}
+
+// unmarshalDirect decodes into a pointer to a predeclared type, like int64 or string, or to
+// a type implementing [encoding.TextUnmarshaler], by calling the accessor of the [Source]
+// directly. Decoding a single value does not need the setters built for the type. Returns
+// false if the target must be decoded using its setter.
+func (d *Decoder) unmarshalDirect(ctx context.Context, source Source, target any) (bool, error) {
+	if d.validate != nil {
+		return false, nil
+	}
+
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return false, nil
+	}
+
+	ty := ptr.Type().Elem()
+
+	if set, ok := predeclaredSetters[ty]; ok {
+		if !d.hasPlainSetter(ty) {
+			return false, nil
+		}
+
+		if err := set(ctx, source, ptr.Elem()); err != nil {
+			return true, withPath(err, "$", ty)
+		}
+
+		return true, nil
+	}
+
+	if unmarshaler, ok := target.(encoding.TextUnmarshaler); ok && d.hasPlainSetter(ty) && decodesAsText(ty) {
+		text, err := source.String()
+		if err != nil {
+			return true, withPath(typeErrorOf(source, ty, fmt.Errorf("get string value: %w", err)), "$", ty)
+		}
+
+		if err := unmarshaler.UnmarshalText([]byte(text)); err != nil {
+			return true, withPath(err, "$", ty)
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
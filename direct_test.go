@@ -0,0 +1,57 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalDirect(t *testing.T) {
+	value, err := UnmarshalNew[int64](StringSource("42"))
+	require.NoError(t, err)
+	require.Equal(t, value, int64(42))
+
+	text, err := UnmarshalNew[string](SourceOf("hello"))
+	require.NoError(t, err)
+	require.Equal(t, text, "hello")
+
+	addr, err := UnmarshalNew[netip.Addr](StringSource("10.0.0.1"))
+	require.NoError(t, err)
+	require.Equal(t, addr, netip.MustParseAddr("10.0.0.1"))
+
+	// errors are the same as for values decoded using their setter
+	_, err = UnmarshalNew[int8](StringSource("300"))
+
+	var overflowErr *OverflowError
+	require.ErrorAs(t, err, &overflowErr)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, decodeErr.Path, "$")
+
+	_, err = UnmarshalNew[netip.Addr](StringSource("invalid"))
+	require.ErrorAs(t, err, &decodeErr)
+
+	// options of the decoder still apply
+	trimmed, err := UnmarshalNewWith[int](NewDecoder().TrimSpace(), StringSource(" 7 "))
+	require.NoError(t, err)
+	require.Equal(t, trimmed, 7)
+
+	doubled := func(source Source, target reflect.Value, next func(Source, reflect.Value) error) error {
+		if err := next(source, target); err != nil {
+			return err
+		}
+
+		target.SetInt(target.Int() * 2)
+		return nil
+	}
+
+	hooked, err := UnmarshalNewWith[int](NewDecoder().RegisterKindHook(reflect.Int, doubled), StringSource("21"))
+	require.NoError(t, err)
+	require.Equal(t, hooked, 42)
+
+	_, err = UnmarshalNewWith[string](NewDecoder().WithLimits(Limits{MaxStringLen: 3}), SourceOf(strings.Repeat("x", 4)))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
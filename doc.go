@@ -10,4 +10,7 @@
 // target Go type and retrieves the corresponding data from the [Source]. This design
 // makes it easy to work with a variety of serialized formats while maintaining a consistent
 // API for decoding.
+//
+// The reverse direction is covered by [Marshal] and the [Encoder] type. Here the Go value
+// drives the process and writes its contents into a [Sink], the counterpart of a [Source].
 package unravel
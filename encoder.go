@@ -0,0 +1,504 @@
+package unravel
+
+import (
+	"cmp"
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Marshal walks the provided value and writes it into the given [Sink]. It is the
+// counterpart to [Unmarshal]: the type of the value drives the process, calling the
+// corresponding methods of the [Sink] for each struct field, map entry or slice element.
+//
+// Struct fields are named the same way as during [Unmarshal], using the `json` struct
+// tag by default. Nil pointers, slices, maps and interfaces are skipped when they appear
+// as struct fields or map values. Map entries are written in the order of their keys.
+// Map keys must be strings, integers, or implement [encoding.TextMarshaler].
+//
+// If a value implements [encoding.TextMarshaler], the result of
+// [encoding.TextMarshaler.MarshalText] is written using [Sink.SetString].
+//
+// Example:
+//
+//	person := Person{Name: "Alex", Tags: []string{"admin"}}
+//	err := unravel.Marshal(person, sink)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func Marshal(value any, sink Sink) error {
+	return enc.Marshal(value, sink)
+}
+
+// An emitter writes a [reflect.Value] into the given [Sink]
+type emitter func(Sink, reflect.Value) error
+
+var tyTextMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+
+// The default [Encoder] instance.
+var enc Encoder
+
+// Encoder can be used to customize marshalling.
+// An encoder is threadsafe once created.
+type Encoder struct {
+	// The struct tag that is used
+	structTag string
+
+	// Cache for emitters, indexed by [reflect.Type]
+	emitterCache sync.Map
+}
+
+func NewEncoder() *Encoder {
+	return &Encoder{
+		structTag: "json",
+	}
+}
+
+func (e *Encoder) WithTag(structTag string) *Encoder {
+	if e.structTag == structTag {
+		return e
+	}
+
+	return &Encoder{structTag: structTag}
+}
+
+func (e *Encoder) Marshal(value any, sink Sink) error {
+	sourceValue := reflect.ValueOf(value)
+	if !sourceValue.IsValid() {
+		// nothing to write for a nil value
+		return nil
+	}
+
+	emitter, err := e.emitterOf(typeSet{}, sourceValue.Type())
+	if err != nil {
+		return err
+	}
+
+	return emitter(sink, sourceValue)
+}
+
+func (e *Encoder) emitterOf(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	if cached, ok := e.emitterCache.Load(ty); ok {
+		return cached.(emitter), nil
+	}
+
+	if _, ok := inConstruction[ty]; ok {
+		// detected a cycle, see Decoder.setterOf
+		lazyEmitter := func(sink Sink, value reflect.Value) error {
+			cached, _ := e.emitterCache.Load(ty)
+			return cached.(emitter)(sink, value)
+		}
+
+		return lazyEmitter, nil
+	}
+
+	inConstruction[ty] = struct{}{}
+
+	emitter, err := e.makeEmitterOf(inConstruction, ty)
+	if err != nil {
+		return nil, err
+	}
+
+	e.emitterCache.Store(ty, emitter)
+
+	return emitter, nil
+}
+
+func (e *Encoder) makeEmitterOf(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	if ty.Implements(tyTextMarshaler) {
+		return emitTextMarshaler, nil
+	}
+
+	if isOptionalType(ty) {
+		return e.makeEmitOptional(inConstruction, ty)
+	}
+
+	if isOrderedMapType(ty) {
+		return e.makeEmitOrderedMap(inConstruction, ty)
+	}
+
+	switch ty {
+	case tyRaw:
+		return e.emitRaw, nil
+	case tySyncMap:
+		return e.emitSyncMap, nil
+	case tyList:
+		return e.emitList, nil
+	}
+
+	switch ty.Kind() {
+	case reflect.Bool:
+		return emitBool, nil
+
+	case reflect.Int:
+		return emitInt, nil
+
+	case reflect.Int8:
+		return makeEmitInt(BinarySink.SetInt8), nil
+
+	case reflect.Int16:
+		return makeEmitInt(BinarySink.SetInt16), nil
+
+	case reflect.Int32:
+		return makeEmitInt(BinarySink.SetInt32), nil
+
+	case reflect.Int64:
+		return makeEmitInt(BinarySink.SetInt64), nil
+
+	case reflect.Uint:
+		return emitUint, nil
+
+	case reflect.Uint8:
+		return makeEmitUint(BinarySink.SetUint8), nil
+
+	case reflect.Uint16:
+		return makeEmitUint(BinarySink.SetUint16), nil
+
+	case reflect.Uint32:
+		return makeEmitUint(BinarySink.SetUint32), nil
+
+	case reflect.Uint64:
+		return makeEmitUint(BinarySink.SetUint64), nil
+
+	case reflect.Float32:
+		return makeEmitFloat(BinarySink.SetFloat32), nil
+
+	case reflect.Float64:
+		return makeEmitFloat(BinarySink.SetFloat64), nil
+
+	case reflect.String:
+		return emitString, nil
+
+	case reflect.Pointer:
+		return e.makeEmitPointer(inConstruction, ty)
+
+	case reflect.Interface:
+		return e.emitInterface, nil
+
+	case reflect.Struct:
+		return e.makeEmitStruct(inConstruction, ty)
+
+	case reflect.Slice, reflect.Array:
+		return e.makeEmitList(inConstruction, ty)
+
+	case reflect.Map:
+		return e.makeEmitMap(inConstruction, ty)
+
+	default:
+		return nil, NotSupportedError{Type: ty}
+	}
+}
+
+func (e *Encoder) makeEmitStruct(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	var emitters []emitter
+
+	structTag := e.structTag
+	if structTag == "" {
+		structTag = "json"
+	}
+
+	fields := fieldsToSerialize(ty, structTag)
+
+	// map fields collecting the remaining keys on decoding
+	var remain []bool
+
+	// fields with the omitzero option
+	var omitZero []bool
+
+	for _, field := range fields {
+		em, err := e.emitterOf(inConstruction, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("emitter for field %q: %w", field.Name, err)
+		}
+
+		emitters = append(emitters, em)
+		remain = append(remain, isRemainField(field))
+		omitZero = append(omitZero, slices.Contains(structTagOptions(field, []string{structTag}), "omitzero"))
+	}
+
+	emitter := func(sink Sink, value reflect.Value) error {
+		for idx, field := range fields {
+			fieldValue, ok := fieldByIndex(value, field.Index)
+			if !ok || isNil(fieldValue) || isEmptyOptional(fieldValue) {
+				// nothing to write
+				continue
+			}
+
+			if omitZero[idx] && isZero(fieldValue) {
+				continue
+			}
+
+			if remain[idx] {
+				// the entries of the map are written next to the other fields
+				if err := emitters[idx](sink, fieldValue); err != nil {
+					return fmt.Errorf("emit field %q of %q: %w", field.Name, value.Type(), err)
+				}
+
+				continue
+			}
+
+			fieldSink, err := sink.Child(field.Name)
+			if err != nil {
+				return fmt.Errorf("child %q: %w", field.Name, err)
+			}
+
+			if err := emitters[idx](fieldSink, fieldValue); err != nil {
+				return fmt.Errorf("emit field %q of %q: %w", field.Name, value.Type(), err)
+			}
+		}
+
+		return nil
+	}
+
+	return emitter, nil
+}
+
+type isZeroer interface {
+	IsZero() bool
+}
+
+var tyIsZeroer = reflect.TypeFor[isZeroer]()
+
+// isZero reports whether the value is zero for the omitzero option. Like in encoding/json,
+// the IsZero method of the value is used if it has one, e.g. for [time.Time].
+func isZero(value reflect.Value) bool {
+	switch {
+	case value.Type().Implements(tyIsZeroer):
+		if value.Kind() == reflect.Pointer && value.IsNil() {
+			return true
+		}
+
+		return value.Interface().(isZeroer).IsZero()
+
+	case value.CanAddr() && reflect.PointerTo(value.Type()).Implements(tyIsZeroer):
+		return value.Addr().Interface().(isZeroer).IsZero()
+
+	default:
+		return value.IsZero()
+	}
+}
+
+// fieldByIndex works like [reflect.Value.FieldByIndex] but returns false
+// if the field is within a nil embedded struct pointer.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for idx, fieldIdx := range index {
+		if idx > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(fieldIdx)
+	}
+
+	return value, true
+}
+
+// isNil reports whether the value is a nil pointer, slice, map or interface.
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+func (e *Encoder) makeEmitList(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	elementEmitter, err := e.emitterOf(inConstruction, ty.Elem())
+	if err != nil {
+		return nil, fmt.Errorf("emitter for element type %q: %w", ty, err)
+	}
+
+	emitter := func(sink Sink, value reflect.Value) error {
+		for idx := range value.Len() {
+			elementSink, err := sink.Element()
+			if err != nil {
+				return fmt.Errorf("element idx=%d: %w", idx, err)
+			}
+
+			if err := elementEmitter(elementSink, value.Index(idx)); err != nil {
+				return fmt.Errorf("emit element idx=%d: %w", idx, err)
+			}
+		}
+
+		return nil
+	}
+
+	return emitter, nil
+}
+
+func (e *Encoder) makeEmitMap(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	formatKey, err := keyFormatterOf(ty.Key())
+	if err != nil {
+		return nil, fmt.Errorf("key of map type %q: %w", ty, err)
+	}
+
+	valueEmitter, err := e.emitterOf(inConstruction, ty.Elem())
+	if err != nil {
+		return nil, fmt.Errorf("emitter for value type %q: %w", ty, err)
+	}
+
+	type entry struct {
+		Key   string
+		Value reflect.Value
+	}
+
+	emitter := func(sink Sink, value reflect.Value) error {
+		entries := make([]entry, 0, value.Len())
+
+		for iter := value.MapRange(); iter.Next(); {
+			key, err := formatKey(iter.Key())
+			if err != nil {
+				return fmt.Errorf("format key: %w", err)
+			}
+
+			entries = append(entries, entry{Key: key, Value: iter.Value()})
+		}
+
+		// write entries in a stable order
+		slices.SortFunc(entries, func(a, b entry) int { return cmp.Compare(a.Key, b.Key) })
+
+		for _, entry := range entries {
+			if isNil(entry.Value) {
+				continue
+			}
+
+			entrySink, err := sink.Child(entry.Key)
+			if err != nil {
+				return fmt.Errorf("child %q: %w", entry.Key, err)
+			}
+
+			if err := valueEmitter(entrySink, entry.Value); err != nil {
+				return fmt.Errorf("emit value of key %q: %w", entry.Key, err)
+			}
+		}
+
+		return nil
+	}
+
+	return emitter, nil
+}
+
+// keyFormatterOf returns a function that formats a map key of the given type as string.
+func keyFormatterOf(ty reflect.Type) (func(reflect.Value) (string, error), error) {
+	if ty.Implements(tyTextMarshaler) {
+		formatter := func(key reflect.Value) (string, error) {
+			text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+			return string(text), err
+		}
+
+		return formatter, nil
+	}
+
+	switch ty.Kind() {
+	case reflect.String:
+		return func(key reflect.Value) (string, error) { return key.String(), nil }, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(key reflect.Value) (string, error) { return strconv.FormatInt(key.Int(), 10), nil }, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(key reflect.Value) (string, error) { return strconv.FormatUint(key.Uint(), 10), nil }, nil
+
+	default:
+		return nil, NotSupportedError{Type: ty}
+	}
+}
+
+func (e *Encoder) makeEmitPointer(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	pointeeEmitter, err := e.emitterOf(inConstruction, ty.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	emitter := func(sink Sink, value reflect.Value) error {
+		if value.IsNil() {
+			return nil
+		}
+
+		return pointeeEmitter(sink, value.Elem())
+	}
+
+	return emitter, nil
+}
+
+// emitInterface emits the dynamic value of an interface.
+func (e *Encoder) emitInterface(sink Sink, value reflect.Value) error {
+	if value.IsNil() {
+		return nil
+	}
+
+	dynamicValue := value.Elem()
+
+	emitter, err := e.emitterOf(typeSet{}, dynamicValue.Type())
+	if err != nil {
+		return err
+	}
+
+	return emitter(sink, dynamicValue)
+}
+
+func emitBool(sink Sink, value reflect.Value) error {
+	return sink.SetBool(value.Bool())
+}
+
+func emitInt(sink Sink, value reflect.Value) error {
+	return sink.SetInt(value.Int())
+}
+
+func emitUint(sink Sink, value reflect.Value) error {
+	return sink.SetUint(value.Uint())
+}
+
+func makeEmitInt[T int8 | int16 | int32 | int64](set func(BinarySink, T) error) emitter {
+	return func(sink Sink, value reflect.Value) error {
+		if binarySink, ok := sink.(BinarySink); ok {
+			return set(binarySink, T(value.Int()))
+		}
+
+		return sink.SetInt(value.Int())
+	}
+}
+
+func makeEmitUint[T uint8 | uint16 | uint32 | uint64](set func(BinarySink, T) error) emitter {
+	return func(sink Sink, value reflect.Value) error {
+		if binarySink, ok := sink.(BinarySink); ok {
+			return set(binarySink, T(value.Uint()))
+		}
+
+		return sink.SetUint(value.Uint())
+	}
+}
+
+func makeEmitFloat[T float32 | float64](set func(BinarySink, T) error) emitter {
+	return func(sink Sink, value reflect.Value) error {
+		if binarySink, ok := sink.(BinarySink); ok {
+			return set(binarySink, T(value.Float()))
+		}
+
+		return sink.SetFloat(value.Float())
+	}
+}
+
+func emitString(sink Sink, value reflect.Value) error {
+	return sink.SetString(value.String())
+}
+
+func emitTextMarshaler(sink Sink, value reflect.Value) error {
+	if isNil(value) {
+		return nil
+	}
+
+	text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return fmt.Errorf("marshal text: %w", err)
+	}
+
+	return sink.SetString(string(text))
+}
@@ -0,0 +1,210 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMarshalStruct(t *testing.T) {
+	type Address struct {
+		City    string
+		ZipCode int32 `json:"zip,omitempty"`
+	}
+
+	//goland:noinspection ALL
+	type Student struct {
+		Name       string
+		AgeInYears int64  `json:"age"`
+		SkipThis   string `json:"-"`
+		Address    *Address
+		Previous   *Address
+		Height     float32
+		Accepted   bool
+		Grades     []uint8
+		Scores     map[string]float64
+		IP         net.IP
+
+		// not exported, must not be written
+		note string
+	}
+
+	stud := Student{
+		Name:       "Albert",
+		AgeInYears: 21,
+		SkipThis:   "FOOBAR",
+		Address:    &Address{City: "Zürich", ZipCode: 8015},
+		Height:     1.5,
+		Accepted:   true,
+		Grades:     []uint8{5, 6},
+		Scores:     map[string]float64{"math": 2.5},
+		IP:         net.IPv4(127, 0, 0, 1),
+		note:       "secret",
+	}
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+
+	err := Marshal(stud, sink)
+	require.NoError(t, err)
+
+	require.Equal(t, sink.Values, map[string]any{
+		"$.Name":         "Albert",
+		"$.age":          int64(21),
+		"$.Address.City": "Zürich",
+		"$.Address.zip":  int64(8015),
+		"$.Height":       1.5,
+		"$.Accepted":     true,
+		"$.Grades.0":     uint64(5),
+		"$.Grades.1":     uint64(6),
+		"$.Scores.math":  2.5,
+		"$.IP":           "127.0.0.1",
+	})
+}
+
+func TestMarshalBinarySink(t *testing.T) {
+	type Header struct {
+		Magic   uint16
+		Version int8
+		Scale   float32
+	}
+
+	sink := &binarySink{}
+
+	err := Marshal(Header{Magic: 0xcafe, Version: -1, Scale: 0.5}, sink)
+	require.NoError(t, err)
+	require.Equal(t, sink.Values, []any{uint16(0xcafe), int8(-1), float32(0.5)})
+}
+
+func TestMarshalMapKeys(t *testing.T) {
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+
+	err := Marshal(map[int]any{2: "two", 10: []string{"ten"}, 3: nil}, sink)
+	require.NoError(t, err)
+
+	require.Equal(t, sink.Values, map[string]any{
+		"$.2":    "two",
+		"$.10.0": "ten",
+	})
+
+	err = Marshal(map[float64]string{1.5: "x"}, sink)
+	require.ErrorAs(t, err, &NotSupportedError{})
+}
+
+func TestMarshalRecursiveType(t *testing.T) {
+	type Node struct {
+		Value    int
+		Children []Node
+	}
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+
+	err := Marshal(Node{Value: 1, Children: []Node{{Value: 2}}}, sink)
+	require.NoError(t, err)
+
+	require.Equal(t, sink.Values, map[string]any{
+		"$.Value":            int64(1),
+		"$.Children.0.Value": int64(2),
+	})
+}
+
+func TestEncoderWithStructTag(t *testing.T) {
+	type Struct struct {
+		Name string `custom:"name"`
+	}
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+
+	err := NewEncoder().WithTag("custom").Marshal(Struct{Name: "Albert"}, sink)
+	require.NoError(t, err)
+	require.Equal(t, sink.Values, map[string]any{"$.name": "Albert"})
+}
+
+// dummySink records all values written using their path as key.
+type dummySink struct {
+	Values map[string]any
+	Path   string
+
+	// number of elements handed out by Element, shared between copies
+	elements *int
+}
+
+func (d dummySink) SetBool(value bool) error {
+	d.Values[d.Path] = value
+	return nil
+}
+
+func (d dummySink) SetInt(value int64) error {
+	d.Values[d.Path] = value
+	return nil
+}
+
+func (d dummySink) SetUint(value uint64) error {
+	d.Values[d.Path] = value
+	return nil
+}
+
+func (d dummySink) SetFloat(value float64) error {
+	d.Values[d.Path] = value
+	return nil
+}
+
+func (d dummySink) SetString(value string) error {
+	d.Values[d.Path] = value
+	return nil
+}
+
+func (d dummySink) Child(key string) (Sink, error) {
+	return dummySink{Values: d.Values, Path: d.Path + "." + key, elements: new(int)}, nil
+}
+
+func (d dummySink) Element() (Sink, error) {
+	if d.elements == nil {
+		return nil, ErrNotSupported
+	}
+
+	idx := *d.elements
+	*d.elements += 1
+
+	return d.Child(strconv.Itoa(idx))
+}
+
+// binarySink records all sized values written.
+type binarySink struct {
+	EmptySink
+	Values []any
+}
+
+func (b *binarySink) Child(key string) (Sink, error) {
+	return b, nil
+}
+
+func (b *binarySink) record(value any) error {
+	b.Values = append(b.Values, value)
+	return nil
+}
+
+func (b *binarySink) SetInt8(value int8) error       { return b.record(value) }
+func (b *binarySink) SetInt16(value int16) error     { return b.record(value) }
+func (b *binarySink) SetInt32(value int32) error     { return b.record(value) }
+func (b *binarySink) SetInt64(value int64) error     { return b.record(value) }
+func (b *binarySink) SetUint8(value uint8) error     { return b.record(value) }
+func (b *binarySink) SetUint16(value uint16) error   { return b.record(value) }
+func (b *binarySink) SetUint32(value uint32) error   { return b.record(value) }
+func (b *binarySink) SetUint64(value uint64) error   { return b.record(value) }
+func (b *binarySink) SetFloat32(value float32) error { return b.record(value) }
+func (b *binarySink) SetFloat64(value float64) error { return b.record(value) }
+
+func TestMarshalOmitZero(t *testing.T) {
+	type Struct struct {
+		Count   int       `json:"count,omitzero"`
+		Name    string    `json:"name,omitzero"`
+		Created time.Time `json:"created,omitzero"`
+		Kept    int       `json:"kept"`
+	}
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+	require.NoError(t, Marshal(Struct{Name: "a"}, sink))
+	require.Equal(t, sink.Values, map[string]any{"$.name": "a", "$.kept": int64(0)})
+}
@@ -0,0 +1,121 @@
+package unravel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EnumError is returned if a field tagged with `enum:"..."` is decoded
+// to a value that is not in the set of allowed values.
+type EnumError struct {
+	Value   string
+	Allowed []string
+}
+
+func (e EnumError) Error() string {
+	return fmt.Sprintf("value %q is not allowed, must be one of: %s", e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// makeEnumCheck returns a function that verifies that a value of the given type is one
+// of the comma separated allowed values. Supported are strings and integers, pointers
+// to them and slices or arrays of them.
+func makeEnumCheck(ty reflect.Type, tag string) (func(reflect.Value) error, error) {
+	allowed := strings.Split(tag, ",")
+	for idx, value := range allowed {
+		allowed[idx] = strings.TrimSpace(value)
+	}
+
+	switch ty.Kind() {
+	case reflect.Pointer:
+		check, err := makeEnumCheck(ty.Elem(), tag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(value reflect.Value) error {
+			if value.IsNil() {
+				return nil
+			}
+
+			return check(value.Elem())
+		}, nil
+
+	case reflect.Slice, reflect.Array:
+		check, err := makeEnumCheck(ty.Elem(), tag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(value reflect.Value) error {
+			for idx := range value.Len() {
+				if err := check(value.Index(idx)); err != nil {
+					return fmt.Errorf("element idx=%d: %w", idx, err)
+				}
+			}
+
+			return nil
+		}, nil
+
+	case reflect.String:
+		// no need to normalize
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		for idx, value := range allowed {
+			intValue, err := strconv.ParseInt(value, 10, ty.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum value %q for %q: %w", value, ty, err)
+			}
+
+			allowed[idx] = strconv.FormatInt(intValue, 10)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		for idx, value := range allowed {
+			uintValue, err := strconv.ParseUint(value, 10, ty.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum value %q for %q: %w", value, ty, err)
+			}
+
+			allowed[idx] = strconv.FormatUint(uintValue, 10)
+		}
+
+	default:
+		return nil, fmt.Errorf("enum requires a string or integer type, got %q", ty)
+	}
+
+	check := func(value reflect.Value) error {
+		var text string
+
+		switch {
+		case value.Kind() == reflect.String:
+			text = value.String()
+		case value.CanInt():
+			text = strconv.FormatInt(value.Int(), 10)
+		default:
+			text = strconv.FormatUint(value.Uint(), 10)
+		}
+
+		if !slices.Contains(allowed, text) {
+			return EnumError{Value: text, Allowed: allowed}
+		}
+
+		return nil
+	}
+
+	return check, nil
+}
+
+// withEnumCheck verifies the field value after it was set.
+func withEnumCheck(check func(reflect.Value) error, setter fieldSetter) fieldSetter {
+	return func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		if err := setter(ctx, source, structValue, fieldValue); err != nil {
+			return err
+		}
+
+		return check(fieldValue)
+	}
+}
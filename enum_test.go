@@ -0,0 +1,43 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEnumTag(t *testing.T) {
+	type Config struct {
+		Level    string   `json:"level" enum:"debug, info, warn"`
+		Mode     *string  `json:"mode" enum:"fast,slow"`
+		Tags     []string `json:"tags" enum:"a,b"`
+		Priority uint8    `json:"priority" enum:"1,2,3"`
+	}
+
+	value, err := UnmarshalNew[Config](SourceOf(map[string]any{
+		"level":    "info",
+		"tags":     []string{"a", "b", "a"},
+		"priority": 2,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, value, Config{Level: "info", Tags: []string{"a", "b", "a"}, Priority: 2})
+
+	_, err = UnmarshalNew[Config](SourceOf(map[string]any{"level": "verbose"}))
+	require.ErrorAs(t, err, &EnumError{})
+	require.ErrorContains(t, err, `value "verbose" is not allowed, must be one of: debug, info, warn`)
+
+	_, err = UnmarshalNew[Config](SourceOf(map[string]any{"mode": "medium"}))
+	require.ErrorAs(t, err, &EnumError{})
+
+	_, err = UnmarshalNew[Config](SourceOf(map[string]any{"tags": []string{"a", "c"}}))
+	require.ErrorAs(t, err, &EnumError{})
+
+	_, err = UnmarshalNew[Config](SourceOf(map[string]any{"priority": 4}))
+	require.ErrorAs(t, err, &EnumError{})
+
+	type Invalid struct {
+		Value int `enum:"one,two"`
+	}
+
+	_, err = UnmarshalNew[Invalid](SourceOf(map[string]any{}))
+	require.ErrorContains(t, err, "invalid enum value")
+}
@@ -0,0 +1,328 @@
+package unravel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DecodeError describes the failure to decode the value at a path within the [Source].
+// It is returned by [Decoder.Unmarshal] if a value could not be decoded, while errors
+// in the setup of the decoder, e.g. due to unsupported types or invalid struct tags,
+// are returned as is.
+//
+// Use [errors.As] to get the path of the value that failed:
+//
+//	var decodeErr *unravel.DecodeError
+//	if errors.As(err, &decodeErr) {
+//	    fmt.Printf("invalid value at %s\n", decodeErr.Path)
+//	}
+type DecodeError struct {
+	// A JSONPath like locator of the value, e.g. `$.items[3].price`. Struct fields
+	// and map keys are given by the key used in the source.
+	Path string
+
+	// The type of the value that failed to decode. Nil if the type is not known, e.g.
+	// for the elements decoded by an [ElementAppender].
+	TargetType reflect.Type
+
+	// The error that caused the failure.
+	Cause error
+}
+
+func (e *DecodeError) Error() string {
+	if e.TargetType == nil {
+		return fmt.Sprintf("decode %s: %s", e.Path, e.Cause)
+	}
+
+	return fmt.Sprintf("decode %s into %q: %s", e.Path, e.TargetType, e.Cause)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Cause
+}
+
+// DecodeErrors holds all failures collected by a [Decoder] using [Decoder.CollectErrors].
+// Like errors created using [errors.Join], it can be inspected using [errors.Is] and
+// [errors.As], which look at each of the failures.
+type DecodeErrors []*DecodeError
+
+func (e DecodeErrors) Error() string {
+	messages := make([]string, len(e))
+	for idx, err := range e {
+		messages[idx] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e DecodeErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for idx, err := range e {
+		errs[idx] = err
+	}
+
+	return errs
+}
+
+// CollectErrors returns a [Decoder] that continues decoding after a field, element or map
+// value failed to decode, leaving the failed value untouched. All failures are returned
+// as [DecodeErrors] once decoding finished. This gives complete feedback when validating
+// user submitted forms or configuration files. Validation of a struct, see [Validator],
+// is skipped if any of its fields failed.
+func (d *Decoder) CollectErrors() *Decoder {
+	if d.collectErrors {
+		return d
+	}
+
+	derived := d.clone()
+	derived.collectErrors = true
+	return derived
+}
+
+// collectError adds the failures within err to collected. If the decoder does not
+// collect errors, err is returned to stop decoding.
+func (d *Decoder) collectError(collected *DecodeErrors, err error) error {
+	if !d.collectErrors {
+		return err
+	}
+
+	var decodeErrs DecodeErrors
+	if errors.As(err, &decodeErrs) {
+		*collected = append(*collected, decodeErrs...)
+		return nil
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		*collected = append(*collected, decodeErr)
+		return nil
+	}
+
+	return err
+}
+
+// withPath prepends the segment to the path of the [DecodeError] within err. If err does
+// not contain a DecodeError yet, a new one is created for a value of the given type.
+func withPath(err error, segment string, target reflect.Type) error {
+	var decodeErrs DecodeErrors
+	if errors.As(err, &decodeErrs) {
+		for _, decodeErr := range decodeErrs {
+			decodeErr.Path = segment + strings.TrimPrefix(decodeErr.Path, "$")
+		}
+
+		return decodeErrs
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		// the error might come from a nested call to Unmarshal
+		decodeErr.Path = segment + strings.TrimPrefix(decodeErr.Path, "$")
+		return decodeErr
+	}
+
+	return &DecodeError{Path: segment, TargetType: target, Cause: err}
+}
+
+// keySegment returns the path segment of a key, e.g. `.name` or `["first name"]`.
+func keySegment(key string) string {
+	if isIdentifier(key) {
+		return "." + key
+	}
+
+	return "[" + strconv.Quote(key) + "]"
+}
+
+// indexSegment returns the path segment of an element of a list, e.g. `[3]`.
+func indexSegment(idx int) string {
+	return "[" + strconv.Itoa(idx) + "]"
+}
+
+// keySegmentOf returns the path segment of a key given as [Source].
+func keySegmentOf(keySource Source) string {
+	key, err := keySource.String()
+	if err != nil {
+		return "[?]"
+	}
+
+	return keySegment(key)
+}
+
+// mapKeySegment returns the path segment of the key of a map.
+func mapKeySegment(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return keySegment(key.String())
+	}
+
+	return fmt.Sprintf("[%v]", key)
+}
+
+func isIdentifier(key string) bool {
+	for idx, r := range key {
+		if r != '_' && !unicode.IsLetter(r) && (idx == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return key != ""
+}
+
+// UnmarshalTypeError describes a value of the [Source] that is not appropriate for the Go
+// type it is decoded into. It mirrors [encoding/json.UnmarshalTypeError], so that code
+// inspecting errors of encoding/json can handle errors of this package the same way.
+type UnmarshalTypeError struct {
+	// Description of the value, e.g. "string" or "number"
+	Value string
+
+	// The Go type the value could not be decoded into
+	Type reflect.Type
+
+	// The name of the struct type containing the field, if any
+	Struct string
+
+	// The full path from the root struct to the field, e.g. "items.price"
+	Field string
+
+	// The error returned by the Source
+	Err error
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	if e.Struct != "" || e.Field != "" {
+		return fmt.Sprintf("cannot unmarshal %s into Go struct field %s.%s of type %s: %s", e.Value, e.Struct, e.Field, e.Type, e.Err)
+	}
+
+	return fmt.Sprintf("cannot unmarshal %s into Go value of type %s: %s", e.Value, e.Type, e.Err)
+}
+
+func (e *UnmarshalTypeError) Unwrap() error {
+	return e.Err
+}
+
+// typeErrorOf returns an [UnmarshalTypeError] if err indicates that the value of the source
+// can not be represented by the target type. Other errors are returned as is.
+func typeErrorOf(source Source, target reflect.Type, err error) error {
+	if !errors.Is(err, ErrNotSupported) && !errors.Is(err, strconv.ErrSyntax) {
+		return err
+	}
+
+	return &UnmarshalTypeError{Value: describeValue(source), Type: target, Err: err}
+}
+
+// describeValue returns a description of the value of a source, like encoding/json.
+func describeValue(source Source) string {
+	if kindSource, ok := source.(KindSource); ok {
+		switch kindSource.Kind() {
+		case KindNull:
+			return "null"
+		case KindBool:
+			return "bool"
+		case KindNumber:
+			return "number"
+		case KindString:
+			return "string"
+		case KindObject:
+			return "object"
+		case KindArray:
+			return "array"
+		}
+	}
+
+	if text, err := source.String(); err == nil {
+		return "string " + strconv.Quote(text)
+	}
+
+	return "value"
+}
+
+// OverflowError describes an integer value that is out of the range of the Go type
+// it is decoded into. It wraps [strconv.ErrRange].
+type OverflowError struct {
+	// The value, an int64 or uint64. Nil if the value does not fit into either of them.
+	Value any
+
+	// The Go type the value could not be decoded into
+	TargetType reflect.Type
+
+	// The full path from the root struct to the field, e.g. "items.count".
+	// Empty if the value is not decoded into a struct field.
+	Field string
+}
+
+func (e *OverflowError) Error() string {
+	subject := "value"
+	if e.Field != "" {
+		subject = e.Field
+	}
+
+	if e.Value == nil {
+		return fmt.Sprintf("%s exceeds %s", subject, e.TargetType)
+	}
+
+	return fmt.Sprintf("%s %v exceeds %s", subject, e.Value, e.TargetType)
+}
+
+func (e *OverflowError) Unwrap() error {
+	return strconv.ErrRange
+}
+
+// overflowErrorOf returns an [OverflowError] if err is a range error of the strconv
+// package, e.g. returned by [StringSource]. Returns nil for all other errors.
+func overflowErrorOf(target reflect.Type, err error) *OverflowError {
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || !errors.Is(numErr.Err, strconv.ErrRange) {
+		return nil
+	}
+
+	overflowErr := &OverflowError{TargetType: target}
+
+	if intValue, err := strconv.ParseInt(numErr.Num, 10, 64); err == nil {
+		overflowErr.Value = intValue
+	} else if uintValue, err := strconv.ParseUint(numErr.Num, 10, 64); err == nil {
+		overflowErr.Value = uintValue
+	}
+
+	return overflowErr
+}
+
+// addFieldContext adds the struct field to all [UnmarshalTypeError] and [OverflowError]
+// values within err. The innermost struct sets the struct name, outer structs extend
+// the path of the field.
+func addFieldContext(err error, structType reflect.Type, fieldName string) {
+	var decodeErrs DecodeErrors
+	if errors.As(err, &decodeErrs) {
+		for _, decodeErr := range decodeErrs {
+			addFieldContext(decodeErr, structType, fieldName)
+		}
+
+		return
+	}
+
+	var overflowErr *OverflowError
+	if errors.As(err, &overflowErr) {
+		overflowErr.Field = joinFieldPath(fieldName, overflowErr.Field)
+		return
+	}
+
+	var typeErr *UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return
+	}
+
+	if typeErr.Struct == "" {
+		typeErr.Struct = structType.Name()
+	}
+
+	typeErr.Field = joinFieldPath(fieldName, typeErr.Field)
+}
+
+func joinFieldPath(parent, child string) string {
+	if child == "" {
+		return parent
+	}
+
+	return parent + "." + child
+}
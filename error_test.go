@@ -0,0 +1,215 @@
+package unravel
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecodeErrorPath(t *testing.T) {
+	type Item struct {
+		Price float64 `json:"price"`
+	}
+
+	type Order struct {
+		Items    []Item         `json:"items"`
+		Labels   map[string]int `json:"labels"`
+		Position [2]int         `json:"position"`
+		Nested   map[int]*Item  `json:"nested"`
+		Owner    string         `json:"owner" unravel:"required"`
+	}
+
+	cases := []struct {
+		Value      map[string]any
+		Path       string
+		TargetType reflect.Type
+	}{
+		{
+			Value:      map[string]any{"owner": "me", "items": []any{map[string]any{"price": 1}, map[string]any{"price": "free"}}},
+			Path:       "$.items[1].price",
+			TargetType: reflect.TypeFor[float64](),
+		},
+		{
+			Value:      map[string]any{"owner": "me", "labels": map[string]any{"first label": "x"}},
+			Path:       `$.labels["first label"]`,
+			TargetType: reflect.TypeFor[int](),
+		},
+		{
+			Value:      map[string]any{"owner": "me", "position": []any{1, "two"}},
+			Path:       "$.position[1]",
+			TargetType: reflect.TypeFor[int](),
+		},
+		{
+			Value:      map[string]any{"owner": "me", "nested": map[int]any{7: map[string]any{"price": "x"}}},
+			Path:       "$.nested[7].price",
+			TargetType: reflect.TypeFor[float64](),
+		},
+		{
+			Value:      map[string]any{},
+			Path:       "$.owner",
+			TargetType: reflect.TypeFor[string](),
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := UnmarshalNew[Order](SourceOf(tc.Value))
+
+		var decodeErr *DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		require.Equal(t, decodeErr.Path, tc.Path)
+		require.Equal(t, decodeErr.TargetType, tc.TargetType)
+	}
+
+	// the cause is kept in the chain
+	_, err := UnmarshalNew[Order](SourceOf(map[string]any{}))
+	require.ErrorIs(t, err, ErrNoValue)
+	require.EqualError(t, err, `decode $.owner into "string": no value`)
+
+	// errors of the root value have the root path
+	_, err = UnmarshalNew[int](StringSource("x"))
+	require.ErrorIs(t, err, strconv.ErrSyntax)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, decodeErr.Path, "$")
+}
+
+func TestDecoderCollectErrors(t *testing.T) {
+	type Item struct {
+		Name  string `json:"name" unravel:"required"`
+		Price int    `json:"price"`
+	}
+
+	type Form struct {
+		Email string         `json:"email" unravel:"required"`
+		Age   int            `json:"age"`
+		Items []Item         `json:"items"`
+		Limit map[string]int `json:"limits"`
+	}
+
+	source := SourceOf(map[string]any{
+		"age": "old",
+		"items": []any{
+			map[string]any{"name": "a", "price": 1},
+			map[string]any{"price": "free"},
+		},
+		"limits": map[string]any{"cpu": 2, "memory": "lots"},
+	})
+
+	form, err := UnmarshalNewWith[Form](NewDecoder().CollectErrors(), source)
+
+	var decodeErrs DecodeErrors
+	require.ErrorAs(t, err, &decodeErrs)
+
+	var paths []string
+	for _, decodeErr := range decodeErrs {
+		paths = append(paths, decodeErr.Path)
+	}
+
+	require.Equal(t, paths, []string{"$.email", "$.age", "$.items[1].name", "$.items[1].price", "$.limits.memory"})
+	require.ErrorIs(t, err, ErrNoValue)
+
+	// values that decoded successfully are kept
+	require.Equal(t, form.Items[0], Item{Name: "a", Price: 1})
+	require.Equal(t, form.Limit, map[string]int{"cpu": 2})
+
+	// by default, decoding stops at the first failure
+	_, err = UnmarshalNew[Form](source)
+	require.False(t, errors.As(err, &decodeErrs))
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, decodeErr.Path, "$.email")
+}
+
+func TestUnmarshalTypeError(t *testing.T) {
+	type Item struct {
+		Price float64 `json:"price"`
+	}
+
+	type Order struct {
+		Items []Item `json:"items"`
+		Count int    `json:"count"`
+	}
+
+	_, err := UnmarshalNew[Order](JSONStreamSource(strings.NewReader(`{"items": [{"price": "free"}]}`)))
+
+	var typeErr *UnmarshalTypeError
+	require.ErrorAs(t, err, &typeErr)
+	require.Equal(t, typeErr.Value, "string")
+	require.Equal(t, typeErr.Type, reflect.TypeFor[float64]())
+	require.Equal(t, typeErr.Struct, "Item")
+	require.Equal(t, typeErr.Field, "items.price")
+	require.ErrorIs(t, err, ErrNotSupported)
+
+	// strings that can not be parsed
+	_, err = UnmarshalNew[Order](SourceOf(map[string]any{"count": "many"}))
+	require.ErrorAs(t, err, &typeErr)
+	require.Equal(t, typeErr.Value, "string")
+	require.Equal(t, typeErr.Field, "count")
+	require.EqualError(t, typeErr, `cannot unmarshal string into Go struct field Order.count of type int: get int value: not supported`)
+
+	// values that are not part of a struct
+	_, err = UnmarshalNew[[]int](StringSource("many"))
+	require.ErrorAs(t, err, &typeErr)
+	require.Equal(t, typeErr.Value, `string "many"`)
+	require.Equal(t, typeErr.Struct, "")
+	require.Equal(t, typeErr.Type, reflect.TypeFor[[]int]())
+}
+
+func TestOverflowError(t *testing.T) {
+	type Person struct {
+		Age   uint8 `json:"age"`
+		Score int8  `json:"score"`
+	}
+
+	type Team struct {
+		Members []Person `json:"members"`
+	}
+
+	_, err := UnmarshalNew[Person](SourceOf(map[string]any{"age": 300}))
+
+	var overflowErr *OverflowError
+	require.ErrorAs(t, err, &overflowErr)
+	require.Equal(t, overflowErr.Value, uint64(300))
+	require.Equal(t, overflowErr.TargetType, reflect.TypeFor[uint8]())
+	require.Equal(t, overflowErr.Field, "age")
+	require.EqualError(t, overflowErr, "age 300 exceeds uint8")
+	require.ErrorIs(t, err, strconv.ErrRange)
+
+	_, err = UnmarshalNew[Team](SourceOf(map[string]any{"members": []any{map[string]any{"score": -200}}}))
+	require.ErrorAs(t, err, &overflowErr)
+	require.Equal(t, overflowErr.Value, int64(-200))
+	require.Equal(t, overflowErr.Field, "members.score")
+
+	// overflow is not a type error
+	var typeErr *UnmarshalTypeError
+	require.False(t, errors.As(err, &typeErr))
+
+	// values parsed from strings
+	_, err = UnmarshalNew[int8](StringSource("-200"))
+	require.ErrorAs(t, err, &overflowErr)
+	require.Equal(t, overflowErr.Value, int64(-200))
+	require.EqualError(t, overflowErr, "value -200 exceeds int8")
+}
+
+func TestDecodeErrorPathOfKeysAndAny(t *testing.T) {
+	type Order struct {
+		Quantities map[int]int `json:"quantities"`
+		Extra      any         `json:"extra"`
+	}
+
+	_, err := UnmarshalNew[Order](JSONStreamSource(strings.NewReader(`{"quantities": {"first": 1}}`)))
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, decodeErr.Path, "$.quantities.first")
+	require.Equal(t, decodeErr.TargetType, reflect.TypeFor[int]())
+
+	_, err = UnmarshalNew[Order](SourceOf(map[string]any{"extra": map[string]any{"values": []any{1, make(chan int)}}}))
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, decodeErr.Path, "$.extra.values[1]")
+}
@@ -6,116 +6,9 @@ import (
 	"encoding/binary"
 	"github.com/go-gum/unravel"
 	"github.com/stretchr/testify/require"
-	"io"
-	"iter"
 	"testing"
 )
 
-type binarySource struct {
-	unravel.EmptySource
-	r io.Reader
-}
-
-func (b binarySource) Iter() (iter.Seq[unravel.Source], error) {
-	it := func(yield func(unravel.Source) bool) {
-		for {
-			if !yield(b) {
-				break
-			}
-		}
-	}
-
-	return it, nil
-}
-
-func (b binarySource) Get(key string) (unravel.Source, error) {
-	return b, nil
-}
-
-func (b binarySource) KeyValues() (iter.Seq2[unravel.Source, unravel.Source], error) {
-	return nil, unravel.ErrNotSupported
-}
-
-func (b binarySource) Int8() (int8, error) {
-	var buf [1]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return int8(buf[0]), nil
-}
-
-func (b binarySource) Int16() (int16, error) {
-	var buf [2]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return int16(binary.LittleEndian.Uint16(buf[:])), nil
-}
-
-func (b binarySource) Int32() (int32, error) {
-	var buf [4]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return int32(binary.LittleEndian.Uint32(buf[:])), nil
-}
-
-func (b binarySource) Int64() (int64, error) {
-	var buf [8]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return int64(binary.LittleEndian.Uint64(buf[:])), nil
-}
-
-func (b binarySource) Uint8() (uint8, error) {
-	var buf [1]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return buf[0], nil
-}
-
-func (b binarySource) Uint16() (uint16, error) {
-	var buf [2]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return binary.LittleEndian.Uint16(buf[:]), nil
-}
-
-func (b binarySource) Uint32() (uint32, error) {
-	var buf [4]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return binary.LittleEndian.Uint32(buf[:]), nil
-}
-
-func (b binarySource) Uint64() (uint64, error) {
-	var buf [8]byte
-	if _, err := b.r.Read(buf[:]); err != nil {
-		return 0, err
-	}
-
-	return binary.LittleEndian.Uint64(buf[:]), nil
-}
-
-func (b binarySource) Float32() (float32, error) {
-	return 0, unravel.ErrNotSupported
-}
-
-func (b binarySource) Float64() (float64, error) {
-	return 0, unravel.ErrNotSupported
-}
-
 func TestBinarySource(t *testing.T) {
 	var values []byte
 	for idx := range 256 {
@@ -146,7 +39,7 @@ func TestBinarySource(t *testing.T) {
 		Uint64: 0x1d1c1b1a19181716,
 	}
 
-	source := binarySource{r: bytes.NewReader(values)}
+	source := unravel.BinaryReaderSource(bytes.NewReader(values), binary.LittleEndian)
 	parsed, err := unravel.UnmarshalNew[Struct](source)
 	require.Equal(t, err, nil)
 	require.Equal(t, parsed, expected)
@@ -183,7 +76,7 @@ func TestDecodeBitmapHeader(t *testing.T) {
 
 	// a 3x5 px bitmap header
 	buf, _ := base64.StdEncoding.DecodeString(`Qk3GAAAAAAAAAIoAAAB8AAAAAwAAAAUAAAABABgAAAAAADwAAAAAAAAAAAAAAAAAAAAAAAAAAAD/AAD/AAD/AAAAAAAA/0JHUnOPwvUoUbgeFR6F6wEzMzMTZmZmJmZmZgaZmZkJPQrXAyhcjzIAAAAAAAAAAAAAAAAEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA`)
-	source := binarySource{r: bytes.NewReader(buf)}
+	source := unravel.BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian)
 
 	parsed, err := unravel.UnmarshalNew[Header](source)
 	require.Equal(t, err, nil)
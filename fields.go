@@ -1,8 +1,12 @@
 package unravel
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -10,9 +14,48 @@ type field struct {
 	Name  string
 	Type  reflect.Type
 	Index []int
+
+	// The name was given explicitly in a struct tag
+	Explicit bool
+
+	// The full struct tag of the field
+	Tag reflect.StructTag
+}
+
+// FieldConflict describes a name shared by multiple fields of a struct, none of which
+// wins. Like in encoding/json, all of the conflicting fields are ignored.
+type FieldConflict struct {
+	Name string
+
+	// The Go paths of the conflicting fields, e.g. "First.A"
+	Fields []string
+}
+
+func (c FieldConflict) String() string {
+	return fmt.Sprintf("%q is used by %s", c.Name, strings.Join(c.Fields, ", "))
+}
+
+// fieldsToSerialize returns the fields of a struct type, following the rules of
+// encoding/json. Names are taken from the first of the struct tags that gives a name.
+func fieldsToSerialize(ty reflect.Type, structTags ...string) []field {
+	return resolveFields(ty, structTags).Fields
+}
+
+// resolvedFields holds the fields of a struct type, as well as the fields that were dropped.
+type resolvedFields struct {
+	Fields []field
+
+	// Go paths of fields skipped by a struct tag, unexported or hidden by a field
+	// with the same name on a lower nesting level.
+	Skipped []string
+
+	// Fields dropped due to naming conflicts.
+	Conflicts []FieldConflict
 }
 
-func fieldsToSerialize(ty reflect.Type, structTag string) []field {
+// resolveFields works like fieldsToSerialize, but also returns the fields
+// that were skipped or dropped due to naming conflicts.
+func resolveFields(ty reflect.Type, structTags []string) resolvedFields {
 	if ty.Kind() != reflect.Struct {
 		panic("not a struct")
 	}
@@ -31,39 +74,64 @@ func fieldsToSerialize(ty reflect.Type, structTag string) []field {
 	// initialize queue to walk
 	queue := []Queued{{Type: ty}}
 
+	// depth at which a struct type was first walked, to stop on recursive embedding
+	visited := map[reflect.Type]int{}
+
 	candidates := map[string][]Candidate{}
 
 	var order []string
 
+	var resolved resolvedFields
+
 	for len(queue) > 0 {
 		item := queue[0]
 		queue = queue[1:]
 
+		// fields of a type walked on a lower nesting level always win. on the same
+		// level, the type is walked again so that its fields conflict
+		depth := len(item.ParentIndex)
+		if visitedDepth, ok := visited[item.Type]; ok && visitedDepth < depth {
+			continue
+		}
+
+		visited[item.Type] = depth
+
 		for idx := range item.Type.NumField() {
 			fi := item.Type.Field(idx)
-			if !fi.IsExported() {
-				continue
-			}
 
-			name, explicit := nameOf(fi, structTag)
+			// derive index of this one. ensure we allocate a new slice by setting cap to
+			// the length of the parents index
+			parent := item.ParentIndex
+			index := append(parent[:len(parent):len(parent)], fi.Index...)
+
+			name, explicit := nameOf(fi, structTags)
 			if name == "" {
 				// this one is skipped
+				resolved.Skipped = append(resolved.Skipped, fieldPathOf(ty, index))
 				continue
 			}
 
-			// derive index of this one. ensure we allocate a new slice by setting cap to
-			// the length of the parents index
-			parent := item.ParentIndex
-			index := append(parent[:len(parent):len(parent)], fi.Index...)
+			// like encoding/json, the exported fields of an embedded struct
+			// are promoted, even if the embedded struct type is unexported
+			if !fi.IsExported() && !(fi.Anonymous && !explicit) {
+				resolved.Skipped = append(resolved.Skipped, fieldPathOf(ty, index))
+				continue
+			}
 
 			if fi.Anonymous && !explicit {
-				// this is an embedded field. skip if not struct
-				if fi.Type.Kind() != reflect.Struct {
+				// this is an embedded field. skip if not struct or pointer to struct
+				embedded := fi.Type
+				if embedded.Kind() == reflect.Pointer {
+					embedded = embedded.Elem()
+				}
+
+				if embedded.Kind() != reflect.Struct {
+					resolved.Skipped = append(resolved.Skipped, fieldPathOf(ty, index))
 					continue
 				}
 
 				// queue for later analysis
-				queue = append(queue, Queued{fi.Type, index})
+				queue = append(queue, Queued{embedded, index})
 				continue
 			}
 
@@ -75,16 +143,16 @@ func fieldsToSerialize(ty reflect.Type, structTag string) []field {
 				Name:     name,
 				Explicit: explicit,
 				Field: field{
-					Name:  name,
-					Index: index,
-					Type:  fi.Type,
+					Name:     name,
+					Index:    index,
+					Type:     fi.Type,
+					Tag:      fi.Tag,
+					Explicit: explicit,
 				},
 			})
 		}
 	}
 
-	var fields []field
-
 	for _, name := range order {
 		candidates := candidates[name]
 
@@ -110,54 +178,267 @@ func fieldsToSerialize(ty reflect.Type, structTag string) []field {
 			}
 		}
 
+		// candidates on a deeper nesting level are hidden
+		for _, candidate := range candidates[len(visible):] {
+			resolved.Skipped = append(resolved.Skipped, fieldPathOf(ty, candidate.Field.Index))
+		}
+
 		// if we have exactly one visible item, that one always wins
 		if len(visible) == 1 {
-			fields = append(fields, visible[0].Field)
+			resolved.Fields = append(resolved.Fields, visible[0].Field)
 			continue
 		}
 
-		// keep only explicit candidates
-		explicit := slices.DeleteFunc(visible, func(c Candidate) bool { return !c.Explicit })
-
 		// if we have exactly one explicit item, that one wins
-		if len(explicit) == 1 {
-			fields = append(fields, explicit[0].Field)
+		explicitCount := 0
+		for _, candidate := range visible {
+			if candidate.Explicit {
+				explicitCount++
+			}
+		}
+
+		if explicitCount == 1 {
+			for _, candidate := range visible {
+				if candidate.Explicit {
+					resolved.Fields = append(resolved.Fields, candidate.Field)
+				} else {
+					resolved.Skipped = append(resolved.Skipped, fieldPathOf(ty, candidate.Field.Index))
+				}
+			}
+
+			continue
+		}
+
+		// No one single candidate found. We ignore this fields and do not raise an
+		// error, but record the conflict for Decoder.StrictFields
+		conflict := FieldConflict{Name: name}
+		for _, candidate := range visible {
+			conflict.Fields = append(conflict.Fields, fieldPathOf(ty, candidate.Field.Index))
+		}
+
+		resolved.Conflicts = append(resolved.Conflicts, conflict)
+	}
+
+	return resolved
+}
+
+// fieldPathOf returns the Go path of the field with the given index, e.g. "First.A".
+func fieldPathOf(ty reflect.Type, index []int) string {
+	var names []string
+
+	for idx := range index {
+		names = append(names, ty.FieldByIndex(index[:idx+1]).Name)
+	}
+
+	return strings.Join(names, ".")
+}
+
+// fieldByIndexAlloc works like [reflect.Value.FieldByIndex] but allocates
+// nil embedded struct pointers on the way to the field. Pointers to unexported
+// struct types can not be allocated, this results in an error.
+func fieldByIndexAlloc(value reflect.Value, index []int) (reflect.Value, error) {
+	for idx, fieldIdx := range index {
+		if idx > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				if !value.CanSet() {
+					return reflect.Value{}, fmt.Errorf("set embedded pointer to unexported struct %q: %w", value.Type().Elem(), ErrNotSupported)
+				}
+
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(fieldIdx)
+	}
+
+	return value, nil
+}
+
+// fieldLookupOf returns a function looking up the source of a field. If the field
+// has a default value, it is used if the lookup finds no value or a null value.
+func (d *Decoder) fieldLookupOf(field field, fieldOpts fieldOptions) (func(context.Context, Source) (Source, error), error) {
+	lookup, err := plainFieldLookupOf(field, fieldOpts, d.keyNormalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.emptyStringAsNoValue {
+		lookup = withEmptyStringAsNoValue(lookup)
+	}
+
+	if fieldOpts.Default == nil {
+		return lookup, nil
+	}
+
+	defaultSource := StringSource(*fieldOpts.Default)
+
+	lookupWithDefault := func(ctx context.Context, source Source) (Source, error) {
+		child, err := lookup(ctx, source)
+		if errors.Is(err, ErrNoValue) || err == nil && isNull(child) {
+			return defaultSource, nil
+		}
+
+		return child, err
+	}
+
+	return lookupWithDefault, nil
+}
+
+// withEmptyStringAsNoValue wraps a lookup to report [ErrNoValue] for empty strings.
+func withEmptyStringAsNoValue(lookup func(context.Context, Source) (Source, error)) func(context.Context, Source) (Source, error) {
+	return func(ctx context.Context, source Source) (Source, error) {
+		child, err := lookup(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+
+		if text, ok := stringValueOf(child); ok && text == "" {
+			return nil, ErrNoValue
+		}
+
+		return child, nil
+	}
+}
+
+// stringValueOf returns the value of a source holding a string. A source implementing
+// [KindSource] is only read if it reports [KindString], any other source only if it is
+// a [StringSource]. Accessors of a source may consume its value, e.g. of a binary
+// stream, so a source of unknown kind is not read ahead of its setter.
+func stringValueOf(source Source) (string, bool) {
+	if stringSource, ok := source.(StringSource); ok {
+		return string(stringSource), true
+	}
+
+	kindSource, ok := source.(KindSource)
+	if !ok || kindSource.Kind() != KindString {
+		return "", false
+	}
+
+	text, err := source.String()
+	if err != nil {
+		return "", false
+	}
+
+	return text, true
+}
+
+// plainFieldLookupOf returns a function looking up the source of a field. Fields tagged
+// with `idx:"2"` are looked up by position using [IndexSource], all other fields by
+// their name, followed by the aliases given in the `unravel` struct tag. If normalize
+// is not nil, names are compared with the keys of the source after normalizing both.
+func plainFieldLookupOf(field field, fieldOpts fieldOptions, normalize func(string) string) (func(context.Context, Source) (Source, error), error) {
+	if idxTag, ok := field.Tag.Lookup("idx"); ok {
+		idx, err := strconv.Atoi(idxTag)
+		if err != nil || idx < 0 {
+			return nil, fmt.Errorf("invalid idx tag %q", idxTag)
+		}
+
+		lookup := func(ctx context.Context, source Source) (Source, error) {
+			indexSource, ok := source.(IndexSource)
+			if !ok {
+				return nil, fmt.Errorf("index %d: %w", idx, ErrNotSupported)
+			}
+
+			return indexSource.Index(idx)
+		}
+
+		return lookup, nil
+	}
+
+	keys := append([]string{field.Name}, fieldOpts.Aliases...)
+
+	if normalize != nil {
+		lookup := func(ctx context.Context, source Source) (Source, error) {
+			return getFirst(ctx, normalizedSource{Source: source, ctx: ctx, normalize: normalize}, keys)
+		}
+
+		return lookup, nil
+	}
+
+	lookup := func(ctx context.Context, source Source) (Source, error) {
+		return getFirst(ctx, source, keys)
+	}
+
+	return lookup, nil
+}
+
+// getFirst returns the child of the first key that has a value in the source.
+func getFirst(ctx context.Context, source Source, keys []string) (Source, error) {
+	for idx, key := range keys {
+		child, err := getPath(ctx, source, key)
+		if errors.Is(err, ErrNoValue) && idx < len(keys)-1 {
 			continue
 		}
 
-		// No one single candidate found.
-		// We ignore this fields and do not raise an error.
+		return child, err
 	}
 
-	return fields
+	return nil, ErrNoValue
 }
 
-func nameOf(fi reflect.StructField, structTag string) (name string, explicit bool) {
-	// parse json struct tag to get renamed alias
-	tag := fi.Tag.Get(structTag)
+// getPath returns the child of the given key. If the source has no value for a key
+// containing dots, e.g. "payment.card.last4", the key is treated as a path and each
+// segment is looked up in the child of the previous segment.
+func getPath(ctx context.Context, source Source, key string) (Source, error) {
+	child, err := getContext(ctx, source, key)
+	if !errors.Is(err, ErrNoValue) || !strings.Contains(key, ".") {
+		return child, err
+	}
+
+	child = source
 
-	if tag == "" {
-		// tag is empty, take the original name
-		return fi.Name, false
+	for _, segment := range strings.Split(key, ".") {
+		child, err = getContext(ctx, child, segment)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if tag == "-" {
-		// return empty name indicate: skip this field
+	return child, nil
+}
+
+func nameOf(fi reflect.StructField, structTags []string) (name string, explicit bool) {
+	// a name given in the unravel struct tag takes precedence
+	unravelTag := fi.Tag.Get("unravel")
+	if unravelTag == "-" {
 		return "", true
 	}
 
-	idx := strings.IndexByte(tag, ',')
-	switch {
-	case idx == -1:
-		// no comma, take the full tag as explicit name
-		return tag, true
+	if name, _ := splitUnravelTag(unravelTag); name != "" {
+		return name, true
+	}
+
+	for _, structTag := range structTags {
+		// parse json struct tag to get renamed alias
+		tag := fi.Tag.Get(structTag)
 
-	case idx > 0:
-		// non empty alias, take up to comma
-		return tag[:idx], true
+		if tag == "" {
+			// tag is empty, try the next one
+			continue
+		}
+
+		if tag == "-" {
+			// return empty name indicate: skip this field. Like in
+			// encoding/json, the tag "-," names the field "-" instead
+			return "", true
+		}
 
-	default:
-		// no alias before the comma, keep field name
-		return fi.Name, false
+		idx := strings.IndexByte(tag, ',')
+		switch {
+		case idx == -1:
+			// no comma, take the full tag as explicit name
+			return tag, true
+
+		case idx > 0:
+			// non empty alias, take up to comma
+			return tag[:idx], true
+		}
+
+		// no alias before the comma, try the next one
 	}
+
+	// no tag gives a name, take the original name
+	return fi.Name, false
 }
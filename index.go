@@ -0,0 +1,114 @@
+package unravel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// indexedSourceOf returns the source as an [IndexSource] together with the number of its
+// elements, if the source implements both IndexSource and [LenSource] and knows its length.
+func indexedSourceOf(source Source) (IndexSource, int, bool) {
+	indexSource, ok := source.(IndexSource)
+	if !ok {
+		return nil, 0, false
+	}
+
+	lenSource, ok := source.(LenSource)
+	if !ok {
+		return nil, 0, false
+	}
+
+	length, ok := lenSource.Len()
+	if !ok {
+		return nil, 0, false
+	}
+
+	return indexSource, length, true
+}
+
+// setSliceIndexed appends the elements of an indexed source to the slice, looking up
+// each element by its position. Works like the setter returned by makeSetSliceOf otherwise.
+func (d *Decoder) setSliceIndexed(ctx context.Context, ty reflect.Type, elementSetter setter, indexSource IndexSource, length int, target reflect.Value) error {
+	if d.limits.MaxSliceLen > 0 && length > d.limits.MaxSliceLen {
+		return fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, d.limits.MaxSliceLen)
+	}
+
+	// the length of the slice before decoding, see SlicePolicy
+	initialLen := target.Len()
+
+	if target.Cap()-initialLen < length {
+		preallocated := reflect.MakeSlice(target.Type(), initialLen, initialLen+length)
+		reflect.Copy(preallocated, target)
+		target.Set(preallocated)
+	}
+
+	var collected DecodeErrors
+
+	for idx := range length {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		targetIdx := initialLen + idx
+
+		// grow the slice element by element, so that it only holds the
+		// elements decoded so far if decoding fails
+		target.SetLen(targetIdx + 1)
+
+		elementValue := target.Index(targetIdx)
+		elementValue.SetZero()
+
+		elementSource, err := indexSource.Index(idx)
+		if err == nil {
+			err = elementSetter(ctx, elementSource, elementValue)
+		}
+
+		if err != nil {
+			if err := d.collectError(&collected, withPath(err, indexSegment(targetIdx), ty.Elem())); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(collected) > 0 {
+		return collected
+	}
+
+	return nil
+}
+
+// setArrayIndexed decodes the elements of an indexed source into the array, looking up
+// each element by its position. Works like the setter returned by makeSetArray otherwise.
+func (d *Decoder) setArrayIndexed(ctx context.Context, ty reflect.Type, elementSetter setter, indexSource IndexSource, length int, target reflect.Value) error {
+	elementCount := ty.Len()
+
+	var collected DecodeErrors
+
+	for idx := range min(length, elementCount) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		elementSource, err := indexSource.Index(idx)
+		if err == nil {
+			err = elementSetter(ctx, elementSource, target.Index(idx))
+		}
+
+		if err != nil {
+			if err := d.collectError(&collected, withPath(err, indexSegment(idx), ty.Elem())); err != nil {
+				return err
+			}
+		}
+	}
+
+	if length > elementCount && d.warn != nil {
+		d.warn(Warning{Kind: WarningTruncated, Type: ty, Message: fmt.Sprintf("dropped elements after the first %d", elementCount)})
+	}
+
+	if len(collected) > 0 {
+		return collected
+	}
+
+	return nil
+}
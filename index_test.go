@@ -0,0 +1,62 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// indexedSource serves its elements by position only, it can not be iterated.
+type indexedSource struct {
+	EmptySource
+	elements []string
+}
+
+func (s indexedSource) Index(idx int) (Source, error) {
+	if idx < 0 || idx >= len(s.elements) {
+		return nil, ErrNoValue
+	}
+
+	return StringSource(s.elements[idx]), nil
+}
+
+func (s indexedSource) Len() (int, bool) {
+	return len(s.elements), true
+}
+
+func TestDecodeIndexedSource(t *testing.T) {
+	source := indexedSource{elements: []string{"1", "2", "3"}}
+
+	values, err := UnmarshalNew[[]int](source)
+	require.NoError(t, err)
+	require.Equal(t, values, []int{1, 2, 3})
+
+	// elements are appended like with Iter
+	values = []int{0}
+	err = NewDecoder().WithSlicePolicy(SliceAppend).Unmarshal(source, &values)
+	require.NoError(t, err)
+	require.Equal(t, values, []int{0, 1, 2, 3})
+
+	var warnings []Warning
+	dec := NewDecoder().WithWarnings(func(w Warning) { warnings = append(warnings, w) })
+
+	pair, err := UnmarshalNewWith[[2]int](dec, source)
+	require.NoError(t, err)
+	require.Equal(t, pair, [2]int{1, 2})
+	require.Len(t, warnings, 1)
+	require.Equal(t, warnings[0].Kind, WarningTruncated)
+
+	_, err = UnmarshalNewWith[[]int](NewDecoder().WithLimits(Limits{MaxSliceLen: 2}), source)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	invalid := indexedSource{elements: []string{"1", "x", "y"}}
+
+	_, err = UnmarshalNew[[]int](invalid)
+	require.ErrorContains(t, err, "$[1]")
+
+	_, err = UnmarshalNewWith[[3]int](NewDecoder().CollectErrors(), invalid)
+
+	var decodeErrs DecodeErrors
+	require.ErrorAs(t, err, &decodeErrs)
+	require.Len(t, decodeErrs, 2)
+	require.Equal(t, decodeErrs[1].Path, "$[2]")
+}
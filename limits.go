@@ -0,0 +1,118 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrMaxDepth is returned if recursive types are nested deeper than allowed,
+// see [Decoder.WithMaxDepth].
+var ErrMaxDepth = errors.New("max depth exceeded")
+
+// WithMaxDepth returns a [Decoder] that limits how often values of a recursive type may
+// be nested within each other, e.g. the chain of parents of a
+//
+//	type Commit struct {
+//	    Parent *Commit
+//	}
+//
+// A value nested deeper than maxDepth fails with [ErrMaxDepth]. Types that are not
+// recursive can only be nested as deep as their definition, which is not limited.
+// Use this option to decode sources of untrusted input, which could otherwise exhaust
+// the stack. A maxDepth of zero removes the limit for recursive types.
+//
+// Values decoded into an empty interface, e.g. `any` or `map[string]any`, recurse based
+// on the data. Objects and arrays within them may be nested maxDepth times, or 10000
+// times if the decoder has no maximum depth, like [encoding/json] allows.
+func (d *Decoder) WithMaxDepth(maxDepth int) *Decoder {
+	derived := d.clone()
+	derived.maxDepth = max(maxDepth, 0)
+	return derived
+}
+
+// makeSetNested returns a setter for a value of a recursive type nested within
+// another value of that type. The value is decoded by the decoder of the next
+// nesting level, each level has its own setters.
+func (d *Decoder) makeSetNested(ty reflect.Type) setter {
+	if d.depth >= d.maxDepth {
+		return func(ctx context.Context, source Source, target reflect.Value) error {
+			return fmt.Errorf("%w: %q nested more than %d times", ErrMaxDepth, ty, d.maxDepth)
+		}
+	}
+
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		deeper := d.deeperDecoder()
+
+		setter, err := deeper.setterOf(typeSet{}, ty)
+		if err != nil {
+			return err
+		}
+
+		return setter(ctx, source, target)
+	}
+}
+
+// deeperDecoder returns the decoder for the next nesting level of recursive types.
+func (d *Decoder) deeperDecoder() *Decoder {
+	for {
+		if deeper := d.deeper.Load(); deeper != nil {
+			return deeper
+		}
+
+		deeper := d.clone()
+		deeper.depth = d.depth + 1
+
+		if d.deeper.CompareAndSwap(nil, deeper) {
+			return deeper
+		}
+	}
+}
+
+// ErrLimitExceeded is returned if a value of the [Source] exceeds the [Limits]
+// of a [Decoder].
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits restrict the size of values decoded by a [Decoder], see [Decoder.WithLimits].
+// A limit of zero does not restrict the size.
+type Limits struct {
+	// The maximum number of elements decoded into a slice
+	MaxSliceLen int
+
+	// The maximum number of entries decoded into a map
+	MaxMapEntries int
+
+	// The maximum length of a string in bytes
+	MaxStringLen int
+}
+
+// WithLimits returns a [Decoder] that fails with [ErrLimitExceeded] if a value of the
+// [Source] exceeds the given limits. A [Source] may yield an endless sequence of elements
+// in [Source.Iter], e.g. when reading from a network stream. Use limits to decode
+// untrusted input without running out of memory. The limits also apply to values decoded
+// into an empty interface, a [Raw], a [sync.Map], a [container/list.List] or an [OrderedMap],
+// and to strings, byte slices and slices read from binary sources using the bin struct tag.
+func (d *Decoder) WithLimits(limits Limits) *Decoder {
+	derived := d.clone()
+	derived.limits = limits
+	return derived
+}
+
+// makeSetStringLimited returns a setter for strings that fails for strings
+// longer than maxLen bytes.
+func makeSetStringLimited(maxLen int) setter {
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		value, err := source.String()
+		if err != nil {
+			return typeErrorOf(source, target.Type(), fmt.Errorf("get string value: %w", err))
+		}
+
+		if len(value) > maxLen {
+			return fmt.Errorf("%w: string of %d bytes is longer than %d bytes", ErrLimitExceeded, len(value), maxLen)
+		}
+
+		target.SetString(value)
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package unravel
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDecoderWithMaxDepth(t *testing.T) {
+	type Commit struct {
+		Sha1   string  `json:"sha1"`
+		Parent *Commit `json:"parent"`
+	}
+
+	nested := func(depth int) string {
+		return strings.Repeat(`{"sha1": "a", "parent": `, depth) + "null" + strings.Repeat("}", depth)
+	}
+
+	dec := NewDecoder().WithMaxDepth(2)
+
+	commit, err := UnmarshalNewWith[Commit](dec, JSONStreamSource(strings.NewReader(nested(3))))
+	require.NoError(t, err)
+	require.Equal(t, commit.Parent.Parent.Sha1, "a")
+	require.Nil(t, commit.Parent.Parent.Parent)
+
+	_, err = UnmarshalNewWith[Commit](dec, JSONStreamSource(strings.NewReader(nested(4))))
+	require.ErrorIs(t, err, ErrMaxDepth)
+
+	// hostile input is rejected without exhausting the stack
+	_, err = UnmarshalNewWith[Commit](NewDecoder().WithMaxDepth(100), JSONStreamSource(strings.NewReader(nested(100_000))))
+	require.ErrorIs(t, err, ErrMaxDepth)
+
+	type Node struct {
+		Children []Node `json:"children"`
+	}
+
+	_, err = UnmarshalNewWith[Node](dec, JSONStreamSource(strings.NewReader(`{"children": [{"children": [{"children": []}]}]}`)))
+	require.NoError(t, err)
+
+	_, err = UnmarshalNewWith[Node](dec, JSONStreamSource(strings.NewReader(`{"children": [{"children": [{"children": [{}]}]}]}`)))
+	require.ErrorIs(t, err, ErrMaxDepth)
+}
+
+// endlessSource yields an endless sequence of elements and entries.
+type endlessSource struct{ EmptySource }
+
+func (endlessSource) Iter() (iter.Seq[Source], error) {
+	return func(yield func(Source) bool) {
+		for yield(StringSource("x")) {
+		}
+	}, nil
+}
+
+func (endlessSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	return func(yield func(Source, Source) bool) {
+		for idx := 0; yield(SourceOf(idx), StringSource("x")); idx++ {
+		}
+	}, nil
+}
+
+func TestDecoderWithLimits(t *testing.T) {
+	dec := NewDecoder().WithLimits(Limits{MaxSliceLen: 3, MaxMapEntries: 2, MaxStringLen: 5})
+
+	_, err := UnmarshalNewWith[[]string](dec, endlessSource{})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = UnmarshalNewWith[map[int]string](dec, endlessSource{})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	type Struct struct {
+		Name string         `json:"name"`
+		Tags []string       `json:"tags"`
+		Meta map[string]int `json:"meta"`
+	}
+
+	value, err := UnmarshalNewWith[Struct](dec, SourceOf(map[string]any{
+		"name": "alex",
+		"tags": []string{"a", "b", "c"},
+		"meta": map[string]int{"a": 1, "b": 2},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, value, Struct{Name: "alex", Tags: []string{"a", "b", "c"}, Meta: map[string]int{"a": 1, "b": 2}})
+
+	_, err = UnmarshalNewWith[Struct](dec, SourceOf(map[string]any{"name": "alexander"}))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, decodeErr.Path, "$.name")
+
+	_, err = UnmarshalNewWith[Struct](dec, SourceOf(map[string]any{"tags": []string{"a", "b", "c", "d"}}))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	// without limits, the same values decode
+	_, err = UnmarshalNew[Struct](SourceOf(map[string]any{"name": "alexander", "tags": []string{"a", "b", "c", "d"}}))
+	require.NoError(t, err)
+}
+
+func TestDecoderWithLimitsBinary(t *testing.T) {
+	dec := NewDecoder().WithLimits(Limits{MaxSliceLen: 3, MaxStringLen: 5})
+
+	decode := func(target any, buf []byte) error {
+		return dec.Unmarshal(BinaryReaderSource(bytes.NewReader(buf), binary.LittleEndian), target)
+	}
+
+	type LengthPrefixed struct {
+		Name string `bin:"lenprefix=u32"`
+	}
+
+	// the length is checked before reading the bytes
+	var lengthPrefixed LengthPrefixed
+	require.ErrorIs(t, decode(&lengthPrefixed, []byte{0xff, 0xff, 0xff, 0xff}), ErrLimitExceeded)
+	require.NoError(t, decode(&lengthPrefixed, []byte{2, 0, 0, 0, 'a', 'b'}))
+	require.Equal(t, lengthPrefixed.Name, "ab")
+
+	type LengthPrefixedBytes struct {
+		Data []byte `bin:"lenprefix=u8"`
+	}
+
+	var lengthPrefixedBytes LengthPrefixedBytes
+	require.ErrorIs(t, decode(&lengthPrefixedBytes, []byte{4, 1, 2, 3, 4}), ErrLimitExceeded)
+
+	type Fixed struct {
+		Name string `bin:"strlen=8"`
+	}
+
+	var fixed Fixed
+	require.ErrorIs(t, decode(&fixed, []byte("abcdefgh")), ErrLimitExceeded)
+	require.NoError(t, decode(&fixed, []byte("abc\x00\x00\x00\x00\x00")))
+	require.Equal(t, fixed.Name, "abc")
+
+	type CString struct {
+		Name string `bin:"cstr"`
+	}
+
+	var cString CString
+	require.ErrorIs(t, decode(&cString, []byte("abcdefgh\x00")), ErrLimitExceeded)
+	require.NoError(t, decode(&cString, []byte("abc\x00")))
+	require.Equal(t, cString.Name, "abc")
+
+	type CountOf struct {
+		Count  uint32
+		Values []uint8 `bin:"countof=Count"`
+	}
+
+	// the count is checked before growing the slice
+	var countOf CountOf
+	require.ErrorIs(t, decode(&countOf, []byte{0xff, 0xff, 0xff, 0xff}), ErrLimitExceeded)
+	require.NoError(t, decode(&countOf, []byte{2, 0, 0, 0, 1, 2}))
+	require.Equal(t, countOf.Values, []uint8{1, 2})
+}
+
+func TestDecoderWithLimitsMaterialized(t *testing.T) {
+	dec := NewDecoder().WithLimits(Limits{MaxSliceLen: 2, MaxMapEntries: 2, MaxStringLen: 3})
+
+	source := func(input string) Source {
+		return JSONStreamSource(strings.NewReader(input))
+	}
+
+	_, err := UnmarshalNewWith[any](dec, source(`[1, 2, 3]`))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = UnmarshalNewWith[any](dec, source(`[1, {"a": "long string"}]`))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+	require.ErrorContains(t, err, "$[1].a")
+
+	_, err = UnmarshalNewWith[map[string]any](dec, source(`{"a": 1, "b": [1, 2, 3]}`))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = UnmarshalNewWith[map[string]any](dec, source(`{"a": {"a": 1, "b": 2, "c": 3}}`))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = UnmarshalNewWith[Raw](dec, SourceOf([]any{"long string"}))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	var syncMap sync.Map
+	err = dec.Unmarshal(source(`{"a": 1, "b": 2, "c": 3}`), &syncMap)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	err = dec.Unmarshal(source(`{"a": "long string"}`), &syncMap)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	var values list.List
+	err = dec.Unmarshal(source(`[1, 2, 3]`), &values)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	err = dec.Unmarshal(source(`[[1, 2, 3]]`), &values)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = UnmarshalNewWith[OrderedMap[string, int]](dec, source(`{"a": 1, "b": 2, "c": 3}`))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	// values within the limits are decoded
+	value, err := UnmarshalNewWith[any](dec, source(`[1, {"a": "abc"}]`))
+	require.NoError(t, err)
+	require.Equal(t, value, []any{int64(1), map[string]any{"a": "abc"}})
+}
+
+func TestDecoderWithMaxDepthMaterialized(t *testing.T) {
+	nested := func(depth int) Source {
+		return JSONStreamSource(strings.NewReader(strings.Repeat("[", depth) + strings.Repeat("]", depth)))
+	}
+
+	dec := NewDecoder().WithMaxDepth(2)
+
+	_, err := UnmarshalNewWith[any](dec, nested(3))
+	require.NoError(t, err)
+
+	_, err = UnmarshalNewWith[any](dec, nested(4))
+	require.ErrorIs(t, err, ErrMaxDepth)
+
+	_, err = UnmarshalNewWith[map[string]any](dec, JSONStreamSource(strings.NewReader(`{"a": {"b": {"c": {"d": {}}}}}`)))
+	require.ErrorIs(t, err, ErrMaxDepth)
+
+	// deeply nested values are rejected without a maximum depth, too
+	var value any = []any{}
+	for range 100_000 {
+		value = []any{value}
+	}
+
+	_, err = UnmarshalNew[any](SourceOf(value))
+	require.ErrorIs(t, err, ErrMaxDepth)
+}
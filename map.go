@@ -0,0 +1,58 @@
+package unravel
+
+import (
+	"context"
+	"reflect"
+)
+
+// MapPolicy controls how the [Decoder] treats the existing entries of a map
+// it decodes into. Configure it using [Decoder.WithMapPolicy] or, for a single
+// field, using the `unravel` struct tag, e.g. `unravel:"merge"`.
+type MapPolicy int
+
+const (
+	// MapReplace replaces the map with a new map holding the decoded entries.
+	// This is the default.
+	MapReplace MapPolicy = iota
+
+	// MapMerge adds the decoded entries to the existing map. Values of existing
+	// keys are decoded into, e.g. fields of a struct value that have no value in
+	// the [Source] keep their current value.
+	MapMerge
+)
+
+// mapPolicies maps the names used in the `unravel` struct tag to a [MapPolicy].
+var mapPolicies = map[string]MapPolicy{
+	"replace": MapReplace,
+	"merge":   MapMerge,
+}
+
+// WithMapPolicy returns a [Decoder] that decodes maps using the given [MapPolicy].
+// Fields can override the policy using the `unravel` struct tag, e.g. `unravel:"replace"`.
+func (d *Decoder) WithMapPolicy(policy MapPolicy) *Decoder {
+	if d.mapPolicy == policy {
+		return d
+	}
+
+	derived := d.clone()
+	derived.mapPolicy = policy
+	return derived
+}
+
+// prepareMap prepares the existing map in target before decoding according to the policy.
+// Setters of maps built by a decoder using MapMerge add the decoded entries to an existing
+// map, so that a field can merge even if the decoder replaces maps.
+func prepareMap(policy MapPolicy, target reflect.Value) {
+	if policy == MapReplace {
+		target.SetZero()
+	}
+}
+
+// withMapPolicy wraps the setter of a struct field holding a map
+// to prepare the map according to the policy.
+func withMapPolicy(policy MapPolicy, setter fieldSetter) fieldSetter {
+	return func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		prepareMap(policy, fieldValue)
+		return setter(ctx, source, structValue, fieldValue)
+	}
+}
@@ -0,0 +1,132 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"testing"
+)
+
+func TestMapPolicy(t *testing.T) {
+	type Limit struct {
+		Rate  int `json:"rate"`
+		Burst int `json:"burst"`
+	}
+
+	type Config struct {
+		Labels map[string]string `json:"labels"`
+		Limits map[string]Limit  `json:"limits" unravel:"merge"`
+	}
+
+	source := SourceOf(map[string]any{
+		"labels": map[string]string{"team": "core"},
+		"limits": map[string]any{
+			"api": map[string]any{"rate": 5},
+			"web": map[string]any{"burst": 1},
+		},
+	})
+
+	newConfig := func() Config {
+		return Config{
+			Labels: map[string]string{"env": "dev"},
+			Limits: map[string]Limit{"api": {Rate: 10, Burst: 20}},
+		}
+	}
+
+	// replaces by default, the tagged field merges
+	config := newConfig()
+	err := Unmarshal(source, &config)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{
+		Labels: map[string]string{"team": "core"},
+		Limits: map[string]Limit{"api": {Rate: 5, Burst: 20}, "web": {Burst: 1}},
+	})
+
+	config = newConfig()
+	err = NewDecoder().WithMapPolicy(MapMerge).Unmarshal(source, &config)
+	require.NoError(t, err)
+	require.Equal(t, config.Labels, map[string]string{"env": "dev", "team": "core"})
+
+	// maps can be merged into directly
+	labels := map[string]string{"env": "dev"}
+	err = NewDecoder().WithMapPolicy(MapMerge).Unmarshal(SourceOf(map[string]string{"team": "core"}), &labels)
+	require.NoError(t, err)
+	require.Equal(t, labels, map[string]string{"env": "dev", "team": "core"})
+
+	err = Unmarshal(SourceOf(map[string]string{"team": "core"}), &labels)
+	require.NoError(t, err)
+	require.Equal(t, labels, map[string]string{"team": "core"})
+}
+
+func TestMapPolicyTagOverridesDecoder(t *testing.T) {
+	type Config struct {
+		Labels map[string]string `json:"labels" unravel:"replace"`
+	}
+
+	config := Config{Labels: map[string]string{"env": "dev"}}
+
+	err := NewDecoder().MergeInto().Unmarshal(SourceOf(map[string]any{"labels": map[string]string{"team": "core"}}), &config)
+	require.NoError(t, err)
+	require.Equal(t, config.Labels, map[string]string{"team": "core"})
+}
+
+func TestMapPolicyNestedMaps(t *testing.T) {
+	source := SourceOf([]map[string]int{{"b": 2}})
+
+	// maps within other values are replaced by default
+	value := [1]map[string]int{{"a": 1}}
+	err := Unmarshal(source, &value)
+	require.NoError(t, err)
+	require.Equal(t, value, [1]map[string]int{{"b": 2}})
+
+	value = [1]map[string]int{{"a": 1}}
+	err = NewDecoder().WithMapPolicy(MapMerge).Unmarshal(source, &value)
+	require.NoError(t, err)
+	require.Equal(t, value, [1]map[string]int{{"a": 1, "b": 2}})
+
+	nested := map[string]map[string]int{"x": {"a": 1}}
+	err = Unmarshal(SourceOf(map[string]any{"x": map[string]int{"b": 2}}), &nested)
+	require.NoError(t, err)
+	require.Equal(t, nested, map[string]map[string]int{"x": {"b": 2}})
+
+	// a tagged field merges maps nested within the field
+	type Config struct {
+		Groups map[string]map[string]int `json:"groups" unravel:"merge"`
+	}
+
+	config := Config{Groups: map[string]map[string]int{"x": {"a": 1}}}
+	err = Unmarshal(SourceOf(map[string]any{"groups": map[string]any{"x": map[string]int{"b": 2}}}), &config)
+	require.NoError(t, err)
+	require.Equal(t, config.Groups, map[string]map[string]int{"x": {"a": 1, "b": 2}})
+}
+
+func TestMapStringKeys(t *testing.T) {
+	type Label string
+
+	labels, err := UnmarshalNew[map[Label]int](SourceOf(map[string]int{"a": 1, "b": 2}))
+	require.NoError(t, err)
+	require.Equal(t, labels, map[Label]int{"a": 1, "b": 2})
+
+	// struct fields are yielded as keys
+	type Point struct{ X, Y int }
+
+	coords, err := UnmarshalNew[map[string]int](SourceOf(Point{X: 1, Y: 2}))
+	require.NoError(t, err)
+	require.Equal(t, coords, map[string]int{"X": 1, "Y": 2})
+
+	hosts, err := UnmarshalNew[map[netip.Addr]string](SourceOf(map[string]string{"127.0.0.1": "localhost"}))
+	require.NoError(t, err)
+	require.Equal(t, hosts, map[netip.Addr]string{netip.MustParseAddr("127.0.0.1"): "localhost"})
+
+	_, err = UnmarshalNew[map[netip.Addr]string](SourceOf(map[string]string{"local": "localhost"}))
+	require.ErrorContains(t, err, `$.local`)
+
+	// keys that are not strings are decoded using their setter
+	numbers, err := UnmarshalNew[map[string]int](SourceOf(map[int]int{1: 2}))
+	require.NoError(t, err)
+	require.Equal(t, numbers, map[string]int{"1": 2})
+
+	// options of the decoder still apply to the keys
+	trimmed, err := UnmarshalNewWith[map[string]int](NewDecoder().TrimSpace(), SourceOf(map[string]int{" a ": 1}))
+	require.NoError(t, err)
+	require.Equal(t, trimmed, map[string]int{"a": 1})
+}
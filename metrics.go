@@ -0,0 +1,90 @@
+package unravel
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics receives events of a [Decoder], giving visibility into its caching and the
+// work it does, see [Decoder.WithMetrics]. [Counters] is a ready to use implementation.
+// All methods must be safe for concurrent use and should return quickly, as they are
+// called while decoding.
+type Metrics interface {
+	// CacheHit is called when the setter of a type is taken from the cache.
+	CacheHit(ty reflect.Type)
+
+	// CacheMiss is called when the setter of a type is not cached and needs to be built.
+	CacheMiss(ty reflect.Type)
+
+	// SetterBuilt is called once the setter of a type has been built, with the time it took
+	// to build it, including the setters of its fields and elements that were not cached.
+	SetterBuilt(ty reflect.Type, elapsed time.Duration)
+
+	// FieldDecoded is called for each struct field that has been decoded from a value.
+	FieldDecoded(structType reflect.Type, field string)
+
+	// DecodeFailed is called when decoding a value of the type using [Decoder.Unmarshal]
+	// or [Decoder.UnmarshalValue] failed, including failed validation.
+	DecodeFailed(ty reflect.Type, err error)
+}
+
+// WithMetrics returns a [Decoder] that reports its events to the given [Metrics], e.g.
+// to tune the cache size of a decoder, see [Decoder.WithCacheLimit], or to find out which
+// types are decoded and how often decoding fails.
+func (d *Decoder) WithMetrics(metrics Metrics) *Decoder {
+	derived := d.clone()
+	derived.metrics = metrics
+	return derived
+}
+
+// Counters implements [Metrics] by counting events. The counters can be read at any time
+// and exported to a monitoring system, e.g. using a CounterFunc of the prometheus client.
+// Counters implements [expvar.Var], so it can also be published directly:
+//
+//	counters := &unravel.Counters{}
+//	expvar.Publish("unravel", counters)
+//
+//	dec := unravel.NewDecoder().WithMetrics(counters)
+type Counters struct {
+	CacheHits     atomic.Int64
+	CacheMisses   atomic.Int64
+	SettersBuilt  atomic.Int64
+	FieldsDecoded atomic.Int64
+	Errors        atomic.Int64
+
+	// Total time spent building setters, in nanoseconds
+	BuildTime atomic.Int64
+}
+
+var _ Metrics = (*Counters)(nil)
+
+func (c *Counters) CacheHit(reflect.Type) {
+	c.CacheHits.Add(1)
+}
+
+func (c *Counters) CacheMiss(reflect.Type) {
+	c.CacheMisses.Add(1)
+}
+
+func (c *Counters) SetterBuilt(_ reflect.Type, elapsed time.Duration) {
+	c.SettersBuilt.Add(1)
+	c.BuildTime.Add(int64(elapsed))
+}
+
+func (c *Counters) FieldDecoded(reflect.Type, string) {
+	c.FieldsDecoded.Add(1)
+}
+
+func (c *Counters) DecodeFailed(reflect.Type, error) {
+	c.Errors.Add(1)
+}
+
+// String returns the counters as a JSON object.
+func (c *Counters) String() string {
+	return fmt.Sprintf(
+		`{"cacheHits": %d, "cacheMisses": %d, "settersBuilt": %d, "buildTimeNanos": %d, "fieldsDecoded": %d, "errors": %d}`,
+		c.CacheHits.Load(), c.CacheMisses.Load(), c.SettersBuilt.Load(), c.BuildTime.Load(), c.FieldsDecoded.Load(), c.Errors.Load(),
+	)
+}
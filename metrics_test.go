@@ -0,0 +1,45 @@
+package unravel
+
+import (
+	"encoding/json"
+	"expvar"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var _ expvar.Var = &Counters{}
+
+func TestDecoderWithMetrics(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	counters := &Counters{}
+	dec := NewDecoder().WithMetrics(counters)
+
+	_, err := UnmarshalNewWith[User](dec, SourceOf(map[string]any{"name": "alex", "age": 21}))
+	require.NoError(t, err)
+
+	// the struct and both field types
+	require.Equal(t, counters.CacheMisses.Load(), int64(3))
+	require.Equal(t, counters.SettersBuilt.Load(), int64(3))
+	require.Equal(t, counters.CacheHits.Load(), int64(0))
+	require.Equal(t, counters.FieldsDecoded.Load(), int64(2))
+
+	// fields without a value are not counted
+	_, err = UnmarshalNewWith[User](dec, SourceOf(map[string]any{"name": "alex"}))
+	require.NoError(t, err)
+	require.Equal(t, counters.CacheHits.Load(), int64(1))
+	require.Equal(t, counters.FieldsDecoded.Load(), int64(3))
+
+	_, err = UnmarshalNewWith[User](dec, SourceOf(map[string]any{"age": "old"}))
+	require.Error(t, err)
+	require.Equal(t, counters.Errors.Load(), int64(1))
+
+	_, err = UnmarshalNewWith[int](dec, StringSource("x"))
+	require.Error(t, err)
+	require.Equal(t, counters.Errors.Load(), int64(2))
+
+	require.True(t, json.Valid([]byte(counters.String())))
+}
@@ -0,0 +1,91 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts a Go identifier to snake_case, e.g. "UserID" to "user_id" and
+// "HTTPServer" to "http_server". Use it with [Decoder.WithNameMapper].
+func SnakeCase(name string) string {
+	return strings.ToLower(splitWords(name))
+}
+
+// ScreamingSnakeCase converts a Go identifier to SCREAMING_SNAKE_CASE, e.g. "UserID" to
+// "USER_ID", as commonly used for environment variables. Use it with [Decoder.WithNameMapper].
+func ScreamingSnakeCase(name string) string {
+	return strings.ToUpper(splitWords(name))
+}
+
+// splitWords inserts an underscore at each word boundary of a CamelCase identifier.
+// A word starts at an upper case letter following a lower case letter or digit, or at
+// the last upper case letter of an acronym followed by a lower case letter.
+func splitWords(name string) string {
+	runes := []rune(name)
+
+	var result strings.Builder
+
+	for idx, r := range runes {
+		if idx > 0 && unicode.IsUpper(r) {
+			prev := runes[idx-1]
+			nextIsLower := idx+1 < len(runes) && unicode.IsLower(runes[idx+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextIsLower {
+				result.WriteByte('_')
+			}
+		}
+
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
+
+// NormalizeKey normalizes a key by removing underscores, dashes and spaces and converting
+// it to upper case, e.g. "DBHost", "db_host" and "DB-HOST" all normalize to "DBHOST". Use it
+// with [Decoder.WithKeyNormalizer].
+func NormalizeKey(key string) string {
+	key = strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', ' ':
+			return -1
+		default:
+			return r
+		}
+	}, key)
+
+	return strings.ToUpper(key)
+}
+
+// normalizedSource looks up keys that have no exact match by comparing the normalized
+// keys of [Source.KeyValues] with the normalized key.
+type normalizedSource struct {
+	Source
+	ctx       context.Context
+	normalize func(string) string
+}
+
+func (n normalizedSource) Get(key string) (Source, error) {
+	child, err := getContext(n.ctx, n.Source, key)
+	if !errors.Is(err, ErrNoValue) {
+		return child, err
+	}
+
+	keyValues, err := keyValuesContext(n.ctx, n.Source)
+	if err != nil {
+		// the keys can not be listed, so there is nothing to compare to
+		return nil, ErrNoValue
+	}
+
+	normalized := n.normalize(key)
+
+	for keySource, valueSource := range keyValues {
+		if name, err := keySource.String(); err == nil && n.normalize(name) == normalized {
+			return valueSource, nil
+		}
+	}
+
+	return nil, ErrNoValue
+}
@@ -0,0 +1,70 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"Name":       "name",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"MaxConns2":  "max_conns2",
+		"Version2Go": "version2_go",
+		"already":    "already",
+	}
+
+	for input, expected := range cases {
+		require.Equal(t, SnakeCase(input), expected, input)
+	}
+
+	require.Equal(t, ScreamingSnakeCase("DatabaseURL"), "DATABASE_URL")
+}
+
+func TestDecoderWithNameMapper(t *testing.T) {
+	type Config struct {
+		DatabaseURL string
+		MaxConns    int
+		Debug       bool `json:"verbose"`
+	}
+
+	source := SourceOf(map[string]any{
+		"DATABASE_URL": "postgres://",
+		"MAX_CONNS":    10,
+		"verbose":      true,
+	})
+
+	config, err := UnmarshalNewWith[Config](NewDecoder().WithNameMapper(ScreamingSnakeCase), source)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{DatabaseURL: "postgres://", MaxConns: 10, Debug: true})
+}
+
+func TestDecoderWithKeyNormalizer(t *testing.T) {
+	type Config struct {
+		DBHost  string
+		DBPort  int            `json:"db-port"`
+		Verbose bool           `json:"VERBOSE"`
+		Extra   map[string]any `unravel:"remain"`
+	}
+
+	dec := NewDecoder().WithKeyNormalizer(NormalizeKey)
+
+	sources := []Source{
+		SourceOf(map[string]any{"DB_HOST": "localhost", "DB_PORT": 5432, "verbose": true, "OTHER": "x"}),
+		SourceOf(map[string]any{"db-host": "localhost", "dbPort": 5432, "Verbose": true, "OTHER": "x"}),
+		SourceOf(map[string]any{"DBHost": "localhost", "db-port": 5432, "VERBOSE": true, "OTHER": "x"}),
+	}
+
+	for _, source := range sources {
+		config, err := UnmarshalNewWith[Config](dec, source)
+		require.NoError(t, err)
+		require.Equal(t, config, Config{DBHost: "localhost", DBPort: 5432, Verbose: true, Extra: map[string]any{"OTHER": "x"}})
+	}
+
+	// keys are matched exactly by default
+	config, err := UnmarshalNew[Config](sources[0])
+	require.NoError(t, err)
+	require.Equal(t, config.DBHost, "")
+	require.Equal(t, config.Extra["DB_HOST"], "localhost")
+}
@@ -0,0 +1,190 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Number holds a number in its textual representation, like [encoding/json.Number].
+// The [Decoder] fills a Number from the string or raw representation of a value, see
+// [RawSource], so that no precision is lost. If neither is available, the value is read
+// using the int, uint and float accessors of the [Source].
+//
+// Use the conversion methods to get the number as a Go type once it is needed,
+// or pass it on as is.
+type Number string
+
+var tyNumber = reflect.TypeFor[Number]()
+
+// String returns the textual representation of the number.
+func (n Number) String() string {
+	return string(n)
+}
+
+// Int64 returns the number as an int64.
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Uint64 returns the number as an uint64.
+func (n Number) Uint64() (uint64, error) {
+	return strconv.ParseUint(string(n), 10, 64)
+}
+
+// Float64 returns the number as a float64.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// BigInt returns the number as a [big.Int].
+func (n Number) BigInt() (*big.Int, error) {
+	value, ok := new(big.Int).SetString(string(n), 10)
+	if !ok {
+		return nil, fmt.Errorf("parse big.Int %q: %w", string(n), strconv.ErrSyntax)
+	}
+
+	return value, nil
+}
+
+// BigFloat returns the number as a [big.Float] with a precision
+// large enough to hold all of its digits.
+func (n Number) BigFloat() (*big.Float, error) {
+	value, _, err := big.ParseFloat(string(n), 10, bigFloatPrecOf(string(n)), big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("parse big.Float %q: %w", string(n), err)
+	}
+
+	return value, nil
+}
+
+func setNumber(ctx context.Context, source Source, target reflect.Value) error {
+	text, err := numberTextOf(source)
+	switch {
+	case err == nil:
+		// verify that the text is a number, it might still be too large for a float64
+		if _, err := strconv.ParseFloat(text, 64); err != nil && !errors.Is(err, strconv.ErrRange) {
+			return fmt.Errorf("parse number %q: %w", text, err)
+		}
+
+		target.SetString(text)
+		return nil
+
+	case !errors.Is(err, ErrNotSupported):
+		return fmt.Errorf("get string value: %w", err)
+	}
+
+	if intValue, err := source.Int(); err == nil {
+		target.SetString(strconv.FormatInt(intValue, 10))
+		return nil
+	}
+
+	if uintValue, err := source.Uint(); err == nil {
+		target.SetString(strconv.FormatUint(uintValue, 10))
+		return nil
+	}
+
+	floatValue, err := source.Float()
+	if err != nil {
+		return fmt.Errorf("get float value: %w", err)
+	}
+
+	target.SetString(strconv.FormatFloat(floatValue, 'g', -1, 64))
+	return nil
+}
+
+// NumberFormat describes how numbers are written in strings, see [Decoder.WithNumberFormat].
+type NumberFormat struct {
+	// Separates the integer part from the fractional part, e.g. ',' for "1,5".
+	Decimal rune
+
+	// Groups the digits of the integer part by three, e.g. '.' for "1.234".
+	// Zero if digits are not grouped.
+	Thousands rune
+}
+
+// DecimalComma is the format of numbers like "1.234,56", as commonly used in Europe.
+var DecimalComma = NumberFormat{Decimal: ',', Thousands: '.'}
+
+// DecimalPoint is the format of numbers like "1,234.56".
+var DecimalPoint = NumberFormat{Decimal: '.', Thousands: ','}
+
+// WithNumberFormat returns a [Decoder] that parses integer and floating point values from
+// strings written in the given format, e.g. "1.234,56" using [DecimalComma]. Thousands
+// separators must group the digits of the integer part by three. Values that are not
+// strings are decoded as is.
+func (d *Decoder) WithNumberFormat(format NumberFormat) *Decoder {
+	derived := d.clone()
+	derived.numberFormat = &format
+	return derived
+}
+
+// isNumberKind reports whether the kind is an integer or floating point kind.
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// normalize rewrites a number in this format to the format understood by the strconv
+// package. A number not matching the format is returned as is, to fail parsing later.
+func (f NumberFormat) normalize(text string) string {
+	decimal := f.Decimal
+	if decimal == 0 {
+		decimal = '.'
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(text, string(decimal))
+
+	if f.Thousands != 0 && strings.ContainsRune(intPart, f.Thousands) {
+		groups := strings.Split(intPart, string(f.Thousands))
+
+		first := strings.TrimLeft(groups[0], "+-")
+		if len(first) < 1 || len(first) > 3 {
+			return text
+		}
+
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return text
+			}
+		}
+
+		intPart = strings.Join(groups, "")
+	}
+
+	if !hasFrac {
+		return intPart
+	}
+
+	return intPart + "." + fracPart
+}
+
+// withNumberFormat wraps the setter of a numeric type to parse string values written in
+// the given format. Values that are not strings are decoded as is. A source implementing
+// [KindSource] is only read as a string if it holds a string, as some sources format
+// native numbers in String, e.g. [SourceOf].
+func withNumberFormat(format NumberFormat, setter setter) setter {
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		if kindSource, ok := source.(KindSource); ok && kindSource.Kind() != KindString {
+			return setter(ctx, source, target)
+		}
+
+		text, err := source.String()
+		if err != nil {
+			return setter(ctx, source, target)
+		}
+
+		return setter(ctx, StringSource(format.normalize(text)), target)
+	}
+}
@@ -0,0 +1,112 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecodeNumber(t *testing.T) {
+	type Payment struct {
+		Amount Number   `json:"amount"`
+		Fee    Number   `json:"fee"`
+		Count  Number   `json:"count"`
+		Rates  []Number `json:"rates"`
+	}
+
+	input := `{"amount": 12345678901234567890.123456789, "fee": "0.01", "count": 3, "rates": [1e400, -2]}`
+
+	payment, err := UnmarshalNew[Payment](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, payment, Payment{
+		Amount: "12345678901234567890.123456789",
+		Fee:    "0.01",
+		Count:  "3",
+		Rates:  []Number{"1e400", "-2"},
+	})
+
+	count, err := payment.Count.Int64()
+	require.NoError(t, err)
+	require.Equal(t, count, int64(3))
+
+	fee, err := payment.Fee.Float64()
+	require.NoError(t, err)
+	require.Equal(t, fee, 0.01)
+
+	amount, err := payment.Amount.BigFloat()
+	require.NoError(t, err)
+	require.Equal(t, amount.Text('f', 9), "12345678901234567890.123456789")
+
+	_, err = payment.Amount.BigInt()
+	require.Error(t, err)
+
+	_, err = UnmarshalNew[Number](StringSource("twelve"))
+	require.Error(t, err)
+}
+
+func TestDecodeNumberFromAccessors(t *testing.T) {
+	value, err := UnmarshalNew[[]Number](SourceOf([]any{uint64(1 << 63), 1.5, -7}))
+	require.NoError(t, err)
+	require.Equal(t, value, []Number{"9223372036854775808", "1.5", "-7"})
+}
+
+func TestDecoderWithNumberFormat(t *testing.T) {
+	type Invoice struct {
+		Total    float64 `json:"total"`
+		Quantity int     `json:"quantity"`
+		Discount float32 `json:"discount"`
+		Tax      float64 `json:"tax"`
+	}
+
+	source := SourceOf(map[string]any{
+		"total":    "1.234.567,89",
+		"quantity": "1.000",
+		"discount": "-0,5",
+		"tax":      19.5,
+	})
+
+	invoice, err := UnmarshalNewWith[Invoice](NewDecoder().WithNumberFormat(DecimalComma), source)
+	require.NoError(t, err)
+	require.Equal(t, invoice, Invoice{Total: 1234567.89, Quantity: 1000, Discount: -0.5, Tax: 19.5})
+
+	invoice, err = UnmarshalNewWith[Invoice](NewDecoder().WithNumberFormat(DecimalPoint), SourceOf(map[string]any{"total": "1,234.5"}))
+	require.NoError(t, err)
+	require.Equal(t, invoice, Invoice{Total: 1234.5})
+
+	// native numbers are not written in the format, even if the source formats them as string
+	var total float64
+	err = NewDecoder().WithNumberFormat(DecimalComma).Unmarshal(formattedFloatSource{value: 1.234}, &total)
+	require.NoError(t, err)
+	require.Equal(t, total, 1.234)
+
+	invoice, err = UnmarshalNewWith[Invoice](NewDecoder().WithNumberFormat(DecimalComma), JSONStreamSource(strings.NewReader(`{"total": 1.234, "tax": "1.234,5"}`)))
+	require.NoError(t, err)
+	require.Equal(t, invoice, Invoice{Total: 1.234, Tax: 1234.5})
+
+	// thousands separators must group by three
+	_, err = UnmarshalNewWith[Invoice](NewDecoder().WithNumberFormat(DecimalComma), SourceOf(map[string]any{"total": "12.34,5"}))
+	require.Error(t, err)
+
+	// by default, the decimal comma is not supported
+	_, err = UnmarshalNew[Invoice](SourceOf(map[string]any{"discount": "0,5"}))
+	require.Error(t, err)
+}
+
+// formattedFloatSource is a native number that is also accessible as string.
+type formattedFloatSource struct {
+	EmptySource
+	value float64
+}
+
+func (f formattedFloatSource) Kind() ValueKind {
+	return KindNumber
+}
+
+func (f formattedFloatSource) Float() (float64, error) {
+	return f.value, nil
+}
+
+func (f formattedFloatSource) String() (string, error) {
+	return strconv.FormatFloat(f.value, 'f', -1, 64), nil
+}
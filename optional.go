@@ -0,0 +1,124 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// Optional holds a value of type T together with a flag recording whether a value
+// was provided at all. The [Decoder] treats Optional specially: if the source has no
+// value for it, e.g. because a key is absent, the Optional stays empty. Otherwise, the
+// value is decoded into Value and Present is set to true. An explicit null value,
+// see [NullableSource], also leaves the Optional empty.
+//
+// This allows to distinguish between a value that was not provided and a value that
+// was explicitly set to its zero value, without using pointers.
+//
+// Optional fields are never required, even if [Decoder.RequireValues] is set.
+//
+// Example:
+//
+//	type Patch struct {
+//	    Name unravel.Optional[string] `json:"name"`
+//	    Age  unravel.Optional[int]    `json:"age"`
+//	}
+//
+//	patch, err := unravel.UnmarshalNew[Patch](source)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	if age, ok := patch.Age.Get(); ok {
+//	    user.Age = age
+//	}
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// Some returns an Optional holding the given value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Present: true}
+}
+
+// Get returns the value and whether it is present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+// OrElse returns the value if present, and the fallback otherwise.
+func (o Optional[T]) OrElse(fallback T) T {
+	if !o.Present {
+		return fallback
+	}
+
+	return o.Value
+}
+
+func (o Optional[T]) isOptional() {}
+
+// optional is implemented by all instances of Optional.
+type optional interface {
+	isOptional()
+}
+
+var tyOptional = reflect.TypeFor[optional]()
+
+// isOptionalType reports whether the type is an instance of [Optional].
+func isOptionalType(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Struct && ty.Implements(tyOptional)
+}
+
+// makeSetOptional returns a setter for an instance of [Optional]. If the
+// source has no value, the target is reset and Present is false.
+func (d *Decoder) makeSetOptional(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	valueSetter, err := d.setterOf(inConstruction, ty.Field(0).Type)
+	if err != nil {
+		return nil, err
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		// reset any previous value
+		target.SetZero()
+
+		if isNull(source) {
+			return nil
+		}
+
+		err := valueSetter(ctx, source, target.Field(0))
+		switch {
+		case errors.Is(err, ErrNoValue):
+			target.Field(0).SetZero()
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		target.Field(1).SetBool(true)
+		return nil
+	}
+
+	return setter, nil
+}
+
+// makeEmitOptional returns an emitter for an instance of [Optional] writing its value.
+// Empty optionals are skipped by the struct emitter and never reach this emitter.
+func (e *Encoder) makeEmitOptional(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	valueEmitter, err := e.emitterOf(inConstruction, ty.Field(0).Type)
+	if err != nil {
+		return nil, err
+	}
+
+	emitter := func(sink Sink, value reflect.Value) error {
+		return valueEmitter(sink, value.Field(0))
+	}
+
+	return emitter, nil
+}
+
+// isEmptyOptional reports whether the value is an instance of [Optional] without a value.
+func isEmptyOptional(value reflect.Value) bool {
+	return isOptionalType(value.Type()) && !value.Field(1).Bool()
+}
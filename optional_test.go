@@ -0,0 +1,54 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDecodeOptional(t *testing.T) {
+	type Patch struct {
+		Name    Optional[string]   `json:"name"`
+		Age     Optional[int]      `json:"age"`
+		Email   Optional[string]   `json:"email"`
+		Tags    Optional[[]string] `json:"tags"`
+		Comment Optional[*string]  `json:"comment"`
+	}
+
+	input := `{"name": "", "age": 21, "email": null, "tags": ["a"]}`
+
+	patch, err := UnmarshalNewWith[Patch](NewDecoder().RequireValues(), JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	require.Equal(t, patch.Name, Some(""))
+	require.Equal(t, patch.Age, Some(21))
+	require.Equal(t, patch.Email, Optional[string]{})
+	require.Equal(t, patch.Tags, Some([]string{"a"}))
+	require.Equal(t, patch.Comment, Optional[*string]{})
+
+	age, ok := patch.Age.Get()
+	require.True(t, ok)
+	require.Equal(t, age, 21)
+
+	require.Equal(t, patch.Email.OrElse("none"), "none")
+	require.Equal(t, patch.Name.OrElse("none"), "")
+
+	_, err = UnmarshalNew[Optional[int]](StringSource("abc"))
+	require.Error(t, err)
+}
+
+func TestMarshalOptional(t *testing.T) {
+	type Patch struct {
+		Name Optional[string] `json:"name"`
+		Age  Optional[int]    `json:"age"`
+	}
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+
+	err := Marshal(Patch{Age: Some(0)}, sink)
+	require.NoError(t, err)
+
+	require.Equal(t, sink.Values, map[string]any{
+		"$.age": int64(0),
+	})
+}
@@ -0,0 +1,242 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// OrderedMap is a map that remembers the order in which keys were inserted. The [Decoder]
+// inserts the entries in the order they are yielded by [Source.KeyValues], which preserves
+// the order of the keys in the input for formats like JSON or YAML. The [Encoder] writes
+// the entries in the same order.
+//
+// The zero value is an empty map ready to use.
+//
+// Example:
+//
+//	type Pipeline struct {
+//	    Steps unravel.OrderedMap[string, Step] `json:"steps"`
+//	}
+//
+//	for name, step := range pipeline.Steps.All() {
+//	    // steps are visited in the order of the input
+//	}
+type OrderedMap[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+}
+
+// Len returns the number of entries in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+// Get returns the value for the given key and whether the key is present.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Set sets the value for the given key. New keys are appended to the end of the map,
+// existing keys keep their position.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if m.values == nil {
+		m.values = map[K]V{}
+	}
+
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// Delete removes the entry with the given key from the map.
+func (m *OrderedMap[K, V]) Delete(key K) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+
+	delete(m.values, key)
+	m.keys = slices.DeleteFunc(m.keys, func(k K) bool { return k == key })
+}
+
+// Keys returns an iterator over the keys of the map in insertion order.
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return slices.Values(m.keys)
+}
+
+// All returns an iterator over the entries of the map in insertion order.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, key := range m.keys {
+			if !yield(key, m.values[key]) {
+				return
+			}
+		}
+	}
+}
+
+func (m *OrderedMap[K, V]) orderedMapTypes() (key, value reflect.Type) {
+	return reflect.TypeFor[K](), reflect.TypeFor[V]()
+}
+
+func (m *OrderedMap[K, V]) orderedMapLookup(key reflect.Value) reflect.Value {
+	value, ok := m.values[key.Interface().(K)]
+	if !ok {
+		return reflect.Value{}
+	}
+
+	return reflect.ValueOf(&value).Elem()
+}
+
+func (m *OrderedMap[K, V]) orderedMapSet(key, value reflect.Value) {
+	m.Set(key.Interface().(K), value.Interface().(V))
+}
+
+func (m *OrderedMap[K, V]) orderedMapEntries() iter.Seq2[reflect.Value, reflect.Value] {
+	return func(yield func(reflect.Value, reflect.Value) bool) {
+		for key, value := range m.All() {
+			if !yield(reflect.ValueOf(&key).Elem(), reflect.ValueOf(&value).Elem()) {
+				return
+			}
+		}
+	}
+}
+
+// orderedMap is implemented by pointers to all instances of OrderedMap.
+type orderedMap interface {
+	orderedMapTypes() (key, value reflect.Type)
+	orderedMapLookup(key reflect.Value) reflect.Value
+	orderedMapSet(key, value reflect.Value)
+	orderedMapEntries() iter.Seq2[reflect.Value, reflect.Value]
+}
+
+var tyOrderedMap = reflect.TypeFor[orderedMap]()
+
+// isOrderedMapType reports whether the type is an instance of [OrderedMap].
+func isOrderedMapType(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Struct && reflect.PointerTo(ty).Implements(tyOrderedMap)
+}
+
+// makeSetOrderedMap returns a setter for an instance of [OrderedMap]. Unless the
+// [MapPolicy] of the decoder is [MapMerge], existing entries are removed first.
+func (d *Decoder) makeSetOrderedMap(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	keyType, valueType := reflect.New(ty).Interface().(orderedMap).orderedMapTypes()
+
+	keySetter, err := d.setterOf(inConstruction, keyType)
+	if err != nil {
+		return nil, fmt.Errorf("setter for key type %q: %w", ty, err)
+	}
+
+	valueSetter, err := d.setterOf(inConstruction, valueType)
+	if err != nil {
+		return nil, fmt.Errorf("setter for value type %q: %w", ty, err)
+	}
+
+	keyScratch := newScratchValues(keyType)
+	valueScratch := newScratchValues(valueType)
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		keyValues, err := keyValuesContext(ctx, source)
+		if err != nil {
+			return fmt.Errorf("iterate key/value pairs: %w", err)
+		}
+
+		if d.mapPolicy != MapMerge {
+			target.SetZero()
+		}
+
+		mapTarget := target.Addr().Interface().(orderedMap)
+
+		// the key and value of each entry are copied by orderedMapSet
+		keyTarget := keyScratch.Get()
+		defer keyScratch.Put(keyTarget)
+
+		valueTarget := valueScratch.Get()
+		defer valueScratch.Put(valueTarget)
+
+		var entryCount int
+
+		for keySource, valueSource := range keyValues {
+			entryCount++
+			if d.limits.MaxMapEntries > 0 && entryCount > d.limits.MaxMapEntries {
+				return fmt.Errorf("%w: more than %d map entries", ErrLimitExceeded, d.limits.MaxMapEntries)
+			}
+
+			keyTarget.SetZero()
+			if err := keySetter(ctx, keySource, keyTarget); err != nil {
+				return withPath(fmt.Errorf("set key: %w", err), keySegmentOf(keySource), keyType)
+			}
+
+			valueTarget.SetZero()
+
+			if existing := mapTarget.orderedMapLookup(keyTarget); existing.IsValid() {
+				valueTarget.Set(existing)
+			}
+
+			err := valueSetter(ctx, valueSource, valueTarget)
+			switch {
+			case errors.Is(err, ErrNoValue) && isNull(valueSource):
+				// skip explicit null values that the value type can not represent
+				continue
+
+			case err != nil:
+				return withPath(err, mapKeySegment(keyTarget), valueType)
+			}
+
+			mapTarget.orderedMapSet(keyTarget, valueTarget)
+		}
+
+		return nil
+	}
+
+	return setter, nil
+}
+
+// makeEmitOrderedMap returns an emitter for an instance of [OrderedMap]
+// writing its entries in insertion order.
+func (e *Encoder) makeEmitOrderedMap(inConstruction typeSet, ty reflect.Type) (emitter, error) {
+	keyType, valueType := reflect.New(ty).Interface().(orderedMap).orderedMapTypes()
+
+	formatKey, err := keyFormatterOf(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("key of map type %q: %w", ty, err)
+	}
+
+	valueEmitter, err := e.emitterOf(inConstruction, valueType)
+	if err != nil {
+		return nil, fmt.Errorf("emitter for value type %q: %w", ty, err)
+	}
+
+	emitter := func(sink Sink, value reflect.Value) error {
+		// the methods of an OrderedMap need a pointer
+		for keyValue, entryValue := range addressableOf(value).Interface().(orderedMap).orderedMapEntries() {
+			if isNil(entryValue) {
+				continue
+			}
+
+			key, err := formatKey(keyValue)
+			if err != nil {
+				return fmt.Errorf("format key: %w", err)
+			}
+
+			entrySink, err := sink.Child(key)
+			if err != nil {
+				return fmt.Errorf("child %q: %w", key, err)
+			}
+
+			if err := valueEmitter(entrySink, entryValue); err != nil {
+				return fmt.Errorf("emit value of key %q: %w", key, err)
+			}
+		}
+
+		return nil
+	}
+
+	return emitter, nil
+}
@@ -0,0 +1,109 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecodeOrderedMap(t *testing.T) {
+	type Step struct {
+		Run string `json:"run"`
+	}
+
+	type Pipeline struct {
+		Steps  OrderedMap[string, Step] `json:"steps"`
+		Limits OrderedMap[string, int]  `json:"limits"`
+	}
+
+	input := `{
+		"steps": {"test": {"run": "go test"}, "build": {"run": "go build"}, "lint": {"run": "go vet"}},
+		"limits": {"z": 1, "a": 2, "skipped": null}
+	}`
+
+	pipeline, err := UnmarshalNew[Pipeline](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	require.Equal(t, slices.Collect(pipeline.Steps.Keys()), []string{"test", "build", "lint"})
+	require.Equal(t, slices.Collect(pipeline.Limits.Keys()), []string{"z", "a"})
+
+	step, ok := pipeline.Steps.Get("build")
+	require.True(t, ok)
+	require.Equal(t, step, Step{Run: "go build"})
+
+	// existing entries are replaced by default
+	limits := pipeline.Limits
+	err = Unmarshal(SourceOf(map[string]int{"b": 3}), &limits)
+	require.NoError(t, err)
+	require.Equal(t, slices.Collect(limits.Keys()), []string{"b"})
+
+	// and kept when merging
+	limits = pipeline.Limits
+	err = NewDecoder().WithMapPolicy(MapMerge).Unmarshal(SourceOf(map[string]int{"a": 3}), &limits)
+	require.NoError(t, err)
+	require.Equal(t, slices.Collect(limits.Keys()), []string{"z", "a"})
+
+	value, _ := limits.Get("a")
+	require.Equal(t, value, 3)
+}
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[string, int]
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("b", 3)
+
+	require.Equal(t, m.Len(), 2)
+	require.Equal(t, slices.Collect(m.Keys()), []string{"b", "a"})
+
+	m.Delete("b")
+	m.Delete("missing")
+
+	_, ok := m.Get("b")
+	require.False(t, ok)
+	require.Equal(t, slices.Collect(m.Keys()), []string{"a"})
+}
+
+// keyOrderSink records the order in which children are requested
+type keyOrderSink struct {
+	EmptySink
+	Keys *[]string
+}
+
+func (k keyOrderSink) Child(key string) (Sink, error) {
+	*k.Keys = append(*k.Keys, key)
+	return dummySink{Values: map[string]any{}}, nil
+}
+
+func TestMarshalOrderedMap(t *testing.T) {
+	var m OrderedMap[string, int]
+	m.Set("z", 1)
+	m.Set("a", 2)
+	m.Set("m", 3)
+
+	var keys []string
+
+	err := Marshal(m, keyOrderSink{Keys: &keys})
+	require.NoError(t, err)
+	require.Equal(t, keys, []string{"z", "a", "m"})
+}
+
+func TestDecodeOrderedMapFieldPolicy(t *testing.T) {
+	type Config struct {
+		Merged   OrderedMap[string, int] `json:"merged" unravel:"merge"`
+		Replaced OrderedMap[string, int] `json:"replaced" unravel:"replace"`
+	}
+
+	var config Config
+	err := Unmarshal(SourceOf(map[string]any{"merged": map[string]int{"a": 1}, "replaced": map[string]int{"a": 1}}), &config)
+	require.NoError(t, err)
+
+	err = Unmarshal(SourceOf(map[string]any{"merged": map[string]int{"b": 2}}), &config)
+	require.NoError(t, err)
+	require.Equal(t, slices.Collect(config.Merged.Keys()), []string{"a", "b"})
+
+	err = NewDecoder().WithMapPolicy(MapMerge).Unmarshal(SourceOf(map[string]any{"replaced": map[string]int{"b": 2}}), &config)
+	require.NoError(t, err)
+	require.Equal(t, slices.Collect(config.Replaced.Keys()), []string{"b"})
+}
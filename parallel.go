@@ -0,0 +1,132 @@
+package unravel
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// WithParallelSlices returns a [Decoder] that decodes the elements of slices concurrently,
+// using up to the given number of goroutines per slice. The elements are taken from
+// [Source.Iter] first, and then decoded in parallel, which speeds up decoding large lists
+// of rows that have been read into memory.
+//
+// Only use this option for sources whose elements can be used from multiple goroutines
+// at the same time, e.g. [SourceOf]. Streaming sources like [JSONStreamSource] or
+// [NDJSONSource] must be consumed in order and can not be decoded in parallel. The handler passed to
+// [Decoder.WithWarnings] must be safe for concurrent use. A value of one or less decodes
+// the elements one after another.
+func (d *Decoder) WithParallelSlices(workers int) *Decoder {
+	derived := d.clone()
+	derived.parallelSlices = max(workers, 1)
+	return derived
+}
+
+// setSliceParallel appends the elements of the iterator to the slice, decoding them
+// concurrently. Works like the setter returned by makeSetSliceOf otherwise.
+func (d *Decoder) setSliceParallel(ctx context.Context, ty reflect.Type, elementSetter setter, sourceIter iter.Seq[Source], target reflect.Value) error {
+	var elementSources []Source
+
+	for elementSource := range sourceIter {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if d.limits.MaxSliceLen > 0 && len(elementSources) >= d.limits.MaxSliceLen {
+			return fmt.Errorf("%w: more than %d elements", ErrLimitExceeded, d.limits.MaxSliceLen)
+		}
+
+		elementSources = append(elementSources, elementSource)
+	}
+
+	// the length of the slice before decoding, see SlicePolicy
+	initialLen := target.Len()
+
+	// grow the slice once, so that elements are not moved while decoding
+	length := initialLen + len(elementSources)
+	if target.Cap() < length {
+		grown := reflect.MakeSlice(target.Type(), initialLen, length)
+		reflect.Copy(grown, target)
+		target.Set(grown)
+	}
+
+	target.SetLen(length)
+
+	errs := make([]error, len(elementSources))
+
+	// stops all workers once an element failed, unless errors are collected
+	var failed atomic.Bool
+
+	// index of the next element to decode
+	var next atomic.Int64
+
+	var wg sync.WaitGroup
+
+	for range min(d.parallelSlices, len(elementSources)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for !failed.Load() {
+				idx := int(next.Add(1) - 1)
+				if idx >= len(elementSources) {
+					return
+				}
+
+				if ctx.Err() != nil {
+					failed.Store(true)
+					return
+				}
+
+				elementValue := target.Index(initialLen + idx)
+				elementValue.SetZero()
+
+				errs[idx] = elementSetter(ctx, elementSources[idx], elementValue)
+				if errs[idx] != nil && !d.collectErrors {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		// some elements might not have been decoded, drop all of them
+		for dropped := initialLen; dropped < length; dropped++ {
+			target.Index(dropped).SetZero()
+		}
+
+		target.SetLen(initialLen)
+		return err
+	}
+
+	var collected DecodeErrors
+
+	for idx, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if err := d.collectError(&collected, withPath(err, indexSegment(initialLen+idx), ty.Elem())); err != nil {
+			// elements are handed out in order, so all elements before the failed one
+			// have been decoded. Keep them like the sequential setter does.
+			for dropped := initialLen + idx + 1; dropped < length; dropped++ {
+				target.Index(dropped).SetZero()
+			}
+
+			target.SetLen(initialLen + idx + 1)
+			return err
+		}
+	}
+
+	if len(collected) > 0 {
+		return collected
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func TestDecoderWithParallelSlices(t *testing.T) {
+	type Row struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	var input []map[string]any
+	for idx := range 1000 {
+		input = append(input, map[string]any{"name": "row" + strconv.Itoa(idx), "count": idx})
+	}
+
+	dec := NewDecoder().WithParallelSlices(4)
+
+	expected, err := UnmarshalNew[[]Row](SourceOf(input))
+	require.NoError(t, err)
+
+	rows, err := UnmarshalNewWith[[]Row](dec, SourceOf(input))
+	require.NoError(t, err)
+	require.Equal(t, rows, expected)
+
+	// elements are appended like with the sequential decoder
+	rows = []Row{{Name: "first"}}
+	err = dec.WithSlicePolicy(SliceAppend).Unmarshal(SourceOf(input[:2]), &rows)
+	require.NoError(t, err)
+	require.Equal(t, rows, []Row{{Name: "first"}, {Name: "row0"}, {Name: "row1", Count: 1}})
+
+	input[500]["count"] = "many"
+	input[700]["count"] = "more"
+
+	_, err = UnmarshalNewWith[[]Row](dec, SourceOf(input))
+	require.ErrorContains(t, err, "$[500].count")
+
+	// like the sequential decoder, only the elements up to the failed one are kept
+	rows = []Row{{Name: "first"}}
+	err = dec.WithSlicePolicy(SliceAppend).Unmarshal(SourceOf(input), &rows)
+	require.ErrorContains(t, err, "$[501].count")
+	require.Len(t, rows, 502)
+	require.Equal(t, rows[500], expected[499])
+
+	sequential := []Row{{Name: "first"}}
+	err = NewDecoder().Unmarshal(SourceOf(input), &sequential)
+	require.Error(t, err)
+	require.Equal(t, rows, sequential)
+
+	// all failures are reported in order of the elements
+	_, err = UnmarshalNewWith[[]Row](dec.CollectErrors(), SourceOf(input))
+
+	var decodeErrs DecodeErrors
+	require.ErrorAs(t, err, &decodeErrs)
+	require.Len(t, decodeErrs, 2)
+	require.Equal(t, decodeErrs[0].Path, "$[500].count")
+	require.Equal(t, decodeErrs[1].Path, "$[700].count")
+
+	_, err = UnmarshalNewWith[[]Row](dec.WithLimits(Limits{MaxSliceLen: 100}), SourceOf(input))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
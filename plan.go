@@ -0,0 +1,81 @@
+package unravel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Plan describes how a [Decoder] maps the fields of a struct type onto a [Source].
+type Plan struct {
+	Type reflect.Type
+
+	// The fields populated by the decoder, in the order they are decoded.
+	Fields []PlannedField
+
+	// Go paths of the fields that are never populated: fields skipped using a struct
+	// tag, unexported fields and fields hidden by a field of the same name on a lower
+	// nesting level.
+	Skipped []string
+
+	// Fields dropped due to naming conflicts, see [Decoder.StrictFields].
+	Conflicts []FieldConflict
+}
+
+// PlannedField describes a field populated by the decoder.
+type PlannedField struct {
+	// The key looked up in the source.
+	Name string
+
+	// Alternative keys looked up if the source has no value for Name.
+	Aliases []string
+
+	// The Go path of the field, e.g. "Address.Street".
+	Path string
+
+	Type reflect.Type
+
+	// The field must have a value in the source.
+	Required bool
+
+	// The field collects all keys not consumed by other fields.
+	Remain bool
+}
+
+// Plan returns the mapping of the fields of a struct type, or pointer to a struct type,
+// as it is used when decoding. Naming conflicts are reported in the plan and do not
+// result in an error, even with [Decoder.StrictFields].
+func (d *Decoder) Plan(ty reflect.Type) (Plan, error) {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	if ty.Kind() != reflect.Struct {
+		return Plan{}, NotSupportedError{Type: ty}
+	}
+
+	resolved := resolveFields(ty, d.structTagsOrDefault())
+
+	plan := Plan{
+		Type:      ty,
+		Skipped:   resolved.Skipped,
+		Conflicts: resolved.Conflicts,
+	}
+
+	for _, field := range d.mapNames(resolved.Fields) {
+		fieldOpts, err := parseFieldOptions(field.Tag.Get("unravel"))
+		if err != nil {
+			return Plan{}, fmt.Errorf("field %q: %w", field.Name, err)
+		}
+
+		plan.Fields = append(plan.Fields, PlannedField{
+			Name:     field.Name,
+			Aliases:  fieldOpts.Aliases,
+			Path:     fieldPathOf(ty, field.Index),
+			Type:     field.Type,
+			Required: fieldOpts.Required || d.requireValues && !isOptionalType(field.Type),
+			Remain:   fieldOpts.Remain,
+		})
+	}
+
+	return plan, nil
+}
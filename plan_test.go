@@ -0,0 +1,47 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestDecoderPlan(t *testing.T) {
+	type First struct{ ID, Name string }
+	type Second struct{ ID string }
+
+	type Struct struct {
+		First
+		Second
+		Name     string        `json:"Name" unravel:"alt=user_name"`
+		Age      Optional[int] `json:"age"`
+		Secret   string        `json:"-"`
+		internal string
+		Extra    map[string]string `unravel:"remain"`
+	}
+
+	plan, err := NewDecoder().RequireValues().Plan(reflect.TypeFor[*Struct]())
+	require.NoError(t, err)
+
+	require.Equal(t, plan.Type, reflect.TypeFor[Struct]())
+
+	require.Equal(t, plan.Fields, []PlannedField{
+		{Name: "Name", Aliases: []string{"user_name"}, Path: "Name", Type: reflect.TypeFor[string](), Required: true},
+		{Name: "age", Path: "Age", Type: reflect.TypeFor[Optional[int]]()},
+		{Name: "Extra", Path: "Extra", Type: reflect.TypeFor[map[string]string](), Required: true, Remain: true},
+	})
+
+	require.Equal(t, plan.Skipped, []string{"Secret", "internal", "First.Name"})
+
+	require.Equal(t, plan.Conflicts, []FieldConflict{
+		{Name: "ID", Fields: []string{"First.ID", "Second.ID"}},
+	})
+
+	// the name mapper applies to the plan
+	plan, err = NewDecoder().WithNameMapper(SnakeCase).Plan(reflect.TypeFor[First]())
+	require.NoError(t, err)
+	require.Equal(t, plan.Fields[0].Name, "id")
+
+	_, err = NewDecoder().Plan(reflect.TypeFor[int]())
+	require.ErrorAs(t, err, &NotSupportedError{})
+}
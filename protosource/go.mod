@@ -0,0 +1,18 @@
+module github.com/go-gum/unravel/protosource
+
+go 1.23.4
+
+require (
+	github.com/go-gum/unravel v0.0.0
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/exp v0.0.0-20250106191152-7588d65b2ba8 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/go-gum/unravel => ../
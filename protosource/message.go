@@ -0,0 +1,337 @@
+// Package protosource adapts dynamic protobuf messages to the [unravel.Source] interface.
+//
+// A [MessageSource] wraps any [protoreflect.Message], including messages created from
+// descriptors loaded at runtime using [google.golang.org/protobuf/types/dynamicpb]. This
+// makes it possible to decode protobuf data into plain Go structs without generated code.
+// Fields are addressable by their protobuf name or their JSON name, repeated fields are
+// exposed as lists and map fields as maps.
+//
+// The package is a module of its own, so that depending on unravel does not pull in
+// the protobuf runtime.
+package protosource
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-gum/unravel"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MessageSource adapts a [protoreflect.Message] to the [unravel.Source] interface.
+//
+// Fields are looked up by their name as declared in the schema, falling back to their
+// JSON name. Fields that track presence, e.g. message fields or proto3 optional fields,
+// return [unravel.ErrNoValue] if they are not set. Other fields always return a value,
+// which is the default value of the field if not populated.
+//
+// Scalar values are mapped as follows:
+//   - Integer and floating point fields are accessible as numbers.
+//   - String and bytes fields are accessible using [unravel.Source.String].
+//   - Enum fields are accessible as number or, using [unravel.Source.String], as
+//     the name of the enum value.
+//   - The well known types google.protobuf.Timestamp and google.protobuf.Duration
+//     are accessible using [unravel.Source.String], formatted as RFC 3339 timestamp
+//     and as a [time.Duration] string.
+//
+// Example:
+//
+//	type User struct {
+//	    Name  string   `json:"name"`
+//	    Roles []string `json:"roles"`
+//	}
+//
+//	msg := dynamicpb.NewMessage(descriptor)
+//	if err := proto.Unmarshal(payload, msg); err != nil {
+//	    return err
+//	}
+//
+//	user, err := unravel.UnmarshalNew[User](protosource.MessageSource{Message: msg})
+type MessageSource struct {
+	Message protoreflect.Message
+}
+
+var _ unravel.Source = MessageSource{}
+
+func (m MessageSource) Bool() (bool, error) {
+	return false, unravel.ErrNotSupported
+}
+
+func (m MessageSource) Int() (int64, error) {
+	return 0, unravel.ErrNotSupported
+}
+
+func (m MessageSource) Uint() (uint64, error) {
+	return 0, unravel.ErrNotSupported
+}
+
+func (m MessageSource) Float() (float64, error) {
+	return 0, unravel.ErrNotSupported
+}
+
+func (m MessageSource) String() (string, error) {
+	desc := m.Message.Descriptor()
+
+	switch desc.FullName() {
+	case "google.protobuf.Timestamp":
+		seconds, nanos := m.secondsAndNanos()
+		return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano), nil
+
+	case "google.protobuf.Duration":
+		seconds, nanos := m.secondsAndNanos()
+		return (time.Duration(seconds)*time.Second + time.Duration(nanos)).String(), nil
+
+	default:
+		return "", unravel.ErrNotSupported
+	}
+}
+
+// secondsAndNanos reads the fields of a Timestamp or Duration message.
+func (m MessageSource) secondsAndNanos() (int64, int64) {
+	fields := m.Message.Descriptor().Fields()
+
+	seconds := m.Message.Get(fields.ByName("seconds")).Int()
+	nanos := m.Message.Get(fields.ByName("nanos")).Int()
+
+	return seconds, nanos
+}
+
+func (m MessageSource) Get(key string) (unravel.Source, error) {
+	fields := m.Message.Descriptor().Fields()
+
+	fd := fields.ByName(protoreflect.Name(key))
+	if fd == nil {
+		fd = fields.ByJSONName(key)
+	}
+
+	if fd == nil {
+		return nil, unravel.ErrNoValue
+	}
+
+	if fd.HasPresence() && !m.Message.Has(fd) {
+		return nil, unravel.ErrNoValue
+	}
+
+	return valueSource{Field: fd, Value: m.Message.Get(fd)}, nil
+}
+
+func (m MessageSource) KeyValues() (iter.Seq2[unravel.Source, unravel.Source], error) {
+	it := func(yield func(unravel.Source, unravel.Source) bool) {
+		m.Message.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			key := unravel.StringSource(fd.Name())
+			return yield(key, valueSource{Field: fd, Value: value})
+		})
+	}
+
+	return it, nil
+}
+
+func (m MessageSource) Iter() (iter.Seq[unravel.Source], error) {
+	return nil, unravel.ErrNotSupported
+}
+
+// valueSource is a single value of a field. Field describes the field the value
+// belongs to, for elements of repeated fields this is the repeated field itself.
+type valueSource struct {
+	Field protoreflect.FieldDescriptor
+	Value protoreflect.Value
+}
+
+var _ unravel.Source = valueSource{}
+var _ unravel.BytesSource = valueSource{}
+
+func (v valueSource) Bool() (bool, error) {
+	boolValue, ok := v.Value.Interface().(bool)
+	if !ok {
+		return false, unravel.ErrNotSupported
+	}
+
+	return boolValue, nil
+}
+
+func (v valueSource) Int() (int64, error) {
+	switch value := v.Value.Interface().(type) {
+	case int32:
+		return int64(value), nil
+	case int64:
+		return value, nil
+	case uint32:
+		return int64(value), nil
+	case uint64:
+		if value > math.MaxInt64 {
+			return 0, fmt.Errorf("invalid int64 value %d: %w", value, strconv.ErrRange)
+		}
+
+		return int64(value), nil
+	case protoreflect.EnumNumber:
+		return int64(value), nil
+	default:
+		return 0, unravel.ErrNotSupported
+	}
+}
+
+func (v valueSource) Uint() (uint64, error) {
+	switch value := v.Value.Interface().(type) {
+	case uint32:
+		return uint64(value), nil
+	case uint64:
+		return value, nil
+	case int32, int64, protoreflect.EnumNumber:
+		intValue, _ := v.Int()
+		if intValue < 0 {
+			return 0, fmt.Errorf("invalid uint64 value %d: %w", intValue, strconv.ErrRange)
+		}
+
+		return uint64(intValue), nil
+	default:
+		return 0, unravel.ErrNotSupported
+	}
+}
+
+func (v valueSource) Float() (float64, error) {
+	switch value := v.Value.Interface().(type) {
+	case float32:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	case int32:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint32:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	default:
+		return 0, unravel.ErrNotSupported
+	}
+}
+
+func (v valueSource) String() (string, error) {
+	switch value := v.Value.Interface().(type) {
+	case string:
+		return value, nil
+
+	case []byte:
+		return string(value), nil
+
+	case protoreflect.EnumNumber:
+		enumValue := v.Field.Enum().Values().ByNumber(value)
+		if enumValue == nil {
+			return "", unravel.ErrNotSupported
+		}
+
+		return string(enumValue.Name()), nil
+
+	case protoreflect.Message:
+		return MessageSource{Message: value}.String()
+
+	default:
+		return "", unravel.ErrNotSupported
+	}
+}
+
+func (v valueSource) Bytes() ([]byte, error) {
+	value, ok := v.Value.Interface().([]byte)
+	if !ok {
+		return nil, unravel.ErrNotSupported
+	}
+
+	return value, nil
+}
+
+func (v valueSource) Get(key string) (unravel.Source, error) {
+	switch value := v.Value.Interface().(type) {
+	case protoreflect.Message:
+		return MessageSource{Message: value}.Get(key)
+
+	case protoreflect.Map:
+		mapKey, err := v.mapKeyOf(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !value.Has(mapKey) {
+			return nil, unravel.ErrNoValue
+		}
+
+		return valueSource{Field: v.Field.MapValue(), Value: value.Get(mapKey)}, nil
+
+	default:
+		return nil, unravel.ErrNotSupported
+	}
+}
+
+// mapKeyOf parses a key into a map key of the kind of the maps key type.
+func (v valueSource) mapKeyOf(key string) (protoreflect.MapKey, error) {
+	source := unravel.StringSource(key)
+
+	switch v.Field.MapKey().Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(key).MapKey(), nil
+
+	case protoreflect.BoolKind:
+		boolValue, err := source.Bool()
+		return protoreflect.ValueOfBool(boolValue).MapKey(), err
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		intValue, err := source.Int()
+		return protoreflect.ValueOfInt32(int32(intValue)).MapKey(), err
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		uintValue, err := source.Uint()
+		return protoreflect.ValueOfUint32(uint32(uintValue)).MapKey(), err
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		uintValue, err := source.Uint()
+		return protoreflect.ValueOfUint64(uintValue).MapKey(), err
+
+	default:
+		intValue, err := source.Int()
+		return protoreflect.ValueOfInt64(intValue).MapKey(), err
+	}
+}
+
+func (v valueSource) KeyValues() (iter.Seq2[unravel.Source, unravel.Source], error) {
+	switch value := v.Value.Interface().(type) {
+	case protoreflect.Message:
+		return MessageSource{Message: value}.KeyValues()
+
+	case protoreflect.Map:
+		keyField, valueField := v.Field.MapKey(), v.Field.MapValue()
+
+		it := func(yield func(unravel.Source, unravel.Source) bool) {
+			value.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				return yield(
+					valueSource{Field: keyField, Value: key.Value()},
+					valueSource{Field: valueField, Value: value},
+				)
+			})
+		}
+
+		return it, nil
+
+	default:
+		return nil, unravel.ErrNotSupported
+	}
+}
+
+func (v valueSource) Iter() (iter.Seq[unravel.Source], error) {
+	list, ok := v.Value.Interface().(protoreflect.List)
+	if !ok {
+		return nil, unravel.ErrNotSupported
+	}
+
+	it := func(yield func(unravel.Source) bool) {
+		for idx := range list.Len() {
+			if !yield(valueSource{Field: v.Field, Value: list.Get(idx)}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
@@ -0,0 +1,177 @@
+package protosource
+
+import (
+	"testing"
+
+	"github.com/go-gum/unravel"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// userDescriptor builds the descriptor of the following message at runtime:
+//
+//	enum Role { ROLE_UNKNOWN = 0; ROLE_ADMIN = 1; }
+//	message Address { string city = 1; }
+//	message User {
+//	  string name = 1;
+//	  int32 age = 2;
+//	  repeated Role roles = 3;
+//	  map<string, int64> scores = 4;
+//	  Address home_address = 5;
+//	  repeated Address addresses = 6;
+//	  optional uint32 level = 7;
+//	}
+func userDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	field := func(name string, number int32, ty descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   ty.Enum(),
+			Label:  label.Enum(),
+		}
+
+		if typeName != "" {
+			fd.TypeName = proto.String(typeName)
+		}
+
+		return fd
+	}
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	level := field("level", 7, descriptorpb.FieldDescriptorProto_TYPE_UINT32, optional, "")
+	level.Proto3Optional = proto.Bool(true)
+	level.OneofIndex = proto.Int32(0)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("user.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Role"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("ROLE_UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("ROLE_ADMIN"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+					field("roles", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, repeated, ".test.Role"),
+					field("scores", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.User.ScoresEntry"),
+					field("home_address", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".test.Address"),
+					field("addresses", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.Address"),
+					level,
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_level")}},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name:    proto.String("ScoresEntry"),
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					},
+				}},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(file, nil)
+	require.NoError(t, err)
+
+	return fd.Messages().ByName("User")
+}
+
+func TestMessageSource(t *testing.T) {
+	desc := userDescriptor(t)
+	fields := desc.Fields()
+
+	msg := dynamicpb.NewMessage(desc)
+	msg.Set(fields.ByName("name"), protoreflect.ValueOfString("Alex"))
+	msg.Set(fields.ByName("age"), protoreflect.ValueOfInt32(42))
+
+	roles := msg.Mutable(fields.ByName("roles")).List()
+	roles.Append(protoreflect.ValueOfEnum(1))
+	roles.Append(protoreflect.ValueOfEnum(0))
+
+	scores := msg.Mutable(fields.ByName("scores")).Map()
+	scores.Set(protoreflect.ValueOfString("go").MapKey(), protoreflect.ValueOfInt64(10))
+
+	home := msg.Mutable(fields.ByName("home_address")).Message()
+	home.Set(home.Descriptor().Fields().ByName("city"), protoreflect.ValueOfString("Berlin"))
+
+	addresses := msg.Mutable(fields.ByName("addresses")).List()
+	addresses.Append(protoreflect.ValueOfMessage(home))
+
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type User struct {
+		Name      string           `json:"name"`
+		Age       int8             `json:"age"`
+		Roles     []string         `json:"roles"`
+		Scores    map[string]int64 `json:"scores"`
+		Home      *Address         `json:"homeAddress"`
+		Addresses []Address        `json:"addresses"`
+		Level     *uint32          `json:"level"`
+	}
+
+	user, err := unravel.UnmarshalNew[User](MessageSource{Message: msg})
+	require.NoError(t, err)
+
+	require.Equal(t, user, User{
+		Name:      "Alex",
+		Age:       42,
+		Roles:     []string{"ROLE_ADMIN", "ROLE_UNKNOWN"},
+		Scores:    map[string]int64{"go": 10},
+		Home:      &Address{City: "Berlin"},
+		Addresses: []Address{{City: "Berlin"}},
+	})
+
+	type RoleIDs struct {
+		Roles []int `json:"roles"`
+	}
+
+	roleIDs, err := unravel.UnmarshalNew[RoleIDs](MessageSource{Message: msg})
+	require.NoError(t, err)
+	require.Equal(t, roleIDs.Roles, []int{1, 0})
+}
+
+func TestMessageSourcePresence(t *testing.T) {
+	desc := userDescriptor(t)
+
+	msg := dynamicpb.NewMessage(desc)
+	msg.Set(desc.Fields().ByName("level"), protoreflect.ValueOfUint32(3))
+
+	type User struct {
+		Name  string  `json:"name"`
+		Level *uint32 `json:"level"`
+		Home  *struct {
+			City string `json:"city"`
+		} `json:"home_address"`
+	}
+
+	user, err := unravel.UnmarshalNewWith[User](unravel.NewDecoder().RequireValues(), MessageSource{Message: msg})
+	require.ErrorIs(t, err, unravel.ErrNoValue)
+
+	user, err = unravel.UnmarshalNew[User](MessageSource{Message: msg})
+	require.NoError(t, err)
+
+	level := uint32(3)
+	require.Equal(t, user, User{Level: &level})
+}
@@ -0,0 +1,88 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Raw captures a value of a [Source] as is, to decode it later, e.g. once the type of a
+// payload is known, or to pass it through unchanged. If the [Source] implements [RawSource],
+// the raw representation of the value is kept in Bytes. Otherwise, the value is materialized
+// into its natural Go representation, see [KindSource], and kept in Value.
+//
+// Use [Raw.Source] to decode the captured value.
+//
+// Example:
+//
+//	type Envelope struct {
+//	    Kind    string      `json:"kind"`
+//	    Payload unravel.Raw `json:"payload"`
+//	}
+//
+//	envelope, err := unravel.UnmarshalNew[Envelope](source)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	switch envelope.Kind {
+//	case "user":
+//	    user, err := unravel.UnmarshalNew[User](envelope.Payload.Source())
+//	    // ...
+//	}
+type Raw struct {
+	// The raw JSON representation of the value, if the source implements [RawSource].
+	Bytes []byte
+
+	// The materialized value, if the raw representation was not available.
+	Value any
+}
+
+var tyRaw = reflect.TypeFor[Raw]()
+
+// Source returns a [Source] to decode the captured value.
+func (r Raw) Source() Source {
+	if r.Bytes == nil {
+		return SourceOf(r.Value)
+	}
+
+	source, err := jsonSourceOfRaw(r.Bytes)
+	if err != nil {
+		return errorSource{err}
+	}
+
+	return source
+}
+
+func (d *Decoder) setRaw(ctx context.Context, source Source, target reflect.Value) error {
+	if rawSource, ok := source.(RawSource); ok {
+		raw, err := rawSource.Raw()
+		switch {
+		case err == nil:
+			target.Set(reflect.ValueOf(Raw{Bytes: raw}))
+			return nil
+
+		case !errors.Is(err, ErrNotSupported):
+			return fmt.Errorf("get raw value: %w", err)
+		}
+	}
+
+	value, err := d.materialize(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	target.Set(reflect.ValueOf(Raw{Value: value}))
+	return nil
+}
+
+// emitRaw writes the value captured by a [Raw].
+func (e *Encoder) emitRaw(sink Sink, value reflect.Value) error {
+	materialized, err := materialize(value.Interface().(Raw).Source())
+	if err != nil {
+		return err
+	}
+
+	return e.emitInterface(sink, reflect.ValueOf(&materialized).Elem())
+}
@@ -0,0 +1,71 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDecodeRaw(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	type Envelope struct {
+		Kind    string `json:"kind"`
+		Payload Raw    `json:"payload"`
+	}
+
+	// the payload comes before the kind, it is buffered and decoded once the kind is known
+	input := `{"payload": {"name": "Alex", "age": 21}, "kind": "user"}`
+
+	envelope, err := UnmarshalNew[Envelope](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, envelope.Kind, "user")
+	require.Equal(t, string(envelope.Payload.Bytes), `{"name": "Alex", "age": 21}`)
+
+	user, err := UnmarshalNew[User](envelope.Payload.Source())
+	require.NoError(t, err)
+	require.Equal(t, user, User{Name: "Alex", Age: 21})
+}
+
+func TestDecodeRawMaterialized(t *testing.T) {
+	type Envelope struct {
+		Payload Raw `json:"payload"`
+	}
+
+	source := SourceOf(map[string]any{
+		"payload": map[string]any{"ids": []int{1, 2}},
+	})
+
+	envelope, err := UnmarshalNew[Envelope](source)
+	require.NoError(t, err)
+	require.Nil(t, envelope.Payload.Bytes)
+	require.Equal(t, envelope.Payload.Value, map[string]any{"ids": []any{int64(1), int64(2)}})
+
+	type Payload struct {
+		IDs []int `json:"ids"`
+	}
+
+	payload, err := UnmarshalNew[Payload](envelope.Payload.Source())
+	require.NoError(t, err)
+	require.Equal(t, payload, Payload{IDs: []int{1, 2}})
+}
+
+func TestMarshalRaw(t *testing.T) {
+	type Envelope struct {
+		Kind    string `json:"kind"`
+		Payload Raw    `json:"payload"`
+	}
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+
+	err := Marshal(Envelope{Kind: "user", Payload: Raw{Bytes: []byte(`{"name": "Alex"}`)}}, sink)
+	require.NoError(t, err)
+
+	require.Equal(t, sink.Values, map[string]any{
+		"$.kind":         "user",
+		"$.payload.name": "Alex",
+	})
+}
@@ -0,0 +1,76 @@
+package unravel
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// addKnownKeys adds the keys looked up by a field to known. For nested
+// paths, the first segment of the path is consumed by the field too.
+func addKnownKeys(known map[string]bool, field field, fieldOpts fieldOptions) {
+	for _, key := range append([]string{field.Name}, fieldOpts.Aliases...) {
+		known[key] = true
+
+		if first, _, ok := strings.Cut(key, "."); ok {
+			known[first] = true
+		}
+	}
+}
+
+// normalizeKnownKeys returns the known keys after normalizing them.
+func normalizeKnownKeys(known map[string]bool, normalize func(string) string) map[string]bool {
+	normalized := make(map[string]bool, len(known))
+	for key := range known {
+		normalized[normalize(key)] = true
+	}
+
+	return normalized
+}
+
+// isRemainField reports whether the field collects the remaining keys of a struct.
+func isRemainField(field field) bool {
+	fieldOpts, err := parseFieldOptions(field.Tag.Get("unravel"))
+	return err == nil && fieldOpts.Remain && field.Type.Kind() == reflect.Map
+}
+
+// remainSource hides the keys consumed by other fields of a struct from [Source.KeyValues].
+// It is used for map fields tagged with `unravel:",remain"` to collect unknown keys.
+type remainSource struct {
+	Source
+	ctx   context.Context
+	known map[string]bool
+
+	// normalizes keys before looking them up in known, if not nil
+	normalize func(string) string
+}
+
+func (r remainSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	keyValues, err := keyValuesContext(r.ctx, r.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	it := func(yield func(Source, Source) bool) {
+		for keySource, valueSource := range keyValues {
+			if key, err := keySource.String(); err == nil && r.isKnown(key) {
+				continue
+			}
+
+			if !yield(keySource, valueSource) {
+				return
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (r remainSource) isKnown(key string) bool {
+	if r.normalize != nil {
+		key = r.normalize(key)
+	}
+
+	return r.known[key]
+}
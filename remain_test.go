@@ -0,0 +1,62 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDecodeRemain(t *testing.T) {
+	type Manifest struct {
+		Name       string         `json:"name"`
+		Version    string         `json:"version" unravel:"alt=v"`
+		Author     string         `json:"author.name"`
+		Extensions map[string]any `json:"extensions" unravel:",remain"`
+	}
+
+	input := `{
+		"name": "app",
+		"v": "1.0",
+		"author": {"name": "Alex"},
+		"x-internal": true,
+		"x-owner": {"team": "core"}
+	}`
+
+	manifest, err := UnmarshalNew[Manifest](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, manifest, Manifest{
+		Name:    "app",
+		Version: "1.0",
+		Author:  "Alex",
+		Extensions: map[string]any{
+			"x-internal": true,
+			"x-owner":    map[string]any{"team": "core"},
+		},
+	})
+
+	sink := dummySink{Path: "$", Values: map[string]any{}}
+	require.NoError(t, Marshal(Manifest{Name: "app", Extensions: map[string]any{"x-internal": true}}, sink))
+	require.Equal(t, sink.Values, map[string]any{
+		"$.name":        "app",
+		"$.version":     "",
+		"$.author.name": "",
+		"$.x-internal":  true,
+	})
+}
+
+func TestDecodeRemainInvalid(t *testing.T) {
+	type Slice struct {
+		Rest []string `unravel:",inline"`
+	}
+
+	_, err := UnmarshalNew[Slice](SourceOf(map[string]any{}))
+	require.ErrorAs(t, err, &NotSupportedError{})
+
+	type Twice struct {
+		First  map[string]any `unravel:",remain"`
+		Second map[string]any `unravel:",remain"`
+	}
+
+	_, err = UnmarshalNew[Twice](SourceOf(map[string]any{}))
+	require.Error(t, err)
+}
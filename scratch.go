@@ -0,0 +1,35 @@
+package unravel
+
+import (
+	"reflect"
+	"sync"
+)
+
+// scratchValues hands out temporary values of a type, e.g. the key and value of a map
+// entry before they are copied into the map. The values are reused across calls to
+// the setter holding them, instead of allocating new values for each decoded value.
+//
+// Setters have no state per call to Unmarshal, so each setter holds the pools of the
+// temporary values it needs. Paths and DecodeErrors are not pooled, as they are only
+// built once decoding failed.
+type scratchValues struct {
+	pool sync.Pool
+}
+
+func newScratchValues(ty reflect.Type) *scratchValues {
+	scratch := &scratchValues{}
+	scratch.pool.New = func() any { return reflect.New(ty).Interface() }
+	return scratch
+}
+
+// Get returns an addressable zero value of the type.
+func (s *scratchValues) Get() reflect.Value {
+	return reflect.ValueOf(s.pool.Get()).Elem()
+}
+
+// Put returns the value for reuse. The value is reset, so that it does not keep
+// anything it references alive, and must not be used afterwards.
+func (s *scratchValues) Put(value reflect.Value) {
+	value.SetZero()
+	s.pool.Put(value.Addr().Interface())
+}
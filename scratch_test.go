@@ -0,0 +1,43 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestScratchValues(t *testing.T) {
+	scratch := newScratchValues(reflect.TypeFor[[]int]())
+
+	value := scratch.Get()
+	require.True(t, value.CanSet())
+	require.True(t, value.IsZero())
+
+	value.Set(reflect.ValueOf([]int{1, 2}))
+	scratch.Put(value)
+
+	// values are reset before they are reused
+	require.True(t, value.IsZero())
+	require.True(t, scratch.Get().IsZero())
+}
+
+func TestScratchValuesNotShared(t *testing.T) {
+	type Entry struct {
+		Values []int `json:"values"`
+	}
+
+	source := SourceOf(map[string]any{
+		"a": map[string]any{"values": []int{1}},
+		"b": map[string]any{"values": []int{2, 3}},
+	})
+
+	first, err := UnmarshalNew[map[string]Entry](source)
+	require.NoError(t, err)
+
+	second, err := UnmarshalNew[map[string]Entry](source)
+	require.NoError(t, err)
+
+	second["a"].Values[0] = 4
+
+	require.Equal(t, first, map[string]Entry{"a": {Values: []int{1}}, "b": {Values: []int{2, 3}}})
+}
@@ -0,0 +1,106 @@
+package unravel
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// KeyStyle controls how a [URLValuesSink] names the keys of nested values.
+type KeyStyle int
+
+const (
+	// KeyStyleBrackets names nested keys using brackets, e.g. `filter[name]`.
+	KeyStyleBrackets KeyStyle = iota
+
+	// KeyStyleDots names nested keys using dots, e.g. `filter.name`.
+	KeyStyleDots
+)
+
+// URLValuesSink returns a [Sink] that writes into the given [url.Values], e.g. to build
+// a query string or a form body. It is the counterpart of decoding a struct from [url.Values].
+//
+// Each field of the value passed to [Marshal] is written using its name as key. Elements of
+// slices are written as repeated values of the same key. Fields of nested structs and entries
+// of nested maps are written using keys built according to the given [KeyStyle].
+//
+// Example:
+//
+//	type Search struct {
+//	    Query  string            `json:"q"`
+//	    Tags   []string          `json:"tag"`
+//	    Filter map[string]string `json:"filter"`
+//	}
+//
+//	values := url.Values{}
+//
+//	search := Search{Query: "go", Tags: []string{"a", "b"}, Filter: map[string]string{"lang": "en"}}
+//	if err := unravel.Marshal(search, unravel.URLValuesSink(values, unravel.KeyStyleBrackets)); err != nil {
+//	    return err
+//	}
+//
+//	// values.Encode() returns "filter%5Blang%5D=en&q=go&tag=a&tag=b"
+func URLValuesSink(values url.Values, style KeyStyle) Sink {
+	return urlValuesSink{values: values, style: style}
+}
+
+type urlValuesSink struct {
+	values url.Values
+	style  KeyStyle
+
+	// the key values are written to, empty for the top level sink
+	key string
+}
+
+func (u urlValuesSink) add(value string) error {
+	if u.key == "" {
+		// the top level value must be an object
+		return ErrNotSupported
+	}
+
+	u.values.Add(u.key, value)
+	return nil
+}
+
+func (u urlValuesSink) SetBool(value bool) error {
+	return u.add(strconv.FormatBool(value))
+}
+
+func (u urlValuesSink) SetInt(value int64) error {
+	return u.add(strconv.FormatInt(value, 10))
+}
+
+func (u urlValuesSink) SetUint(value uint64) error {
+	return u.add(strconv.FormatUint(value, 10))
+}
+
+func (u urlValuesSink) SetFloat(value float64) error {
+	return u.add(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func (u urlValuesSink) SetString(value string) error {
+	return u.add(value)
+}
+
+func (u urlValuesSink) Child(key string) (Sink, error) {
+	child := u
+
+	switch {
+	case u.key == "":
+		child.key = key
+	case u.style == KeyStyleDots:
+		child.key = u.key + "." + key
+	default:
+		child.key = u.key + "[" + key + "]"
+	}
+
+	return child, nil
+}
+
+func (u urlValuesSink) Element() (Sink, error) {
+	if u.key == "" {
+		return nil, ErrNotSupported
+	}
+
+	// elements are written as repeated values of the same key
+	return u, nil
+}
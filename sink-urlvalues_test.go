@@ -0,0 +1,65 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+)
+
+func TestURLValuesSink(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  int    `json:"zip"`
+	}
+
+	type Form struct {
+		Query   string            `json:"q"`
+		Page    uint              `json:"page"`
+		Ratio   float64           `json:"ratio"`
+		Exact   bool              `json:"exact"`
+		Tags    []string          `json:"tag"`
+		Filter  map[string]string `json:"filter"`
+		Address *Address          `json:"address"`
+		Missing *Address          `json:"missing"`
+	}
+
+	form := Form{
+		Query:   "go",
+		Page:    2,
+		Ratio:   0.25,
+		Exact:   true,
+		Tags:    []string{"a", "b"},
+		Filter:  map[string]string{"lang": "en"},
+		Address: &Address{City: "Zürich", Zip: 8015},
+	}
+
+	values := url.Values{}
+	err := Marshal(form, URLValuesSink(values, KeyStyleBrackets))
+	require.NoError(t, err)
+
+	require.Equal(t, values, url.Values{
+		"q":             {"go"},
+		"page":          {"2"},
+		"ratio":         {"0.25"},
+		"exact":         {"true"},
+		"tag":           {"a", "b"},
+		"filter[lang]":  {"en"},
+		"address[city]": {"Zürich"},
+		"address[zip]":  {"8015"},
+	})
+
+	values = url.Values{}
+	err = Marshal(form, URLValuesSink(values, KeyStyleDots))
+	require.NoError(t, err)
+
+	require.Equal(t, values["filter.lang"], []string{"en"})
+	require.Equal(t, values["address.city"], []string{"Zürich"})
+}
+
+func TestURLValuesSinkRequiresObject(t *testing.T) {
+	err := Marshal("text", URLValuesSink(url.Values{}, KeyStyleBrackets))
+	require.ErrorIs(t, err, ErrNotSupported)
+
+	err = Marshal([]string{"a"}, URLValuesSink(url.Values{}, KeyStyleBrackets))
+	require.ErrorIs(t, err, ErrNotSupported)
+}
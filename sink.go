@@ -0,0 +1,105 @@
+package unravel
+
+// Sink is the counterpart of [Source]: an abstract interface to a serialization target,
+// designed to work with the [Marshal] function. While a [Source] is queried for values
+// during [Unmarshal], a [Sink] receives values while [Marshal] walks a Go value.
+//
+// A [Sink] accepts values in different forms:
+//   - Primitive types: A primitive value is written using one of the setter methods,
+//     e.g. [Sink.SetInt] for integers or [Sink.SetString] for strings.
+//   - Objects and maps: Each field of a struct and each entry of a map is written to
+//     the sink returned by [Sink.Child] for the respective key.
+//   - Slices and arrays: Each element is written to the sink returned by [Sink.Element].
+//
+// If a value can not be represented by the [Sink], the method must return [ErrNotSupported].
+//
+// Example:
+//
+//	type Person struct {
+//	    Name string `json:"name"`
+//	    Tags []string `json:"tags"`
+//	}
+//
+// Marshalling a Person calls `sink.Child("name")` and then `SetString` on the returned
+// child. For the tags, it calls `sink.Child("tags")` and then `Element()` on the
+// returned child once for each tag, calling `SetString` on each element.
+type Sink interface {
+	// SetBool writes a bool value.
+	SetBool(value bool) error
+
+	// SetInt writes a signed integer value.
+	SetInt(value int64) error
+
+	// SetUint writes an unsigned integer value.
+	SetUint(value uint64) error
+
+	// SetFloat writes a floating point value.
+	SetFloat(value float64) error
+
+	// SetString writes a string value.
+	SetString(value string) error
+
+	// Child returns a [Sink] for the child value with the given key.
+	// It is used to write the fields of a struct and the entries of a map.
+	// Returns [ErrNotSupported] if the [Sink] can not hold child values.
+	Child(key string) (Sink, error)
+
+	// Element returns a [Sink] for the next element of a list. It is called once
+	// for each element of a slice or array, in order.
+	// Returns [ErrNotSupported] if the [Sink] can not hold a list.
+	Element() (Sink, error)
+}
+
+// BinarySink is an optional extension of the [Sink] interface, the counterpart of
+// [BinarySource]. When using [Marshal], the sized methods are preferred over the more
+// generic [Sink.SetInt], [Sink.SetUint] and [Sink.SetFloat] methods, so that binary
+// formats can write values with their exact size.
+type BinarySink interface {
+	SetInt8(value int8) error
+	SetInt16(value int16) error
+	SetInt32(value int32) error
+	SetInt64(value int64) error
+
+	SetUint8(value uint8) error
+	SetUint16(value uint16) error
+	SetUint32(value uint32) error
+	SetUint64(value uint64) error
+
+	SetFloat32(value float32) error
+	SetFloat64(value float64) error
+}
+
+// EmptySink is a minimal implementation of the [Sink] interface that returns
+// [ErrNotSupported] for all methods. Like [EmptySource], it is intended to be
+// embedded into custom [Sink] implementations.
+type EmptySink struct{}
+
+var _ Sink = EmptySink{}
+
+func (EmptySink) SetBool(value bool) error {
+	return ErrNotSupported
+}
+
+func (EmptySink) SetInt(value int64) error {
+	return ErrNotSupported
+}
+
+func (EmptySink) SetUint(value uint64) error {
+	return ErrNotSupported
+}
+
+func (EmptySink) SetFloat(value float64) error {
+	return ErrNotSupported
+}
+
+func (EmptySink) SetString(value string) error {
+	return ErrNotSupported
+}
+
+func (EmptySink) Child(key string) (Sink, error) {
+	return nil, ErrNotSupported
+}
+
+func (EmptySink) Element() (Sink, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,111 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"reflect"
+)
+
+// SlicePolicy controls how the [Decoder] treats the existing content of a slice
+// it decodes into. Configure it using [Decoder.WithSlicePolicy] or, for a single
+// field, using the `unravel` struct tag, e.g. `unravel:"replace"`.
+type SlicePolicy int
+
+const (
+	// SliceAppend appends the decoded elements to the existing elements of the slice.
+	// This is the default.
+	SliceAppend SlicePolicy = iota
+
+	// SliceReplace replaces the slice with a new slice holding the decoded elements,
+	// like [encoding/json.Unmarshal] does.
+	SliceReplace
+
+	// SliceReuse replaces the elements of the slice with the decoded elements, reusing
+	// the capacity of the existing slice. This avoids allocations when repeatedly
+	// decoding into the same value, but the previous elements are overwritten.
+	SliceReuse
+)
+
+// slicePolicies maps the names used in the `unravel` struct tag to a [SlicePolicy].
+var slicePolicies = map[string]SlicePolicy{
+	"append":  SliceAppend,
+	"replace": SliceReplace,
+	"reuse":   SliceReuse,
+}
+
+// WithSlicePolicy returns a [Decoder] that decodes slices using the given [SlicePolicy].
+// Fields can override the policy using the `unravel` struct tag, e.g. `unravel:"append"`.
+func (d *Decoder) WithSlicePolicy(policy SlicePolicy) *Decoder {
+	if d.slicePolicy == policy {
+		return d
+	}
+
+	derived := d.clone()
+	derived.slicePolicy = policy
+	return derived
+}
+
+// prepareSlice prepares the existing slice in target before decoding according to the policy.
+// Setters of slices always append the decoded elements.
+func prepareSlice(policy SlicePolicy, target reflect.Value) {
+	switch policy {
+	case SliceReplace:
+		target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+
+	case SliceReuse:
+		if !target.IsNil() {
+			target.SetLen(0)
+		}
+	}
+}
+
+// withSlicePolicy wraps the setter of a struct field holding a slice
+// to prepare the slice according to the policy.
+func withSlicePolicy(policy SlicePolicy, setter fieldSetter) fieldSetter {
+	return func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		prepareSlice(policy, fieldValue)
+		return setter(ctx, source, structValue, fieldValue)
+	}
+}
+
+// SingleValueAsSlice returns a [Decoder] that decodes a single value into a slice with one
+// element, if the [Source] of the slice does not support [Source.Iter]. Query strings and
+// XML documents often collapse lists with a single element into the element itself.
+func (d *Decoder) SingleValueAsSlice() *Decoder {
+	if d.singleValueAsSlice {
+		return d
+	}
+
+	derived := d.clone()
+	derived.singleValueAsSlice = true
+	return derived
+}
+
+// withSingleValueAsSlice wraps the setter of a slice to decode a single value
+// as a slice with one element.
+func withSingleValueAsSlice(setter setter) setter {
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		return setter(ctx, singleValueSource{Source: source, ctx: ctx}, target)
+	}
+}
+
+// singleValueSource yields the wrapped [Source] as the only element
+// if the wrapped [Source] does not support [Source.Iter].
+type singleValueSource struct {
+	Source
+	ctx context.Context
+}
+
+func (s singleValueSource) Iter() (iter.Seq[Source], error) {
+	sourceIter, err := iterContext(s.ctx, s.Source)
+	if !errors.Is(err, ErrNotSupported) {
+		return sourceIter, err
+	}
+
+	it := func(yield func(Source) bool) {
+		yield(s.Source)
+	}
+
+	return it, nil
+}
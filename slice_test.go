@@ -0,0 +1,112 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSlicePolicy(t *testing.T) {
+	type Config struct {
+		Hosts   []string `json:"hosts"`
+		Ports   []int    `json:"ports" unravel:"append"`
+		Missing []string `json:"missing"`
+	}
+
+	source := SourceOf(map[string]any{
+		"hosts": []string{"b"},
+		"ports": []int{2},
+	})
+
+	newConfig := func() Config {
+		return Config{Hosts: []string{"a"}, Ports: []int{1}, Missing: []string{"x"}}
+	}
+
+	// appends by default
+	config := newConfig()
+	err := Unmarshal(source, &config)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{Hosts: []string{"a", "b"}, Ports: []int{1, 2}, Missing: []string{"x"}})
+
+	// the tagged field keeps appending, fields without a value are not touched
+	config = newConfig()
+	err = NewDecoder().WithSlicePolicy(SliceReplace).Unmarshal(source, &config)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{Hosts: []string{"b"}, Ports: []int{1, 2}, Missing: []string{"x"}})
+
+	// an empty list replaces the slice with an empty slice
+	config = newConfig()
+	err = NewDecoder().WithSlicePolicy(SliceReplace).Unmarshal(SourceOf(map[string]any{"hosts": []string{}}), &config)
+	require.NoError(t, err)
+	require.Equal(t, config.Hosts, []string{})
+}
+
+func TestSlicePolicyReuse(t *testing.T) {
+	dec := NewDecoder().WithSlicePolicy(SliceReuse)
+
+	values := make([]int, 0, 4)
+	values = append(values, 1, 2, 3)
+
+	err := dec.Unmarshal(SourceOf([]int{7, 8}), &values)
+	require.NoError(t, err)
+	require.Equal(t, values, []int{7, 8})
+	require.Equal(t, cap(values), 4)
+
+	type Struct struct {
+		Values []int `json:"values" unravel:"reuse"`
+	}
+
+	value := Struct{Values: values}
+	err = Unmarshal(SourceOf(map[string]any{"values": []int{9}}), &value)
+	require.NoError(t, err)
+	require.Equal(t, value.Values, []int{9})
+	require.Equal(t, cap(value.Values), 4)
+}
+
+func TestSlicePolicyTagConflict(t *testing.T) {
+	type Struct struct {
+		Values []int `json:"values" unravel:"reuse,append"`
+	}
+
+	_, err := UnmarshalNew[Struct](SourceOf(map[string]any{}))
+	require.Error(t, err)
+}
+
+func TestSingleValueAsSlice(t *testing.T) {
+	type Query struct {
+		Tags []string `json:"tags"`
+		IDs  []int    `json:"ids"`
+	}
+
+	dec := NewDecoder().SingleValueAsSlice()
+
+	query, err := UnmarshalNewWith[Query](dec, SourceOf(map[string]any{"tags": "go", "ids": []int{1, 2}}))
+	require.NoError(t, err)
+	require.Equal(t, query, Query{Tags: []string{"go"}, IDs: []int{1, 2}})
+
+	query, err = UnmarshalNewWith[Query](dec, JSONStreamSource(strings.NewReader(`{"tags": ["a", "b"], "ids": 7}`)))
+	require.NoError(t, err)
+	require.Equal(t, query, Query{Tags: []string{"a", "b"}, IDs: []int{7}})
+
+	// the default decoder requires a list
+	_, err = UnmarshalNew[Query](SourceOf(map[string]any{"tags": "go"}))
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestSlicePreallocate(t *testing.T) {
+	input := make([]int, 1000)
+	for idx := range input {
+		input[idx] = idx
+	}
+
+	values, err := UnmarshalNew[[]int](SourceOf(input))
+	require.NoError(t, err)
+	require.Equal(t, values, input)
+
+	// the length is known upfront, the slice is allocated once
+	require.Equal(t, cap(values), len(input))
+
+	// the capacity is bounded by the limits of the decoder
+	_, err = UnmarshalNewWith[[]int](NewDecoder().WithLimits(Limits{MaxSliceLen: 10}), SourceOf(input))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
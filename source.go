@@ -30,7 +30,7 @@ import "iter"
 // for implementing binary protocols, where precise control over data representation is required.
 //
 // To facilitate the creation of custom [Source] implementations, the package includes
-// two ready-to-use implementations:
+// three ready-to-use implementations:
 //
 //  1. [StringSource]: This implementation leverages the `strconv` package to parse strings
 //     into various target types, such as integers, floats, and booleans. It serves as a practical
@@ -40,6 +40,9 @@ import "iter"
 //     This is ideal as a fallback or placeholder for unsupported operations or as a starting
 //     point for developing new [Source] implementations.
 //
+//  3. [BinaryReader]: Reads sized integers and floats from an [io.Reader] using a configurable
+//     [encoding/binary.ByteOrder]. Create one using [BinaryReaderSource].
+//
 // These implementations can be embedded or delegated to within your custom [Source]
 // implementation, allowing you to focus on extending functionality rather than re-implementing
 // common behaviors.
@@ -117,3 +120,108 @@ type BinarySource interface {
 	Float32() (float32, error)
 	Float64() (float64, error)
 }
+
+// VarintSource is an optional extension of a [BinarySource] for sources that can decode
+// variable length integers natively. Fields tagged with `bin:"varint"` are decoded using
+// these methods if available. Otherwise, the [Decoder] reads the varint byte by byte
+// using [BinarySource.Uint8].
+//
+// Varints use the LEB128 encoding as implemented by [encoding/binary.ReadUvarint].
+// Signed values are zig-zag encoded, see [encoding/binary.ReadVarint].
+type VarintSource interface {
+	Varint() (int64, error)
+	Uvarint() (uint64, error)
+}
+
+// ReadBytesSource is an optional extension of a [BinarySource] for sources that can read
+// a sequence of raw bytes at once. It is used for fields tagged with `bin:"lenprefix=..."`
+// and for byte arrays, e.g. `[16]byte`. Without it, the [Decoder] falls back to reading
+// byte by byte using [BinarySource.Uint8].
+type ReadBytesSource interface {
+	// ReadBytes reads exactly n bytes.
+	ReadBytes(n uint64) ([]byte, error)
+}
+
+// BytesSource is an optional extension of the [Source] interface for sources that hold
+// a sequence of bytes as a single value, e.g. a binary column or a bencode string.
+// It is used to decode `[]byte` and `[N]byte` at once, instead of element by element
+// using [Source.Iter]. The [Decoder] copies the returned bytes, a source may return
+// a slice of its internal buffer. Bytes returns [ErrNotSupported] if the value
+// is not a sequence of bytes, in which case the [Decoder] falls back to [Source.Iter].
+type BytesSource interface {
+	Bytes() ([]byte, error)
+}
+
+// SeekerSource is an optional extension of a [BinarySource] for sources backed by
+// seekable input. It is used for fields tagged with `bin:"offset=..."`, which are decoded
+// from a position that is either fixed or read from a previously decoded field.
+// The semantics of Seek follow [io.Seeker].
+type SeekerSource interface {
+	Seek(offset int64, whence int) (int64, error)
+}
+
+// RecordingSource is an optional extension of a [BinarySource] that can record the raw bytes
+// consumed while decoding. It is required to verify fields tagged with `bin:"checksum=..."`.
+type RecordingSource interface {
+	// Record starts recording all bytes consumed from the source. The returned function
+	// stops the recording and returns the bytes consumed since the call to Record.
+	// Calling the function more than once must return the same bytes.
+	// Recordings may be nested.
+	Record() (stop func() []byte)
+}
+
+// RawSource is an optional extension of the [Source] interface for sources backed by JSON.
+// Raw returns the raw JSON representation of the current value. It is used to decode types
+// that implement [encoding/json.Unmarshaler], but neither [Unmarshaler] nor
+// [encoding.TextUnmarshaler]. Raw returns [ErrNotSupported] if the raw representation
+// is not available, in which case the [Decoder] decodes the type based on its kind.
+type RawSource interface {
+	Raw() ([]byte, error)
+}
+
+// NullableSource is an optional extension of the [Source] interface for sources that
+// can distinguish an explicit null value from a missing value, e.g. a JSON `null`.
+// If IsNull returns true, the [Decoder] sets pointers to nil and [Optional] values to
+// empty instead of decoding into them.
+type NullableSource interface {
+	IsNull() bool
+}
+
+// KindSource is an optional extension of the [Source] interface for self describing sources,
+// e.g. JSON, that know the kind of their values without being told the target type.
+// It allows the [Decoder] to decode into `any` and into maps and slices of `any`.
+// See [ValueKind] for the possible kinds.
+type KindSource interface {
+	Kind() ValueKind
+}
+
+// IndexSource is an optional extension of the [Source] interface for sources that provide
+// positional access to their elements, e.g. the columns of a CSV row, command line arguments
+// or the capture groups of a regular expression. It is required for struct fields tagged
+// with `idx:"2"`, which are decoded from the element at the given zero based position.
+// Index returns [ErrNoValue] if there is no element at the position.
+//
+// Sources that also implement [LenSource] are decoded into slices and arrays by looking up
+// each element by its position, instead of using [Source.Iter]. Memory-mapped or columnar
+// sources can often serve elements by position much cheaper than by iteration.
+type IndexSource interface {
+	Index(idx int) (Source, error)
+}
+
+// LenSource is an optional extension of the [Source] interface for sources that know the
+// number of elements yielded by [Source.Iter] upfront, e.g. materialized arrays. The
+// [Decoder] uses the length to allocate a slice of the right capacity once, instead of
+// growing it element by element. Len returns false if the length is not known.
+type LenSource interface {
+	Len() (int, bool)
+}
+
+// StringKeyValuesSource is an optional extension of the [Source] interface for sources whose
+// keys are strings, e.g. JSON objects or maps with string keys. StringKeyValues works like
+// [Source.KeyValues], but yields the keys as strings. The [Decoder] prefers it for maps with
+// keys of a string type or a type implementing [encoding.TextUnmarshaler], which avoids
+// wrapping each key into a [Source] and decoding it using a setter. StringKeyValues returns
+// [ErrNotSupported] if the keys are not strings, in which case KeyValues is used instead.
+type StringKeyValuesSource interface {
+	StringKeyValues() (iter.Seq2[string, Source], error)
+}
@@ -0,0 +1,47 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// isSQLNullType reports whether the type is one of the nullable types of [database/sql],
+// e.g. [database/sql.NullString] or [database/sql.Null]. All of them are structs
+// holding the value in their first field, followed by a Valid flag.
+func isSQLNullType(ty reflect.Type) bool {
+	return ty.PkgPath() == "database/sql" &&
+		ty.Kind() == reflect.Struct &&
+		ty.NumField() == 2 &&
+		ty.Field(1).Name == "Valid" &&
+		ty.Field(1).Type.Kind() == reflect.Bool
+}
+
+// makeSetSQLNull returns a setter for a nullable type of [database/sql]. If the
+// source has no value, the target is reset and Valid is false.
+func (d *Decoder) makeSetSQLNull(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	valueSetter, err := d.setterOf(inConstruction, ty.Field(0).Type)
+	if err != nil {
+		return nil, err
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		// reset any previous value
+		target.SetZero()
+
+		err := valueSetter(ctx, source, target.Field(0))
+		switch {
+		case errors.Is(err, ErrNoValue):
+			target.Field(0).SetZero()
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		target.Field(1).SetBool(true)
+		return nil
+	}
+
+	return setter, nil
+}
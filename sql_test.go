@@ -0,0 +1,50 @@
+package unravel
+
+import (
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeSQLNullTypes(t *testing.T) {
+	type Row struct {
+		Name      sql.NullString          `json:"name"`
+		Age       sql.NullInt64           `json:"age"`
+		Score     sql.NullFloat64         `json:"score"`
+		Active    sql.NullBool            `json:"active"`
+		CreatedAt sql.NullTime            `json:"createdAt"`
+		Level     sql.Null[int]           `json:"level"`
+		Missing   sql.NullString          `json:"missing"`
+		Nulls     []sql.NullString        `json:"nulls"`
+		Nested    sql.Null[sql.NullInt32] `json:"nested"`
+	}
+
+	input := `{
+		"name": "Alex",
+		"age": null,
+		"score": 1.5,
+		"active": false,
+		"createdAt": "2024-05-01T12:30:00Z",
+		"level": 3,
+		"nulls": [null, "a"],
+		"nested": 7
+	}`
+
+	value, err := UnmarshalNew[Row](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	require.Equal(t, value, Row{
+		Name:      sql.NullString{String: "Alex", Valid: true},
+		Score:     sql.NullFloat64{Float64: 1.5, Valid: true},
+		Active:    sql.NullBool{Bool: false, Valid: true},
+		CreatedAt: sql.NullTime{Time: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), Valid: true},
+		Level:     sql.Null[int]{V: 3, Valid: true},
+		Nulls:     []sql.NullString{{}, {String: "a", Valid: true}},
+		Nested:    sql.Null[sql.NullInt32]{V: sql.NullInt32{Int32: 7, Valid: true}, Valid: true},
+	})
+
+	_, err = UnmarshalNew[sql.NullInt64](StringSource("abc"))
+	require.Error(t, err)
+}
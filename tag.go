@@ -0,0 +1,235 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fieldOptions holds the options parsed from an `unravel` struct tag.
+//
+// The tag starts with an optional name of the field, followed by options separated
+// by comma, e.g. `unravel:"user_name,required,default=guest"`. The name takes
+// precedence over the name given in the `json` struct tag, see [Decoder.WithTag].
+// As options may be given without a name, e.g. `unravel:"unixmilli"`, a first
+// element that is a known option is never taken as a name.
+type fieldOptions struct {
+	// Decode a time.Time from an integer epoch timestamp in this unit.
+	EpochUnit time.Duration
+
+	// Overrides the SlicePolicy of the decoder, if not nil.
+	SlicePolicy *SlicePolicy
+
+	// Overrides the MapPolicy of the decoder, if not nil.
+	MapPolicy *MapPolicy
+
+	// Decode a struct from the elements of an iterable source, in field order.
+	Tuple bool
+
+	// Alternative keys to look up if the source has no value for the fields name,
+	// e.g. `unravel:"alt=Name|user_name"`.
+	Aliases []string
+
+	// The field must have a value, like with Decoder.RequireValues.
+	Required bool
+
+	// Decoded into the field if the source has no value for it, e.g. `unravel:"default=5"`.
+	Default *string
+
+	// Layout to parse a time.Time from, e.g. `unravel:"layout=2006-01-02"`.
+	Layout string
+
+	// Collect all keys not consumed by other fields into this map field.
+	Remain bool
+}
+
+// splitUnravelTag splits an `unravel` struct tag into the name and the options.
+// The name is empty if the tag starts with an option.
+func splitUnravelTag(tag string) (name string, options []string) {
+	if tag == "" {
+		return "", nil
+	}
+
+	options = strings.Split(tag, ",")
+
+	first := strings.TrimSpace(options[0])
+	if isUnravelOption(first) {
+		return "", options
+	}
+
+	return first, options[1:]
+}
+
+// isUnravelOption reports whether the element of an `unravel` struct tag is an option.
+func isUnravelOption(element string) bool {
+	if strings.Contains(element, "=") {
+		return true
+	}
+
+	switch element {
+	case "unix", "unixmilli", "unixmicro", "unixnano",
+		"append", "replace", "reuse", "merge",
+		"tuple", "required", "inline", "remain":
+		return true
+
+	default:
+		return false
+	}
+}
+
+func parseFieldOptions(tag string) (fieldOptions, error) {
+	var opts fieldOptions
+
+	_, options := splitUnravelTag(tag)
+
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+
+		switch option {
+		case "":
+			// empty tag or trailing comma
+			continue
+
+		case "unix", "unixmilli", "unixmicro", "unixnano":
+			if opts.EpochUnit != 0 || opts.Layout != "" {
+				return fieldOptions{}, fmt.Errorf("conflicting options in unravel tag")
+			}
+
+			opts.EpochUnit = epochUnits[option]
+
+		case "append", "replace", "reuse", "merge":
+			if opts.SlicePolicy != nil || opts.MapPolicy != nil {
+				return fieldOptions{}, fmt.Errorf("conflicting options in unravel tag")
+			}
+
+			// replace applies to slices and maps
+			if policy, ok := slicePolicies[option]; ok {
+				opts.SlicePolicy = &policy
+			}
+
+			if policy, ok := mapPolicies[option]; ok {
+				opts.MapPolicy = &policy
+			}
+
+		case "tuple":
+			opts.Tuple = true
+
+		case "required":
+			opts.Required = true
+
+		case "inline", "remain":
+			opts.Remain = true
+
+		default:
+			if err := parseValueOption(&opts, option); err != nil {
+				return fieldOptions{}, err
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// parseValueOption parses an option of the form key=value into opts.
+func parseValueOption(opts *fieldOptions, option string) error {
+	key, value, ok := strings.Cut(option, "=")
+	if !ok {
+		return fmt.Errorf("unknown unravel tag option %q", option)
+	}
+
+	switch key {
+	case "alt":
+		if value == "" || opts.Aliases != nil {
+			return fmt.Errorf("invalid option %q in unravel tag", option)
+		}
+
+		opts.Aliases = strings.Split(value, "|")
+
+	case "default":
+		if opts.Default != nil {
+			return fmt.Errorf("invalid option %q in unravel tag", option)
+		}
+
+		opts.Default = &value
+
+	case "layout":
+		if value == "" || opts.Layout != "" {
+			return fmt.Errorf("invalid option %q in unravel tag", option)
+		}
+
+		if opts.EpochUnit != 0 {
+			return fmt.Errorf("conflicting options in unravel tag")
+		}
+
+		opts.Layout = value
+
+	default:
+		return fmt.Errorf("unknown unravel tag option %q", option)
+	}
+
+	return nil
+}
+
+// hasStringOption reports whether the field has the `string` option in the first of the
+// struct tags that is present, e.g. `json:"age,string"`. Like in encoding/json, the option
+// only applies to fields of bool, integer and floating point types, or pointers to them.
+func hasStringOption(field field, structTags []string) bool {
+	ty := field.Type
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	switch ty.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+
+	default:
+		return false
+	}
+
+	return slices.Contains(structTagOptions(field, structTags), "string")
+}
+
+// structTagOptions returns the options of the first of the struct tags that is present,
+// e.g. omitempty and string for `json:"age,omitempty,string"`.
+func structTagOptions(field field, structTags []string) []string {
+	for _, structTag := range structTags {
+		tag := field.Tag.Get(structTag)
+		if tag == "" {
+			continue
+		}
+
+		_, options, ok := strings.Cut(tag, ",")
+		if !ok {
+			return nil
+		}
+
+		return strings.Split(options, ",")
+	}
+
+	return nil
+}
+
+// withStringOption wraps the setter of a field with the `string` option to read the
+// value as string and decode it using a [StringSource]. Values that are not strings
+// are decoded as is.
+func withStringOption(setter fieldSetter) fieldSetter {
+	return func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		text, err := source.String()
+		switch {
+		case errors.Is(err, ErrNotSupported):
+			return setter(ctx, source, structValue, fieldValue)
+
+		case err != nil:
+			return fmt.Errorf("get string value: %w", err)
+		}
+
+		return setter(ctx, StringSource(text), structValue, fieldValue)
+	}
+}
@@ -0,0 +1,103 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnravelTag(t *testing.T) {
+	type Event struct {
+		// the unravel tag takes precedence over the json tag
+		Name    string    `json:"name" unravel:"event_name,required"`
+		Retries int       `json:"retries" unravel:",default=3"`
+		Level   string    `unravel:"level,default=info"`
+		Date    time.Time `json:"date" unravel:"layout=2006-01-02"`
+		Ignored string    `json:"ignored" unravel:"-"`
+	}
+
+	source := SourceOf(map[string]any{
+		"event_name": "deploy",
+		"name":       "ignored",
+		"level":      nil,
+		"date":       "2024-05-01",
+		"ignored":    "value",
+	})
+
+	event, err := UnmarshalNew[Event](source)
+	require.NoError(t, err)
+	require.Equal(t, event, Event{
+		Name:    "deploy",
+		Retries: 3,
+		Level:   "info",
+		Date:    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	_, err = UnmarshalNew[Event](SourceOf(map[string]any{"name": "deploy"}))
+	require.ErrorIs(t, err, ErrNoValue)
+}
+
+func TestUnravelTagOptionsWithoutName(t *testing.T) {
+	// known options are not taken as a name
+	name, options := splitUnravelTag("tuple,required")
+	require.Equal(t, name, "")
+	require.Equal(t, options, []string{"tuple", "required"})
+
+	name, options = splitUnravelTag("user,alt=User")
+	require.Equal(t, name, "user")
+	require.Equal(t, options, []string{"alt=User"})
+
+	name, _ = splitUnravelTag("default=1")
+	require.Equal(t, name, "")
+}
+
+func TestUnravelTagInvalid(t *testing.T) {
+	for _, tag := range []string{"a,unknown", "a,tuple=1", "layout=", "unix,layout=2006", "default=1,default=2"} {
+		_, err := parseFieldOptions(tag)
+		require.Error(t, err, tag)
+	}
+
+	type Invalid struct {
+		Value string `unravel:"layout=2006"`
+	}
+
+	_, err := UnmarshalNew[Invalid](SourceOf(map[string]any{}))
+	require.Error(t, err)
+}
+
+func TestStringOption(t *testing.T) {
+	type Account struct {
+		ID      uint64   `json:"id,string"`
+		Balance float64  `json:"balance,omitempty,string"`
+		Active  bool     `json:"active,string"`
+		Limit   *int     `json:"limit,string"`
+		Count   int      `json:"count,string"`
+		Tags    []string `json:"tags,string"`
+	}
+
+	input := `{
+		"id": "18446744073709551615",
+		"balance": "12.5",
+		"active": "true",
+		"limit": "10",
+		"count": 3,
+		"tags": ["a"]
+	}`
+
+	limit := 10
+
+	account, err := UnmarshalNew[Account](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, account, Account{
+		ID:      18446744073709551615,
+		Balance: 12.5,
+		Active:  true,
+		Limit:   &limit,
+		Count:   3,
+		Tags:    []string{"a"},
+	})
+
+	_, err = UnmarshalNew[Account](JSONStreamSource(strings.NewReader(`{"id": "abc"}`)))
+	require.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// makeSetTime returns a setter for [time.Time]. Strings are parsed using the layouts
+// configured on the decoder. Integers, given as number or as string, are interpreted
+// as seconds since the unix epoch.
+func (d *Decoder) makeSetTime() setter {
+	return d.makeSetTimeLayouts(d.timeLayouts)
+}
+
+// makeSetTimeLayouts works like makeSetTime but parses strings using the given layouts.
+func (d *Decoder) makeSetTimeLayouts(layouts []string) setter {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+
+	loc := d.location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		text, err := source.String()
+		switch {
+		case errors.Is(err, ErrNotSupported):
+			// not a string, try epoch seconds
+			seconds, err := source.Int()
+			if err != nil {
+				return fmt.Errorf("get time value: %w", err)
+			}
+
+			target.Set(reflect.ValueOf(time.Unix(seconds, 0).UTC()))
+			return nil
+
+		case err != nil:
+			return fmt.Errorf("get string value: %w", err)
+		}
+
+		parsed, err := parseTime(text, layouts, loc)
+		if err != nil {
+			return err
+		}
+
+		target.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+}
+
+// parseTime parses the text using the first matching layout, in the given location if the
+// text has no zone information. Falls back to interpret the text as seconds since the unix epoch.
+func parseTime(text string, layouts []string, loc *time.Location) (time.Time, error) {
+	for _, layout := range layouts {
+		parsed, err := time.ParseInLocation(layout, text, loc)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	if seconds, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("parse time %q: does not match any layout of %q", text, layouts)
+}
+
+// setDuration sets a [time.Duration]. Strings are parsed using [time.ParseDuration],
+// integers are interpreted as nanoseconds.
+func setDuration(ctx context.Context, source Source, target reflect.Value) error {
+	text, err := source.String()
+	switch {
+	case errors.Is(err, ErrNotSupported):
+		nanos, err := source.Int()
+		if err != nil {
+			return fmt.Errorf("get duration value: %w", err)
+		}
+
+		target.SetInt(nanos)
+		return nil
+
+	case err != nil:
+		return fmt.Errorf("get string value: %w", err)
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		nanos, intErr := strconv.ParseInt(text, 10, 64)
+		if intErr != nil {
+			return fmt.Errorf("parse duration: %w", err)
+		}
+
+		duration = time.Duration(nanos)
+	}
+
+	target.SetInt(int64(duration))
+	return nil
+}
+
+// epochUnits maps the epoch options of the `unravel` struct tag to their unit.
+var epochUnits = map[string]time.Duration{
+	"unix":      time.Second,
+	"unixmilli": time.Millisecond,
+	"unixmicro": time.Microsecond,
+	"unixnano":  time.Nanosecond,
+}
+
+// makeSetEpoch returns a setter that decodes a [time.Time] or a pointer to it from an
+// integer epoch timestamp in the given unit. Integers given as string are accepted too.
+func makeSetEpoch(ty reflect.Type, unit time.Duration) (setter, error) {
+	if ty.Kind() == reflect.Pointer {
+		elemSetter, err := makeSetEpoch(ty.Elem(), unit)
+		if err != nil {
+			return nil, err
+		}
+
+		setter := func(ctx context.Context, source Source, target reflect.Value) error {
+			newValue := reflect.New(ty.Elem())
+			if err := elemSetter(ctx, source, newValue.Elem()); err != nil {
+				return err
+			}
+
+			target.Set(newValue)
+			return nil
+		}
+
+		return setter, nil
+	}
+
+	if ty != tyTime {
+		return nil, fmt.Errorf("epoch timestamp requires time.Time, got %q", ty)
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		epoch, err := source.Int()
+		if errors.Is(err, ErrNotSupported) {
+			text, textErr := source.String()
+			if textErr != nil {
+				return fmt.Errorf("get epoch value: %w", err)
+			}
+
+			epoch, err = strconv.ParseInt(text, 10, 64)
+		}
+
+		if err != nil {
+			return fmt.Errorf("get epoch value: %w", err)
+		}
+
+		// split into seconds and the remainder, to not overflow time.Duration
+		perSecond := int64(time.Second / unit)
+		seconds, remainder := epoch/perSecond, epoch%perSecond
+
+		value := time.Unix(seconds, remainder*int64(unit)).UTC()
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	return setter, nil
+}
@@ -0,0 +1,132 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDecodeTime(t *testing.T) {
+	type Struct struct {
+		CreatedAt time.Time  `json:"createdAt"`
+		UpdatedAt *time.Time `json:"updatedAt"`
+		Epoch     time.Time  `json:"epoch"`
+		EpochText time.Time  `json:"epochText"`
+	}
+
+	source := SourceOf(map[string]any{
+		"createdAt": "2024-05-01T12:30:00+02:00",
+		"updatedAt": "2024-05-01T12:30:00.5Z",
+		"epoch":     60,
+		"epochText": "120",
+	})
+
+	value, err := UnmarshalNew[Struct](source)
+	require.NoError(t, err)
+
+	require.True(t, value.CreatedAt.Equal(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)))
+	require.Equal(t, *value.UpdatedAt, time.Date(2024, 5, 1, 12, 30, 0, 5e8, time.UTC))
+	require.Equal(t, value.Epoch, time.Unix(60, 0).UTC())
+	require.Equal(t, value.EpochText, time.Unix(120, 0).UTC())
+}
+
+func TestDecodeTimeWithLayouts(t *testing.T) {
+	dec := NewDecoder().WithTimeLayouts(time.DateOnly, time.DateTime)
+
+	value, err := UnmarshalNewWith[[]time.Time](dec, SourceOf([]string{"2024-05-01", "2024-05-01 12:30:00"}))
+	require.NoError(t, err)
+	require.Equal(t, value, []time.Time{
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+	})
+
+	_, err = UnmarshalNewWith[time.Time](dec, StringSource("2024-05-01T12:30:00Z"))
+	require.ErrorContains(t, err, "does not match any layout")
+
+	_, err = UnmarshalNew[time.Time](StringSource("yesterday"))
+	require.Error(t, err)
+}
+
+func TestDecodeDuration(t *testing.T) {
+	type Struct struct {
+		Timeout  time.Duration  `json:"timeout"`
+		Interval time.Duration  `json:"interval"`
+		Nanos    time.Duration  `json:"nanos"`
+		Optional *time.Duration `json:"optional"`
+	}
+
+	source := SourceOf(map[string]any{
+		"timeout":  "30s",
+		"interval": int64(time.Minute),
+		"nanos":    "1500",
+		"optional": "1h2m",
+	})
+
+	value, err := UnmarshalNew[Struct](source)
+	require.NoError(t, err)
+
+	optional := time.Hour + 2*time.Minute
+	require.Equal(t, value, Struct{
+		Timeout:  30 * time.Second,
+		Interval: time.Minute,
+		Nanos:    1500,
+		Optional: &optional,
+	})
+
+	_, err = UnmarshalNew[time.Duration](StringSource("soon"))
+	require.ErrorContains(t, err, "parse duration")
+}
+
+func TestDecodeEpochTag(t *testing.T) {
+	type Struct struct {
+		Seconds time.Time  `json:"seconds" unravel:"unix"`
+		Millis  time.Time  `json:"millis" unravel:"unixmilli"`
+		Micros  *time.Time `json:"micros" unravel:"unixmicro"`
+		Nanos   time.Time  `json:"nanos" unravel:"unixnano"`
+	}
+
+	source := SourceOf(map[string]any{
+		"seconds": 1714566600,
+		"millis":  "1714566600123",
+		"micros":  int64(1714566600123456),
+		"nanos":   int64(1714566600123456789),
+	})
+
+	value, err := UnmarshalNew[Struct](source)
+	require.NoError(t, err)
+
+	micros := time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC)
+	require.Equal(t, value, Struct{
+		Seconds: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+		Millis:  time.Date(2024, 5, 1, 12, 30, 0, 123000000, time.UTC),
+		Micros:  &micros,
+		Nanos:   time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC),
+	})
+
+	type Invalid struct {
+		Value int `unravel:"unix"`
+	}
+
+	_, err = UnmarshalNew[Invalid](source)
+	require.ErrorContains(t, err, "requires time.Time")
+
+	type Conflicting struct {
+		Value time.Time `unravel:"unix,unixmilli"`
+	}
+
+	_, err = UnmarshalNew[Conflicting](source)
+	require.ErrorContains(t, err, "conflicting options")
+}
+
+func TestDecodeTimeWithLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	dec := NewDecoder().WithTimeLayouts(time.DateTime, time.RFC3339).WithLocation(berlin)
+
+	value, err := UnmarshalNewWith[[]time.Time](dec, SourceOf([]string{"2024-05-01 12:30:00", "2024-05-01T12:30:00Z"}))
+	require.NoError(t, err)
+
+	require.Equal(t, value[0], time.Date(2024, 5, 1, 12, 30, 0, 0, berlin))
+	require.True(t, value[1].Equal(time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)))
+}
@@ -0,0 +1,67 @@
+package unravel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Transcode copies data from a [Source] into a [Sink], using the given type as schema.
+// The data is decoded into a value of type shape using [Unmarshal] and then written into
+// the sink using [Marshal]. This turns any pair of [Source] and [Sink] into a converter
+// between their formats, e.g. from environment variables into url values.
+//
+// If shape is a slice type, the elements are transcoded one after another, so the
+// full list never needs to be held in memory. This makes it possible to convert
+// large streams, e.g. read using [NDJSONSource].
+//
+// Example:
+//
+//	type Config struct {
+//	    Host string `json:"host"`
+//	    Port int    `json:"port"`
+//	}
+//
+//	values := url.Values{}
+//	sink := unravel.URLValuesSink(values, unravel.KeyStyleDots)
+//
+//	err := unravel.Transcode(source, sink, reflect.TypeFor[Config]())
+func Transcode(src Source, dst Sink, shape reflect.Type) error {
+	return TranscodeWith(&dec, &enc, src, dst, shape)
+}
+
+// TranscodeWith works like [Transcode] but uses the provided [Decoder] and [Encoder].
+func TranscodeWith(dec *Decoder, enc *Encoder, src Source, dst Sink, shape reflect.Type) error {
+	if shape.Kind() != reflect.Slice {
+		value := reflect.New(shape)
+		if err := dec.Unmarshal(src, value.Interface()); err != nil {
+			return fmt.Errorf("decode %q: %w", shape, err)
+		}
+
+		if err := enc.Marshal(value.Elem().Interface(), dst); err != nil {
+			return fmt.Errorf("encode %q: %w", shape, err)
+		}
+
+		return nil
+	}
+
+	elements, err := src.Iter()
+	if err != nil {
+		return fmt.Errorf("as iter: %w", err)
+	}
+
+	var idx int
+	for element := range elements {
+		elementSink, err := dst.Element()
+		if err != nil {
+			return fmt.Errorf("element idx=%d: %w", idx, err)
+		}
+
+		if err := TranscodeWith(dec, enc, element, elementSink, shape.Elem()); err != nil {
+			return fmt.Errorf("transcode element idx=%d: %w", idx, err)
+		}
+
+		idx++
+	}
+
+	return nil
+}
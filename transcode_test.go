@@ -0,0 +1,47 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTranscode(t *testing.T) {
+	type Config struct {
+		Host string   `json:"host"`
+		Port int      `json:"port"`
+		Tags []string `json:"tags"`
+	}
+
+	source := JSONStreamSource(strings.NewReader(`{"host": "localhost", "port": 8080, "tags": ["a", "b"], "unknown": true}`))
+
+	values := url.Values{}
+	err := Transcode(source, URLValuesSink(values, KeyStyleDots), reflect.TypeFor[Config]())
+	require.NoError(t, err)
+
+	require.Equal(t, values, url.Values{
+		"host": {"localhost"},
+		"port": {"8080"},
+		"tags": {"a", "b"},
+	})
+}
+
+func TestTranscodeSlice(t *testing.T) {
+	type Event struct {
+		Level string `json:"level"`
+	}
+
+	source := NDJSONSource(strings.NewReader("{\"level\": \"info\"}\n{\"level\": \"warn\"}\n"))
+
+	sink := dummySink{Path: "$", Values: map[string]any{}, elements: new(int)}
+
+	err := Transcode(source, sink, reflect.TypeFor[[]Event]())
+	require.NoError(t, err)
+
+	require.Equal(t, sink.Values, map[string]any{
+		"$.0.level": "info",
+		"$.1.level": "warn",
+	})
+}
@@ -0,0 +1,147 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+)
+
+// Transform cleans up the string value of a field before it is decoded,
+// see [Decoder.RegisterTransform].
+type Transform func(value string) (string, error)
+
+// builtinTransforms are available to all decoders. They can be
+// replaced using Decoder.RegisterTransform.
+var builtinTransforms = map[string]Transform{
+	"trim":  func(value string) (string, error) { return strings.TrimSpace(value), nil },
+	"lower": func(value string) (string, error) { return strings.ToLower(value), nil },
+	"upper": func(value string) (string, error) { return strings.ToUpper(value), nil },
+}
+
+// RegisterTransform returns a [Decoder] that knows the [Transform] of the given name.
+// Transforms are applied to fields tagged with `transform:"name"`, in the order they
+// are listed in the tag, e.g. `transform:"trim,lower"`. The transformed value is then
+// decoded into the field, which allows to decode "$ 12.50" into a float64 after
+// stripping the currency symbol.
+//
+// Transforms only apply to values that support [Source.String]. The transforms
+// "trim", "lower" and "upper" are always available.
+func (d *Decoder) RegisterTransform(name string, transform Transform) *Decoder {
+	derived := d.cloneWithSetters()
+	derived.transforms = maps.Clone(d.transforms)
+	if derived.transforms == nil {
+		derived.transforms = map[string]Transform{}
+	}
+
+	derived.transforms[name] = transform
+	return derived
+}
+
+// makeTransform returns a Transform applying all transforms listed in a `transform` struct tag.
+func (d *Decoder) makeTransform(tag string) (Transform, error) {
+	names := strings.Split(tag, ",")
+
+	var transforms []Transform
+
+	for idx, name := range names {
+		name = strings.TrimSpace(name)
+		names[idx] = name
+
+		transform, ok := d.transforms[name]
+		if !ok {
+			transform, ok = builtinTransforms[name]
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("unknown transform %q", name)
+		}
+
+		transforms = append(transforms, transform)
+	}
+
+	combined := func(value string) (string, error) {
+		for idx, transform := range transforms {
+			var err error
+
+			value, err = transform(value)
+			if err != nil {
+				return "", fmt.Errorf("transform %q: %w", names[idx], err)
+			}
+		}
+
+		return value, nil
+	}
+
+	return combined, nil
+}
+
+// withTransform wraps the setter of a field to transform its string value before
+// decoding it using a [StringSource]. Values that are not strings are decoded as is.
+func withTransform(transform Transform, setter fieldSetter) fieldSetter {
+	return func(ctx context.Context, source Source, structValue, fieldValue reflect.Value) error {
+		text, err := source.String()
+		switch {
+		case errors.Is(err, ErrNotSupported):
+			return setter(ctx, source, structValue, fieldValue)
+
+		case err != nil:
+			return fmt.Errorf("get string value: %w", err)
+		}
+
+		text, err = transform(text)
+		if err != nil {
+			return err
+		}
+
+		return setter(ctx, StringSource(text), structValue, fieldValue)
+	}
+}
+
+// TrimSpace returns a [Decoder] that trims leading and trailing whitespace from all
+// strings, before they are decoded into fields of string, bool or numeric types. This
+// also allows parsing numbers with stray whitespace, e.g. " 42" from a CSV file.
+//
+// Only values known to be strings are trimmed: values of a [StringSource] and of sources
+// implementing [KindSource] that report [KindString].
+//
+// Values of types using a custom setter, see [Decoder.RegisterSetter], are not trimmed.
+func (d *Decoder) TrimSpace() *Decoder {
+	if d.trimSpace {
+		return d
+	}
+
+	derived := d.clone()
+	derived.trimSpace = true
+	return derived
+}
+
+// isScalarKind reports whether values of the kind are trimmed by Decoder.TrimSpace.
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// withTrimSpace wraps the setter to decode the trimmed string value using a [StringSource].
+// Values not known to be strings are decoded as is, see stringValueOf. The value is read
+// once, the setter decodes the trimmed copy.
+func withTrimSpace(setter setter) setter {
+	return func(ctx context.Context, source Source, target reflect.Value) error {
+		text, ok := stringValueOf(source)
+		if !ok {
+			return setter(ctx, source, target)
+		}
+
+		return setter(ctx, StringSource(strings.TrimSpace(text)), target)
+	}
+}
@@ -0,0 +1,86 @@
+package unravel
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	type Order struct {
+		Email    string  `json:"email" transform:"trim,lower"`
+		Country  string  `json:"country" transform:"upper"`
+		Price    float64 `json:"price" transform:"currency, trim"`
+		Quantity int     `json:"quantity" transform:"trim"`
+	}
+
+	stripCurrency := func(value string) (string, error) {
+		if !strings.HasPrefix(value, "$") {
+			return "", errors.New("no dollar sign")
+		}
+
+		return strings.TrimPrefix(value, "$"), nil
+	}
+
+	dec := NewDecoder().RegisterTransform("currency", stripCurrency)
+
+	source := SourceOf(map[string]any{
+		"email":    "  Alex@Example.COM ",
+		"country":  "de",
+		"price":    "$ 12.50",
+		"quantity": 3,
+	})
+
+	order, err := UnmarshalNewWith[Order](dec, source)
+	require.NoError(t, err)
+	require.Equal(t, order, Order{Email: "alex@example.com", Country: "DE", Price: 12.5, Quantity: 3})
+
+	// errors of a transform are returned
+	_, err = UnmarshalNewWith[Order](dec, SourceOf(map[string]any{"price": "12.50"}))
+	require.ErrorContains(t, err, `transform "currency": no dollar sign`)
+
+	// the currency transform is not known to the default decoder
+	_, err = UnmarshalNew[Order](source)
+	require.ErrorContains(t, err, `unknown transform "currency"`)
+}
+
+func TestDecoderTrimSpace(t *testing.T) {
+	type Row struct {
+		Name   string   `json:"name"`
+		Age    int      `json:"age"`
+		Score  float64  `json:"score"`
+		Active bool     `json:"active"`
+		Tags   []string `json:"tags"`
+	}
+
+	source := SourceOf(map[string]any{
+		"name":   "  Alex\t",
+		"age":    " 21 ",
+		"score":  "1.5 ",
+		"active": " true",
+		"tags":   []string{" a", "b "},
+	})
+
+	row, err := UnmarshalNewWith[Row](NewDecoder().TrimSpace(), source)
+	require.NoError(t, err)
+	require.Equal(t, row, Row{Name: "Alex", Age: 21, Score: 1.5, Active: true, Tags: []string{"a", "b"}})
+
+	// numbers with whitespace can not be parsed by default
+	_, err = UnmarshalNew[Row](source)
+	require.Error(t, err)
+}
+
+func TestDecoderTrimSpaceConsumingSource(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  int
+	}
+
+	// values of unknown kind are decoded as is, without reading them twice
+	source := &tokenSource{tokens: []string{"alex", "42"}}
+
+	person, err := UnmarshalNewWith[Person](NewDecoder().TrimSpace(), source)
+	require.NoError(t, err)
+	require.Equal(t, person, Person{Name: "alex", Age: 42})
+}
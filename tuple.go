@@ -0,0 +1,93 @@
+package unravel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// makeSetTuple returns a setter decoding a struct from the elements of an iterable
+// source. Elements are assigned to the fields of the struct in declaration order, e.g.
+// the JSON array [52.5, 13.4] to the fields Lat and Lon. A slice of structs decodes
+// each element as a tuple.
+//
+// Tuples are enabled per field using the `unravel` struct tag, e.g. `unravel:"tuple"`.
+func (d *Decoder) makeSetTuple(inConstruction typeSet, ty reflect.Type) (setter, error) {
+	if ty.Kind() == reflect.Slice && ty.Elem().Kind() == reflect.Struct {
+		elementSetter, err := d.makeSetTuple(inConstruction, ty.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return d.makeSetSliceOf(ty, elementSetter), nil
+	}
+
+	if ty.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("tuple on type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+
+	fields, err := d.fieldsOf(ty)
+	if err != nil {
+		return nil, err
+	}
+
+	setters := make([]setter, len(fields))
+	for idx, field := range fields {
+		fieldSetter, err := d.setterOf(inConstruction, field.Type)
+		if err != nil {
+			return nil, fieldSetterError(err, ty, field.Name)
+		}
+
+		setters[idx] = fieldSetter
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		sourceIter, err := iterContext(ctx, source)
+		if err != nil {
+			return fmt.Errorf("as iter: %w", err)
+		}
+
+		var count int
+
+		for elementSource := range sourceIter {
+			idx := count
+			if idx >= len(fields) {
+				return fmt.Errorf("tuple %q has %d fields: too many elements", target.Type(), len(fields))
+			}
+
+			count++
+
+			field := fields[idx]
+
+			fieldValue, err := fieldByIndexAlloc(target, field.Index)
+			if err != nil {
+				return withPath(err, indexSegment(idx), field.Type)
+			}
+
+			err = setters[idx](ctx, elementSource, fieldValue)
+			switch {
+			case errors.Is(err, ErrNoValue) && isNull(elementSource):
+				// an explicit null value is handled like a missing value
+				if d.requireValues && !isOptionalType(field.Type) {
+					return withPath(err, indexSegment(idx), field.Type)
+				}
+
+			case err != nil:
+				return withPath(err, indexSegment(idx), field.Type)
+			}
+		}
+
+		if d.requireValues {
+			for idx, field := range fields[count:] {
+				if !isOptionalType(field.Type) {
+					return withPath(ErrNoValue, indexSegment(count+idx), field.Type)
+				}
+			}
+		}
+
+		return nil
+	}
+
+	return setter, nil
+}
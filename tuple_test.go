@@ -0,0 +1,57 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTuple(t *testing.T) {
+	type Coordinate struct {
+		Lat float64
+		Lon float64
+	}
+
+	type Reply struct {
+		Command string `json:"-"`
+		Key     string
+		Value   int
+	}
+
+	type Route struct {
+		Start Coordinate   `json:"start" unravel:"tuple"`
+		Stops []Coordinate `json:"stops" unravel:"tuple"`
+		Reply Reply        `json:"reply" unravel:"tuple"`
+		Short Coordinate   `json:"short" unravel:"tuple"`
+	}
+
+	input := `{
+		"start": [52.5, 13.4],
+		"stops": [[48.1, 11.6], [53.6, 10.0]],
+		"reply": ["counter", 12],
+		"short": [1.5]
+	}`
+
+	route, err := UnmarshalNew[Route](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, route, Route{
+		Start: Coordinate{Lat: 52.5, Lon: 13.4},
+		Stops: []Coordinate{{Lat: 48.1, Lon: 11.6}, {Lat: 53.6, Lon: 10.0}},
+		Reply: Reply{Key: "counter", Value: 12},
+		Short: Coordinate{Lat: 1.5},
+	})
+
+	// missing elements are required if the decoder requires values
+	_, err = UnmarshalNewWith[Route](NewDecoder().RequireValues(), SourceOf(map[string]any{"short": []float64{1.5}}))
+	require.ErrorIs(t, err, ErrNoValue)
+
+	_, err = UnmarshalNew[Route](SourceOf(map[string]any{"start": []float64{1, 2, 3}}))
+	require.ErrorContains(t, err, "too many elements")
+
+	type Invalid struct {
+		Value int `unravel:"tuple"`
+	}
+
+	_, err = UnmarshalNew[Invalid](SourceOf(map[string]any{}))
+	require.ErrorAs(t, err, &NotSupportedError{})
+}
@@ -0,0 +1,155 @@
+package unravel
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+var tyString = reflect.TypeFor[string]()
+
+// RegisterVariant returns a [Decoder] that knows the concrete type variant as the variant
+// called name of the interface type iface. Fields of type iface, or slices of iface, tagged
+// with `union:"key"` are decoded by first reading the discriminator with the given key from
+// the [Source] of the value. The value is then decoded into a new value of the variant
+// registered for the discriminator and assigned to the field. The discriminator itself is
+// only decoded into the variant if the variant has a field for it.
+//
+// RegisterVariant panics if iface is not an interface or variant does not implement it.
+func (d *Decoder) RegisterVariant(iface reflect.Type, name string, variant reflect.Type) *Decoder {
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("type %q is not an interface", iface))
+	}
+
+	if !variant.Implements(iface) {
+		panic(fmt.Sprintf("type %q does not implement %q", variant, iface))
+	}
+
+	derived := d.clone()
+	derived.variants = maps.Clone(d.variants)
+	if derived.variants == nil {
+		derived.variants = map[reflect.Type]map[string]reflect.Type{}
+	}
+
+	derived.variants[iface] = maps.Clone(d.variants[iface])
+	if derived.variants[iface] == nil {
+		derived.variants[iface] = map[string]reflect.Type{}
+	}
+
+	derived.variants[iface][name] = variant
+	return derived
+}
+
+// RegisterUnionVariant works like [Decoder.RegisterVariant], registering the concrete
+// type `T` as variant of the interface type `I`.
+//
+// Example:
+//
+//	type Envelope struct {
+//	    ID    string `json:"id"`
+//	    Event Event  `json:"event" union:"type"`
+//	}
+//
+//	dec := unravel.NewDecoder()
+//	dec = unravel.RegisterUnionVariant[Event, *ClickEvent](dec, "click")
+//	dec = unravel.RegisterUnionVariant[Event, *ScrollEvent](dec, "scroll")
+//
+//	// decodes {"id": "1", "event": {"type": "click", "x": 10, "y": 20}}
+//	envelope, err := unravel.UnmarshalNewWith[Envelope](dec, source)
+func RegisterUnionVariant[I, T any](dec *Decoder, name string) *Decoder {
+	return dec.RegisterVariant(reflect.TypeFor[I](), name, reflect.TypeFor[T]())
+}
+
+// makeSetUnion returns a setter for a field tagged with `union:"key"`.
+func (d *Decoder) makeSetUnion(inConstruction typeSet, ty reflect.Type, key string) (setter, error) {
+	if ty.Kind() == reflect.Slice && ty.Elem().Kind() == reflect.Interface {
+		elementSetter, err := d.makeSetUnion(inConstruction, ty.Elem(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.makeSetSliceOf(ty, elementSetter), nil
+	}
+
+	if ty.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("union on type %q: %w", ty, NotSupportedError{Type: ty})
+	}
+
+	if len(d.variants[ty]) == 0 {
+		return nil, fmt.Errorf("union on type %q: no variants registered", ty)
+	}
+
+	type Variant struct {
+		Type   reflect.Type
+		Setter setter
+
+		// the decoded value is copied into the interface by Set
+		Scratch *scratchValues
+	}
+
+	variants := map[string]Variant{}
+
+	for name, variantType := range d.variants[ty] {
+		variantSetter, err := d.setterOf(inConstruction, variantType)
+		if err != nil {
+			return nil, fmt.Errorf("setter for variant %q: %w", name, err)
+		}
+
+		variants[name] = Variant{Type: variantType, Setter: variantSetter, Scratch: newScratchValues(variantType)}
+	}
+
+	setter := func(ctx context.Context, source Source, target reflect.Value) error {
+		if isNull(source) {
+			target.SetZero()
+			return nil
+		}
+
+		discriminatorSource, err := getContext(ctx, source, key)
+		if err != nil {
+			return withPath(fmt.Errorf("lookup discriminator: %w", err), keySegment(key), tyString)
+		}
+
+		discriminator, err := discriminatorSource.String()
+		if err != nil {
+			return withPath(fmt.Errorf("get discriminator: %w", err), keySegment(key), tyString)
+		}
+
+		variant, ok := variants[discriminator]
+		if !ok {
+			return fmt.Errorf("no variant for %s=%q: %w", key, discriminator, ErrNotSupported)
+		}
+
+		// the discriminator might not be readable a second time, e.g. from a stream
+		source = discriminatedSource{Source: source, Context: ctx, Key: key, Discriminator: discriminator}
+
+		value := variant.Scratch.Get()
+		defer variant.Scratch.Put(value)
+
+		if err := variant.Setter(ctx, source, value); err != nil {
+			return fmt.Errorf("variant %q: %w", discriminator, err)
+		}
+
+		target.Set(value)
+		return nil
+	}
+
+	return setter, nil
+}
+
+// discriminatedSource returns the already decoded discriminator of a union
+// when its key is requested, and delegates to the wrapped [Source] otherwise.
+type discriminatedSource struct {
+	Source
+	Context       context.Context
+	Key           string
+	Discriminator string
+}
+
+func (d discriminatedSource) Get(key string) (Source, error) {
+	if key == d.Key {
+		return StringSource(d.Discriminator), nil
+	}
+
+	return getContext(d.Context, d.Source, key)
+}
@@ -0,0 +1,84 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+type unionEvent interface {
+	EventName() string
+}
+
+type clickEvent struct {
+	Type string `json:"type"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+func (c *clickEvent) EventName() string {
+	return "click"
+}
+
+type scrollEvent struct {
+	Delta float64 `json:"delta"`
+}
+
+func (s scrollEvent) EventName() string {
+	return "scroll"
+}
+
+func TestDecodeUnion(t *testing.T) {
+	type Envelope struct {
+		ID      string       `json:"id"`
+		Event   unionEvent   `json:"event" union:"type"`
+		History []unionEvent `json:"history" union:"type"`
+		Missing unionEvent   `json:"missing" union:"type"`
+	}
+
+	dec := NewDecoder()
+	dec = RegisterUnionVariant[unionEvent, *clickEvent](dec, "click")
+	dec = RegisterUnionVariant[unionEvent, scrollEvent](dec, "scroll")
+
+	// the discriminator is not the first key of the event
+	input := `{
+		"id": "1",
+		"event": {"x": 10, "y": 20, "type": "click"},
+		"history": [{"type": "scroll", "delta": 1.5}, {"type": "click"}],
+		"missing": null
+	}`
+
+	envelope, err := UnmarshalNewWith[Envelope](dec, JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+
+	require.Equal(t, envelope, Envelope{
+		ID:    "1",
+		Event: &clickEvent{Type: "click", X: 10, Y: 20},
+		History: []unionEvent{
+			scrollEvent{Delta: 1.5},
+			&clickEvent{Type: "click"},
+		},
+	})
+}
+
+func TestDecodeUnionErrors(t *testing.T) {
+	type Envelope struct {
+		Event unionEvent `json:"event" union:"type"`
+	}
+
+	dec := RegisterUnionVariant[unionEvent, *clickEvent](NewDecoder(), "click")
+
+	_, err := UnmarshalNewWith[Envelope](dec, JSONStreamSource(strings.NewReader(`{"event": {"type": "drag"}}`)))
+	require.ErrorIs(t, err, ErrNotSupported)
+
+	_, err = UnmarshalNewWith[Envelope](dec, JSONStreamSource(strings.NewReader(`{"event": {"x": 1}}`)))
+	require.ErrorIs(t, err, ErrNoValue)
+
+	// no variants are registered on the default decoder
+	_, err = UnmarshalNew[Envelope](JSONStreamSource(strings.NewReader(`{}`)))
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		RegisterUnionVariant[unionEvent, clickEvent](NewDecoder(), "click")
+	})
+}
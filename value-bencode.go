@@ -0,0 +1,295 @@
+package unravel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+)
+
+// ErrInvalidBencode is returned by [BencodeSource] if the input is not valid bencode.
+var ErrInvalidBencode = errors.New("invalid bencode")
+
+// BencodeSource parses bencode encoded data, as used for BitTorrent metainfo files, and
+// returns a [Source] for the top level value. The data is parsed completely upfront and
+// an error wrapping [ErrInvalidBencode] is returned if it is malformed.
+//
+// The bencode data model maps to the [Source] interface as follows:
+//   - Integers are accessible using [Source.Int], [Source.Uint] and [Source.Float].
+//     The integers 0 and 1 can also be read using [Source.Bool].
+//   - Byte strings are accessible using [Source.String].
+//   - Lists are accessible using [Source.Iter].
+//   - Dictionaries are accessible using [Source.Get] and [Source.KeyValues].
+//
+// Example:
+//
+//	type Torrent struct {
+//	    Announce string `json:"announce"`
+//	    Info     struct {
+//	        Name        string `json:"name"`
+//	        PieceLength int64  `json:"piece length"`
+//	    } `json:"info"`
+//	}
+//
+//	source, err := unravel.BencodeSource(content)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	torrent, err := unravel.UnmarshalNew[Torrent](source)
+func BencodeSource(data []byte) (Source, error) {
+	p := bencodeParser{data: data}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.data) {
+		return nil, fmt.Errorf("trailing data at offset %d: %w", p.pos, ErrInvalidBencode)
+	}
+
+	return value, nil
+}
+
+// bencodeValue is a single parsed bencode value.
+type bencodeValue struct {
+	kind bencodeKind
+
+	integer int64
+	str     string
+	list    []bencodeValue
+	dict    []bencodeEntry
+}
+
+type bencodeEntry struct {
+	Key   string
+	Value bencodeValue
+}
+
+type bencodeKind uint8
+
+const (
+	bencodeInteger bencodeKind = iota
+	bencodeString
+	bencodeList
+	bencodeDict
+)
+
+var _ Source = bencodeValue{}
+var _ BytesSource = bencodeValue{}
+
+func (b bencodeValue) Bool() (bool, error) {
+	if b.kind == bencodeInteger && (b.integer == 0 || b.integer == 1) {
+		return b.integer == 1, nil
+	}
+
+	return false, ErrNotSupported
+}
+
+func (b bencodeValue) Int() (int64, error) {
+	if b.kind != bencodeInteger {
+		return 0, ErrNotSupported
+	}
+
+	return b.integer, nil
+}
+
+func (b bencodeValue) Uint() (uint64, error) {
+	if b.kind != bencodeInteger {
+		return 0, ErrNotSupported
+	}
+
+	if b.integer < 0 {
+		return 0, fmt.Errorf("invalid uint64 value %d: %w", b.integer, strconv.ErrRange)
+	}
+
+	return uint64(b.integer), nil
+}
+
+func (b bencodeValue) Float() (float64, error) {
+	if b.kind != bencodeInteger {
+		return 0, ErrNotSupported
+	}
+
+	return float64(b.integer), nil
+}
+
+func (b bencodeValue) String() (string, error) {
+	if b.kind != bencodeString {
+		return "", ErrNotSupported
+	}
+
+	return b.str, nil
+}
+
+func (b bencodeValue) Bytes() ([]byte, error) {
+	if b.kind != bencodeString {
+		return nil, ErrNotSupported
+	}
+
+	return []byte(b.str), nil
+}
+
+func (b bencodeValue) Get(key string) (Source, error) {
+	if b.kind != bencodeDict {
+		return nil, ErrNotSupported
+	}
+
+	for _, entry := range b.dict {
+		if entry.Key == key {
+			return entry.Value, nil
+		}
+	}
+
+	return nil, ErrNoValue
+}
+
+func (b bencodeValue) KeyValues() (iter.Seq2[Source, Source], error) {
+	if b.kind != bencodeDict {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(Source, Source) bool) {
+		for _, entry := range b.dict {
+			if !yield(StringSource(entry.Key), entry.Value) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (b bencodeValue) Iter() (iter.Seq[Source], error) {
+	if b.kind != bencodeList {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(Source) bool) {
+		for _, value := range b.list {
+			if !yield(value) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+type bencodeParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *bencodeParser) parseValue() (bencodeValue, error) {
+	if p.pos >= len(p.data) {
+		return bencodeValue{}, fmt.Errorf("unexpected end of input: %w", ErrInvalidBencode)
+	}
+
+	switch ch := p.data[p.pos]; {
+	case ch == 'i':
+		p.pos++
+
+		integer, err := p.parseInteger('e')
+		if err != nil {
+			return bencodeValue{}, err
+		}
+
+		return bencodeValue{kind: bencodeInteger, integer: integer}, nil
+
+	case ch >= '0' && ch <= '9':
+		str, err := p.parseString()
+		if err != nil {
+			return bencodeValue{}, err
+		}
+
+		return bencodeValue{kind: bencodeString, str: str}, nil
+
+	case ch == 'l':
+		p.pos++
+
+		var list []bencodeValue
+		for !p.consumeEnd() {
+			value, err := p.parseValue()
+			if err != nil {
+				return bencodeValue{}, err
+			}
+
+			list = append(list, value)
+		}
+
+		return bencodeValue{kind: bencodeList, list: list}, nil
+
+	case ch == 'd':
+		p.pos++
+
+		var dict []bencodeEntry
+		for !p.consumeEnd() {
+			key, err := p.parseString()
+			if err != nil {
+				return bencodeValue{}, fmt.Errorf("dictionary key: %w", err)
+			}
+
+			value, err := p.parseValue()
+			if err != nil {
+				return bencodeValue{}, err
+			}
+
+			dict = append(dict, bencodeEntry{Key: key, Value: value})
+		}
+
+		return bencodeValue{kind: bencodeDict, dict: dict}, nil
+
+	default:
+		return bencodeValue{}, fmt.Errorf("unexpected %q at offset %d: %w", ch, p.pos, ErrInvalidBencode)
+	}
+}
+
+// consumeEnd consumes the 'e' terminating a list or dictionary. Returns false
+// if the next byte is not an 'e', or if the end of the input was reached.
+func (p *bencodeParser) consumeEnd() bool {
+	if p.pos < len(p.data) && p.data[p.pos] == 'e' {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+// parseInteger parses a decimal integer terminated by the given delimiter.
+func (p *bencodeParser) parseInteger(delimiter byte) (int64, error) {
+	end := bytes.IndexByte(p.data[p.pos:], delimiter)
+	if end < 0 {
+		return 0, fmt.Errorf("unterminated integer at offset %d: %w", p.pos, ErrInvalidBencode)
+	}
+
+	text := string(p.data[p.pos : p.pos+end])
+
+	integer, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse integer %q: %w", text, errors.Join(err, ErrInvalidBencode))
+	}
+
+	p.pos += end + 1
+
+	return integer, nil
+}
+
+// parseString parses a length prefixed byte string.
+func (p *bencodeParser) parseString() (string, error) {
+	length, err := p.parseInteger(':')
+	if err != nil {
+		return "", err
+	}
+
+	if length < 0 || length > int64(len(p.data)-p.pos) {
+		return "", fmt.Errorf("invalid string length %d: %w", length, ErrInvalidBencode)
+	}
+
+	str := string(p.data[p.pos : p.pos+int(length)])
+	p.pos += int(length)
+
+	return str, nil
+}
@@ -0,0 +1,69 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBencodeSource(t *testing.T) {
+	type Torrent struct {
+		Announce     string     `json:"announce"`
+		AnnounceList [][]string `json:"announce-list"`
+		Private      bool       `json:"private"`
+		Info         struct {
+			Name        string `json:"name"`
+			PieceLength int64  `json:"piece length"`
+			Files       []struct {
+				Length uint64   `json:"length"`
+				Path   []string `json:"path"`
+			} `json:"files"`
+		} `json:"info"`
+		Extra map[string]int `json:"extra"`
+	}
+
+	content := "d" +
+		"8:announce" + "17:http://tracker/an" +
+		"13:announce-list" + "ll3:onee" + "l3:two5:threeee" +
+		"5:extra" + "d1:ai-1e1:bi2ee" +
+		"4:info" + "d" +
+		"5:files" + "l" + "d6:lengthi42e4:pathl1:a5:b.txteee" +
+		"4:name" + "4:test" +
+		"12:piece length" + "i16384e" +
+		"e" +
+		"7:private" + "i1e" +
+		"e"
+
+	source, err := BencodeSource([]byte(content))
+	require.NoError(t, err)
+
+	torrent, err := UnmarshalNew[Torrent](source)
+	require.NoError(t, err)
+
+	require.Equal(t, torrent.Announce, "http://tracker/an")
+	require.Equal(t, torrent.AnnounceList, [][]string{{"one"}, {"two", "three"}})
+	require.True(t, torrent.Private)
+	require.Equal(t, torrent.Info.Name, "test")
+	require.Equal(t, torrent.Info.PieceLength, int64(16384))
+	require.Len(t, torrent.Info.Files, 1)
+	require.Equal(t, torrent.Info.Files[0].Length, uint64(42))
+	require.Equal(t, torrent.Info.Files[0].Path, []string{"a", "b.txt"})
+	require.Equal(t, torrent.Extra, map[string]int{"a": -1, "b": 2})
+}
+
+func TestBencodeSourceInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"i42",
+		"ixe",
+		"5:abc",
+		"l1:a",
+		"di1ei2ee",
+		"i1ei2e",
+		"x",
+	}
+
+	for _, input := range inputs {
+		_, err := BencodeSource([]byte(input))
+		require.ErrorIs(t, err, ErrInvalidBencode, "input %q", input)
+	}
+}
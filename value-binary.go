@@ -0,0 +1,320 @@
+package unravel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"iter"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// BinaryReader implements the [BinarySource] interface on top of an [io.Reader]. Every
+// access to a value consumes the number of bytes required for the requested type from the
+// underlying reader, decoding multi-byte values using the configured [binary.ByteOrder].
+//
+// As binary data has no notion of field names, [BinaryReader.Get] returns the reader itself:
+// struct fields are decoded one after another in the order they are declared. Slices are
+// decoded by [BinaryReader.Iter] until the underlying reader is exhausted, arrays such as
+// `[4]byte` consume exactly as many elements as the array holds.
+//
+// Use [BinaryReaderSource] to create a new instance.
+//
+// Example:
+//
+//	type Header struct {
+//	    Magic   [4]byte
+//	    Version uint16
+//	    Scale   float32
+//	}
+//
+//	source := unravel.BinaryReaderSource(file, binary.BigEndian)
+//	header, err := unravel.UnmarshalNew[Header](source)
+type BinaryReader struct {
+	r     io.Reader
+	order binary.ByteOrder
+
+	// a byte that was read ahead by Iter to detect the end of the input
+	peeked    byte
+	hasPeeked bool
+
+	// active recordings, every byte read is appended to each of them
+	recordings []*bytes.Buffer
+}
+
+var _ Source = &BinaryReader{}
+var _ BinarySource = &BinaryReader{}
+var _ VarintSource = &BinaryReader{}
+var _ ReadBytesSource = &BinaryReader{}
+var _ SeekerSource = &BinaryReader{}
+var _ RecordingSource = &BinaryReader{}
+
+// BinaryReaderSource returns a new [BinaryReader] that reads values from r, using the given
+// byte order to decode multi-byte values.
+func BinaryReaderSource(r io.Reader, order binary.ByteOrder) *BinaryReader {
+	return &BinaryReader{r: r, order: order}
+}
+
+func (b *BinaryReader) Int8() (int8, error) {
+	value, err := b.Uint8()
+	return int8(value), err
+}
+
+func (b *BinaryReader) Int16() (int16, error) {
+	value, err := b.Uint16()
+	return int16(value), err
+}
+
+func (b *BinaryReader) Int32() (int32, error) {
+	value, err := b.Uint32()
+	return int32(value), err
+}
+
+func (b *BinaryReader) Int64() (int64, error) {
+	value, err := b.Uint64()
+	return int64(value), err
+}
+
+func (b *BinaryReader) Uint8() (uint8, error) {
+	var buf [1]byte
+	if err := b.read(buf[:]); err != nil {
+		return 0, err
+	}
+
+	return buf[0], nil
+}
+
+func (b *BinaryReader) Uint16() (uint16, error) {
+	var buf [2]byte
+	if err := b.read(buf[:]); err != nil {
+		return 0, err
+	}
+
+	return b.order.Uint16(buf[:]), nil
+}
+
+func (b *BinaryReader) Uint32() (uint32, error) {
+	var buf [4]byte
+	if err := b.read(buf[:]); err != nil {
+		return 0, err
+	}
+
+	return b.order.Uint32(buf[:]), nil
+}
+
+func (b *BinaryReader) Uint64() (uint64, error) {
+	var buf [8]byte
+	if err := b.read(buf[:]); err != nil {
+		return 0, err
+	}
+
+	return b.order.Uint64(buf[:]), nil
+}
+
+func (b *BinaryReader) Float32() (float32, error) {
+	bits, err := b.Uint32()
+	return math.Float32frombits(bits), err
+}
+
+func (b *BinaryReader) Float64() (float64, error) {
+	bits, err := b.Uint64()
+	return math.Float64frombits(bits), err
+}
+
+func (b *BinaryReader) Varint() (int64, error) {
+	return binary.ReadVarint(byteReader{b})
+}
+
+func (b *BinaryReader) Uvarint() (uint64, error) {
+	return binary.ReadUvarint(byteReader{b})
+}
+
+func (b *BinaryReader) ReadBytes(n uint64) ([]byte, error) {
+	if n == 0 {
+		return []byte{}, nil
+	}
+
+	if n > math.MaxInt64 {
+		return nil, &OverflowError{Value: n, TargetType: reflect.TypeFor[int64]()}
+	}
+
+	// do not trust n to allocate the buffer upfront, it might be
+	// much larger than the remaining input.
+	var buf bytes.Buffer
+
+	if b.hasPeeked {
+		buf.WriteByte(b.peeked)
+		b.hasPeeked = false
+		n--
+	}
+
+	if _, err := io.CopyN(&buf, b.r, int64(n)); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	b.record(buf.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// Seek sets the offset for the next read, see [io.Seeker]. Returns [ErrNotSupported]
+// if the underlying reader does not implement [io.Seeker]. While a recording is active,
+// see [BinaryReader.Record], skipping forward reads the skipped bytes, so that they are
+// recorded independent of whether the underlying reader can seek.
+func (b *BinaryReader) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := b.r.(io.Seeker)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	if len(b.recordings) > 0 && whence == io.SeekCurrent && offset >= 0 {
+		if err := b.discard(offset); err != nil {
+			return 0, err
+		}
+
+		return seeker.Seek(0, io.SeekCurrent)
+	}
+
+	if b.hasPeeked && whence == io.SeekCurrent {
+		// the underlying reader is one byte ahead
+		offset--
+	}
+
+	b.hasPeeked = false
+
+	return seeker.Seek(offset, whence)
+}
+
+// discard reads and drops count bytes.
+func (b *BinaryReader) discard(count int64) error {
+	var buf [512]byte
+
+	for count > 0 {
+		chunk := buf[:min(count, int64(len(buf)))]
+		if err := b.read(chunk); err != nil {
+			return err
+		}
+
+		count -= int64(len(chunk))
+	}
+
+	return nil
+}
+
+func (b *BinaryReader) Record() (stop func() []byte) {
+	recording := &bytes.Buffer{}
+	b.recordings = append(b.recordings, recording)
+
+	var stopped bool
+
+	return func() []byte {
+		if !stopped {
+			stopped = true
+			b.recordings = slices.DeleteFunc(b.recordings, func(r *bytes.Buffer) bool { return r == recording })
+		}
+
+		return recording.Bytes()
+	}
+}
+
+// record appends buf to all active recordings.
+func (b *BinaryReader) record(buf []byte) {
+	for _, recording := range b.recordings {
+		recording.Write(buf)
+	}
+}
+
+// Bool reads a single byte. Any value other than zero is interpreted as true.
+func (b *BinaryReader) Bool() (bool, error) {
+	value, err := b.Uint8()
+	return value != 0, err
+}
+
+func (b *BinaryReader) Int() (int64, error) {
+	return b.Int64()
+}
+
+func (b *BinaryReader) Uint() (uint64, error) {
+	return b.Uint64()
+}
+
+func (b *BinaryReader) Float() (float64, error) {
+	return b.Float64()
+}
+
+// String is not supported, as a plain binary stream does not encode the length of a string.
+func (b *BinaryReader) String() (string, error) {
+	return "", ErrNotSupported
+}
+
+// Get returns the reader itself, fields are read sequentially from the stream.
+func (b *BinaryReader) Get(key string) (Source, error) {
+	return b, nil
+}
+
+func (b *BinaryReader) KeyValues() (iter.Seq2[Source, Source], error) {
+	return nil, ErrNotSupported
+}
+
+// Iter yields the reader itself for every element until the underlying reader is exhausted.
+func (b *BinaryReader) Iter() (iter.Seq[Source], error) {
+	it := func(yield func(Source) bool) {
+		for b.more() {
+			if !yield(b) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// more reports whether there is at least one more byte available to read.
+func (b *BinaryReader) more() bool {
+	if b.hasPeeked {
+		return true
+	}
+
+	var buf [1]byte
+	if _, err := io.ReadFull(b.r, buf[:]); err != nil {
+		return false
+	}
+
+	b.peeked, b.hasPeeked = buf[0], true
+	return true
+}
+
+// read fills buf completely from the underlying reader.
+func (b *BinaryReader) read(buf []byte) error {
+	if len(buf) == 0 {
+		return nil
+	}
+
+	if !b.hasPeeked {
+		if _, err := io.ReadFull(b.r, buf); err != nil {
+			return err
+		}
+
+		b.record(buf)
+		return nil
+	}
+
+	buf[0], b.hasPeeked = b.peeked, false
+
+	// we already consumed a byte, hitting the end of input now is unexpected
+	if _, err := io.ReadFull(b.r, buf[1:]); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+
+		return err
+	}
+
+	b.record(buf)
+	return nil
+}
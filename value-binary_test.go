@@ -0,0 +1,66 @@
+package unravel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestBinaryReaderSourceByteOrder(t *testing.T) {
+	type Struct struct {
+		Magic   [4]byte
+		Version uint16
+		Scale   float32
+		Offset  float64
+		Enabled bool
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("UNRV")
+	_ = binary.Write(&buf, binary.BigEndian, uint16(3))
+	_ = binary.Write(&buf, binary.BigEndian, float32(1.5))
+	_ = binary.Write(&buf, binary.BigEndian, float64(-0.25))
+	buf.WriteByte(1)
+
+	parsed, err := UnmarshalNew[Struct](BinaryReaderSource(&buf, binary.BigEndian))
+	require.NoError(t, err)
+	require.Equal(t, parsed, Struct{
+		Magic:   [4]byte{'U', 'N', 'R', 'V'},
+		Version: 3,
+		Scale:   1.5,
+		Offset:  -0.25,
+		Enabled: true,
+	})
+}
+
+func TestBinaryReaderSourceIterStopsAtEOF(t *testing.T) {
+	source := BinaryReaderSource(bytes.NewReader([]byte{1, 0, 2, 0, 3, 0}), binary.LittleEndian)
+
+	parsed, err := UnmarshalNew[[]uint16](source)
+	require.NoError(t, err)
+	require.Equal(t, parsed, []uint16{1, 2, 3})
+}
+
+func TestBinaryReaderSourceUnexpectedEOF(t *testing.T) {
+	source := BinaryReaderSource(bytes.NewReader([]byte{1, 0, 2}), binary.LittleEndian)
+
+	_, err := UnmarshalNew[[]uint16](source)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestBinaryReaderSourceReadBytesOverflow(t *testing.T) {
+	source := BinaryReaderSource(bytes.NewReader(nil), binary.LittleEndian)
+
+	_, err := source.ReadBytes(math.MaxUint64)
+	require.ErrorIs(t, err, strconv.ErrRange)
+
+	var overflowErr *OverflowError
+	require.ErrorAs(t, err, &overflowErr)
+	require.Equal(t, overflowErr.Value, uint64(math.MaxUint64))
+	require.Equal(t, overflowErr.TargetType, reflect.TypeFor[int64]())
+}
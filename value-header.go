@@ -0,0 +1,202 @@
+package unravel
+
+import (
+	"iter"
+	"mime"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// MIMEHeaderSource returns a [Source] over the fields of a MIME header, as found in
+// emails, HTTP and multipart messages. Keys passed to [Source.Get] are canonicalized
+// using [textproto.CanonicalMIMEHeaderKey], so `json:"content-type"` and
+// `json:"Content-Type"` both refer to the same header field.
+//
+// The value of a header field provides the following accessors:
+//   - [Source.String] returns the first value with RFC 2047 encoded words decoded.
+//     Date fields (Date and Resent-Date) are parsed using [mail.ParseDate] and returned
+//     in RFC 3339 format, so they can be decoded into a [time.Time].
+//   - Numbers and booleans are parsed from the first value using [StringSource].
+//   - [Source.Iter] yields the addresses if the field holds an address list, such as
+//     the To or Cc fields of an email. Otherwise, it yields every value of the field.
+//
+// Each address yielded by [Source.Iter] can be decoded into a [mail.Address] or any
+// struct with Name and Address fields. Read as a string, an address returns
+// just the email address. A field holding a single address, such as the From field
+// of an email, can be decoded into a [mail.Address] directly.
+//
+// Example:
+//
+//	type Envelope struct {
+//	    From    *mail.Address  `json:"from"`
+//	    To      []string       `json:"to"`
+//	    Subject string         `json:"subject"`
+//	    Date    time.Time      `json:"date"`
+//	}
+//
+//	msg, _ := mail.ReadMessage(r)
+//	envelope, err := unravel.UnmarshalNew[Envelope](unravel.MIMEHeaderSource(textproto.MIMEHeader(msg.Header)))
+func MIMEHeaderSource(header textproto.MIMEHeader) Source {
+	return mimeHeaderSource{header: header}
+}
+
+// MailHeaderSource works like [MIMEHeaderSource] for a [mail.Header].
+func MailHeaderSource(header mail.Header) Source {
+	return mimeHeaderSource{header: textproto.MIMEHeader(header)}
+}
+
+type mimeHeaderSource struct {
+	EmptySource
+	header textproto.MIMEHeader
+}
+
+func (m mimeHeaderSource) Get(key string) (Source, error) {
+	key = textproto.CanonicalMIMEHeaderKey(key)
+
+	values := m.header[key]
+	if len(values) == 0 {
+		return nil, ErrNoValue
+	}
+
+	return headerFieldSource{key: key, values: values}, nil
+}
+
+func (m mimeHeaderSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	it := func(yield func(Source, Source) bool) {
+		for key, values := range m.header {
+			if len(values) == 0 {
+				continue
+			}
+
+			if !yield(StringSource(key), headerFieldSource{key: key, values: values}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// headerFieldSource holds all values of a single header field.
+type headerFieldSource struct {
+	EmptySource
+	key    string
+	values []string
+}
+
+func (h headerFieldSource) first() StringSource {
+	return StringSource(strings.TrimSpace(h.values[0]))
+}
+
+func (h headerFieldSource) Bool() (bool, error) {
+	return h.first().Bool()
+}
+
+func (h headerFieldSource) Int() (int64, error) {
+	return h.first().Int()
+}
+
+func (h headerFieldSource) Uint() (uint64, error) {
+	return h.first().Uint()
+}
+
+func (h headerFieldSource) Float() (float64, error) {
+	return h.first().Float()
+}
+
+func (h headerFieldSource) String() (string, error) {
+	value := string(h.first())
+
+	if h.key == "Date" || h.key == "Resent-Date" {
+		date, err := mail.ParseDate(value)
+		if err != nil {
+			return "", err
+		}
+
+		return date.Format(time.RFC3339Nano), nil
+	}
+
+	var decoder mime.WordDecoder
+
+	decoded, err := decoder.DecodeHeader(value)
+	if err != nil {
+		// not a valid encoded word, keep the value as is
+		return value, nil
+	}
+
+	return decoded, nil
+}
+
+// Get interprets the field as a single address, see [addressSource].
+func (h headerFieldSource) Get(key string) (Source, error) {
+	address, err := mail.ParseAddress(h.values[0])
+	if err != nil {
+		return nil, ErrNotSupported
+	}
+
+	return addressSource{address: address}.Get(key)
+}
+
+func (h headerFieldSource) Iter() (iter.Seq[Source], error) {
+	var addresses []*mail.Address
+
+	for _, value := range h.values {
+		parsed, err := mail.ParseAddressList(value)
+		if err != nil {
+			// not an address list, iterate over the raw values
+			addresses = nil
+			break
+		}
+
+		addresses = append(addresses, parsed...)
+	}
+
+	it := func(yield func(Source) bool) {
+		if addresses == nil {
+			for _, value := range h.values {
+				if !yield(headerFieldSource{key: h.key, values: []string{value}}) {
+					return
+				}
+			}
+
+			return
+		}
+
+		for _, address := range addresses {
+			if !yield(addressSource{address: address}) {
+				return
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// addressSource is a single parsed email address.
+type addressSource struct {
+	EmptySource
+	address *mail.Address
+}
+
+func (a addressSource) String() (string, error) {
+	return a.address.Address, nil
+}
+
+func (a addressSource) Get(key string) (Source, error) {
+	switch key {
+	case "Name", "name":
+		if a.address.Name == "" {
+			return nil, ErrNoValue
+		}
+
+		return StringSource(a.address.Name), nil
+
+	case "Address", "address":
+		return StringSource(a.address.Address), nil
+
+	default:
+		return nil, ErrNoValue
+	}
+}
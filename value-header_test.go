@@ -0,0 +1,64 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMailHeaderSource(t *testing.T) {
+	message := "From: Albert Einstein <albert@example.com>\r\n" +
+		"To: bob@example.com, \"Carol C.\" <carol@example.com>\r\n" +
+		"Cc: dave@example.com\r\n" +
+		"Subject: =?utf-8?q?Gr=C3=BC=C3=9Fe?=\r\n" +
+		"Date: Mon, 02 Jan 2006 15:04:05 -0700\r\n" +
+		"X-Priority: 3\r\n" +
+		"Received: from a\r\n" +
+		"Received: from b\r\n" +
+		"\r\n" +
+		"body"
+
+	msg, err := mail.ReadMessage(strings.NewReader(message))
+	require.NoError(t, err)
+
+	type Envelope struct {
+		From     *mail.Address   `json:"from"`
+		To       []string        `json:"to"`
+		Cc       []*mail.Address `json:"cc"`
+		Subject  string          `json:"subject"`
+		Date     time.Time       `json:"date"`
+		Priority int             `json:"x-priority"`
+		Received []string        `json:"received"`
+		Missing  string          `json:"x-missing"`
+	}
+
+	envelope, err := UnmarshalNew[Envelope](MailHeaderSource(msg.Header))
+	require.NoError(t, err)
+
+	require.Equal(t, envelope.From, &mail.Address{Name: "Albert Einstein", Address: "albert@example.com"})
+	require.Equal(t, envelope.To, []string{"bob@example.com", "carol@example.com"})
+	require.Equal(t, envelope.Cc, []*mail.Address{{Address: "dave@example.com"}})
+	require.Equal(t, envelope.Subject, "Grüße")
+	require.True(t, envelope.Date.Equal(time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)))
+	require.Equal(t, envelope.Priority, 3)
+	require.Equal(t, envelope.Received, []string{"from a", "from b"})
+	require.Empty(t, envelope.Missing)
+}
+
+func TestMIMEHeaderSource(t *testing.T) {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "text/plain")
+	header.Set("Content-Length", "42")
+
+	type Part struct {
+		ContentType   string `json:"content-type"`
+		ContentLength int64  `json:"Content-Length"`
+	}
+
+	part, err := UnmarshalNew[Part](MIMEHeaderSource(header))
+	require.NoError(t, err)
+	require.Equal(t, part, Part{ContentType: "text/plain", ContentLength: 42})
+}
@@ -0,0 +1,272 @@
+package unravel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+)
+
+// jsonValue adapts a value produced by [encoding/json] when decoding into `any`
+// with [json.Decoder.UseNumber] enabled to the [Source] interface.
+type jsonValue struct {
+	Value any
+
+	// the raw JSON the value was decoded from, if available
+	raw json.RawMessage
+}
+
+var _ Source = jsonValue{}
+var _ RawSource = jsonValue{}
+var _ NullableSource = jsonValue{}
+var _ KindSource = jsonValue{}
+var _ IndexSource = jsonValue{}
+var _ LenSource = jsonValue{}
+var _ StringKeyValuesSource = jsonValue{}
+
+// jsonValueOf decodes the raw JSON value into a jsonValue.
+func jsonValueOf(raw json.RawMessage) (jsonValue, error) {
+	var value any
+	if err := unmarshalJSONNumber(raw, &value); err != nil {
+		return jsonValue{}, err
+	}
+
+	return jsonValue{Value: value, raw: raw}, nil
+}
+
+func (j jsonValue) Kind() ValueKind {
+	switch j.Value.(type) {
+	case nil:
+		return KindNull
+	case bool:
+		return KindBool
+	case json.Number:
+		return KindNumber
+	case string:
+		return KindString
+	case map[string]any:
+		return KindObject
+	case []any:
+		return KindArray
+	default:
+		return KindUnknown
+	}
+}
+
+func (j jsonValue) IsNull() bool {
+	return j.Value == nil
+}
+
+func (j jsonValue) Bool() (bool, error) {
+	if j.Value == nil {
+		// explicit null
+		return false, ErrNoValue
+	}
+
+	boolValue, ok := j.Value.(bool)
+	if !ok {
+		return false, ErrNotSupported
+	}
+
+	return boolValue, nil
+}
+
+func (j jsonValue) Int() (int64, error) {
+	if j.Value == nil {
+		// explicit null
+		return 0, ErrNoValue
+	}
+
+	number, ok := j.Value.(json.Number)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return parseJSONInt(number)
+}
+
+func (j jsonValue) Uint() (uint64, error) {
+	if j.Value == nil {
+		// explicit null
+		return 0, ErrNoValue
+	}
+
+	number, ok := j.Value.(json.Number)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return parseJSONUint(number)
+}
+
+func (j jsonValue) Float() (float64, error) {
+	if j.Value == nil {
+		// explicit null
+		return 0, ErrNoValue
+	}
+
+	number, ok := j.Value.(json.Number)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return parseJSONFloat(number)
+}
+
+func (j jsonValue) String() (string, error) {
+	if j.Value == nil {
+		// explicit null
+		return "", ErrNoValue
+	}
+
+	stringValue, ok := j.Value.(string)
+	if !ok {
+		return "", ErrNotSupported
+	}
+
+	return stringValue, nil
+}
+
+func (j jsonValue) Get(key string) (Source, error) {
+	if j.Value == nil {
+		return nil, ErrNoValue
+	}
+
+	object, ok := j.Value.(map[string]any)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	value, ok := object[key]
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	return jsonValue{Value: value}, nil
+}
+
+func (j jsonValue) KeyValues() (iter.Seq2[Source, Source], error) {
+	if j.Value == nil {
+		return nil, ErrNoValue
+	}
+
+	object, ok := j.Value.(map[string]any)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(Source, Source) bool) {
+		for key, value := range object {
+			if !yield(StringSource(key), jsonValue{Value: value}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (j jsonValue) StringKeyValues() (iter.Seq2[string, Source], error) {
+	if j.Value == nil {
+		return nil, ErrNoValue
+	}
+
+	object, ok := j.Value.(map[string]any)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(string, Source) bool) {
+		for key, value := range object {
+			if !yield(key, jsonValue{Value: value}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (j jsonValue) Iter() (iter.Seq[Source], error) {
+	if j.Value == nil {
+		return nil, ErrNoValue
+	}
+
+	array, ok := j.Value.([]any)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(Source) bool) {
+		for _, value := range array {
+			if !yield(jsonValue{Value: value}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (j jsonValue) Len() (int, bool) {
+	array, ok := j.Value.([]any)
+	return len(array), ok
+}
+
+func (j jsonValue) Index(idx int) (Source, error) {
+	if j.Value == nil {
+		return nil, ErrNoValue
+	}
+
+	array, ok := j.Value.([]any)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	if idx < 0 || idx >= len(array) {
+		return nil, ErrNoValue
+	}
+
+	return jsonValue{Value: array[idx]}, nil
+}
+
+func (j jsonValue) Raw() ([]byte, error) {
+	if j.raw != nil {
+		return j.raw, nil
+	}
+
+	raw, err := json.Marshal(j.Value)
+	if err != nil {
+		return nil, fmt.Errorf("encode json: %w", err)
+	}
+
+	return raw, nil
+}
+
+func parseJSONInt(number json.Number) (int64, error) {
+	intValue, err := strconv.ParseInt(string(number), 10, 64)
+	return handleSyntaxErr(string(number), intValue, err)
+}
+
+func parseJSONUint(number json.Number) (uint64, error) {
+	intValue, err := strconv.ParseUint(string(number), 10, 64)
+	return handleSyntaxErr(string(number), intValue, err)
+}
+
+func parseJSONFloat(number json.Number) (float64, error) {
+	floatValue, err := strconv.ParseFloat(string(number), 64)
+	return handleSyntaxErr(string(number), floatValue, err)
+}
+
+// unmarshalJSONNumber works like [json.Unmarshal] but keeps numbers as [json.Number].
+func unmarshalJSONNumber(raw json.RawMessage, target any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if err := dec.Decode(target); err != nil {
+		return fmt.Errorf("decode json: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,574 @@
+package unravel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+// JSONStreamSource returns a [Source] that lazily reads a JSON document from r using
+// the tokenizer of [json.Decoder]. The document is never materialized as a whole, which
+// makes it possible to decode very large documents, e.g. a huge top level array decoded
+// element by element.
+//
+// The source reads the document strictly front to back:
+//   - [Source.Get] skips forward to the requested key of an object. Values of keys
+//     skipped on the way are buffered in memory, so they are still available if requested
+//     later. Decoding a struct whose fields are declared in a different order than the
+//     keys of the document therefore buffers the values of the keys read out of order.
+//   - [Source.KeyValues] yields the buffered values first, in document order, followed
+//     by the remaining keys of the object.
+//   - [Source.Iter] streams the elements of an array.
+//   - Values after the last key requested by the [Decoder], and elements not accessed
+//     by the [Decoder], are skipped without buffering them.
+//
+// As the source consumes its input, a value can only be decoded once. Explicit JSON null
+// values are reported as [ErrNoValue].
+//
+// Example:
+//
+//	source := unravel.JSONStreamSource(file)
+//
+//	events, err := unravel.UnmarshalNew[[]Event](source)
+func JSONStreamSource(r io.Reader) Source {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	return &jsonStreamValue{stream: &jsonStream{dec: dec}}
+}
+
+// jsonStream wraps a [json.Decoder] and tracks the nesting depth of the current token.
+type jsonStream struct {
+	dec   *json.Decoder
+	depth int
+}
+
+func (s *jsonStream) token() (json.Token, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("read json token: %w", err)
+	}
+
+	switch tok {
+	case json.Delim('{'), json.Delim('['):
+		s.depth++
+	case json.Delim('}'), json.Delim(']'):
+		s.depth--
+	}
+
+	return tok, nil
+}
+
+// next starts reading the next value from the stream.
+func (s *jsonStream) next() (*jsonStreamValue, error) {
+	value := &jsonStreamValue{stream: s}
+	if err := value.load(); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// jsonStreamValue is a single value within a json stream. The first token of a value
+// is read when the value is loaded, object and array values are consumed incrementally.
+type jsonStreamValue struct {
+	stream *jsonStream
+
+	loaded bool
+	first  json.Token
+
+	// depth of the stream before the first token was read
+	parentDepth int
+
+	// the child value most recently handed out by Get or Iter
+	child *jsonStreamValue
+
+	// true once the closing delimiter of an object or array was consumed
+	closed bool
+
+	// values of object keys that were skipped while looking for another key,
+	// in document order
+	skipped []skippedJSONValue
+}
+
+// skippedJSONValue is the buffered value of an object key, see jsonStreamValue.Get.
+type skippedJSONValue struct {
+	key string
+	raw json.RawMessage
+}
+
+var _ Source = &jsonStreamValue{}
+var _ RawSource = &jsonStreamValue{}
+var _ NullableSource = &jsonStreamValue{}
+var _ KindSource = &jsonStreamValue{}
+
+func (j *jsonStreamValue) load() error {
+	if j.loaded {
+		return nil
+	}
+
+	j.parentDepth = j.stream.depth
+
+	tok, err := j.stream.token()
+	if err != nil {
+		return err
+	}
+
+	j.first, j.loaded = tok, true
+	return nil
+}
+
+// finish consumes the remaining tokens of this value from the stream.
+func (j *jsonStreamValue) finish() error {
+	if err := j.load(); err != nil {
+		return err
+	}
+
+	for j.stream.depth > j.parentDepth {
+		if _, err := j.stream.token(); err != nil {
+			return err
+		}
+	}
+
+	j.closed = true
+	return nil
+}
+
+// finishChild skips whatever is left of the previously returned child value.
+func (j *jsonStreamValue) finishChild() error {
+	if j.child == nil {
+		return nil
+	}
+
+	child := j.child
+	j.child = nil
+
+	return child.finish()
+}
+
+func (j *jsonStreamValue) scalar() (json.Token, error) {
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	if j.first == nil {
+		return nil, ErrNoValue
+	}
+
+	return j.first, nil
+}
+
+func (j *jsonStreamValue) Kind() ValueKind {
+	if err := j.load(); err != nil {
+		// let the decoder run into the error
+		return KindUnknown
+	}
+
+	switch j.first {
+	case nil:
+		return KindNull
+	case json.Delim('{'):
+		return KindObject
+	case json.Delim('['):
+		return KindArray
+	}
+
+	switch j.first.(type) {
+	case bool:
+		return KindBool
+	case json.Number, float64:
+		return KindNumber
+	case string:
+		return KindString
+	default:
+		return KindUnknown
+	}
+}
+
+func (j *jsonStreamValue) IsNull() bool {
+	if err := j.load(); err != nil {
+		// let the decoder run into the error
+		return false
+	}
+
+	return j.first == nil
+}
+
+func (j *jsonStreamValue) Bool() (bool, error) {
+	tok, err := j.scalar()
+	if err != nil {
+		return false, err
+	}
+
+	boolValue, ok := tok.(bool)
+	if !ok {
+		return false, ErrNotSupported
+	}
+
+	return boolValue, nil
+}
+
+func (j *jsonStreamValue) Int() (int64, error) {
+	tok, err := j.scalar()
+	if err != nil {
+		return 0, err
+	}
+
+	number, ok := tok.(json.Number)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return parseJSONInt(number)
+}
+
+func (j *jsonStreamValue) Uint() (uint64, error) {
+	tok, err := j.scalar()
+	if err != nil {
+		return 0, err
+	}
+
+	number, ok := tok.(json.Number)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return parseJSONUint(number)
+}
+
+func (j *jsonStreamValue) Float() (float64, error) {
+	tok, err := j.scalar()
+	if err != nil {
+		return 0, err
+	}
+
+	number, ok := tok.(json.Number)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+
+	return parseJSONFloat(number)
+}
+
+func (j *jsonStreamValue) String() (string, error) {
+	tok, err := j.scalar()
+	if err != nil {
+		return "", err
+	}
+
+	stringValue, ok := tok.(string)
+	if !ok {
+		return "", ErrNotSupported
+	}
+
+	return stringValue, nil
+}
+
+func (j *jsonStreamValue) Get(key string) (Source, error) {
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	if j.first == nil {
+		return nil, ErrNoValue
+	}
+
+	if j.first != json.Delim('{') {
+		return nil, ErrNotSupported
+	}
+
+	if idx := slices.IndexFunc(j.skipped, func(skipped skippedJSONValue) bool { return skipped.key == key }); idx >= 0 {
+		raw := j.skipped[idx].raw
+		j.skipped = slices.Delete(j.skipped, idx, idx+1)
+		return jsonSourceOfRaw(raw)
+	}
+
+	if err := j.finishChild(); err != nil {
+		return nil, err
+	}
+
+	for !j.closed {
+		currentKey, ok, err := j.nextKey()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			break
+		}
+
+		if currentKey == key {
+			child, err := j.stream.next()
+			if err != nil {
+				return nil, err
+			}
+
+			j.child = child
+			return child, nil
+		}
+
+		// buffer the value of the skipped key. A duplicate key replaces the
+		// previous value, like encoding/json does
+		var raw json.RawMessage
+		if err := j.stream.dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("skip value of %q: %w", currentKey, err)
+		}
+
+		idx := slices.IndexFunc(j.skipped, func(skipped skippedJSONValue) bool { return skipped.key == currentKey })
+		if idx >= 0 {
+			j.skipped[idx].raw = raw
+		} else {
+			j.skipped = append(j.skipped, skippedJSONValue{key: currentKey, raw: raw})
+		}
+	}
+
+	return nil, ErrNoValue
+}
+
+// nextKey reads the next key of an object. Returns false if the end
+// of the object was reached.
+func (j *jsonStreamValue) nextKey() (string, bool, error) {
+	if !j.stream.dec.More() {
+		// consume the closing delimiter
+		if _, err := j.stream.token(); err != nil {
+			return "", false, err
+		}
+
+		j.closed = true
+		return "", false, nil
+	}
+
+	tok, err := j.stream.token()
+	if err != nil {
+		return "", false, err
+	}
+
+	return tok.(string), true, nil
+}
+
+func (j *jsonStreamValue) KeyValues() (iter.Seq2[Source, Source], error) {
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	if j.first == nil {
+		return nil, ErrNoValue
+	}
+
+	if j.first != json.Delim('{') {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(Source, Source) bool) {
+		for len(j.skipped) > 0 {
+			skipped := j.skipped[0]
+			j.skipped = j.skipped[1:]
+
+			source, err := jsonSourceOfRaw(skipped.raw)
+			if err != nil {
+				source = errorSource{err}
+			}
+
+			if !yield(StringSource(skipped.key), source) {
+				return
+			}
+		}
+
+		for !j.closed {
+			if err := j.finishChild(); err != nil {
+				yield(errorSource{err}, errorSource{err})
+				return
+			}
+
+			key, ok, err := j.nextKey()
+			if err != nil {
+				yield(errorSource{err}, errorSource{err})
+				return
+			}
+
+			if !ok {
+				return
+			}
+
+			child, err := j.stream.next()
+			if err != nil {
+				yield(errorSource{err}, errorSource{err})
+				return
+			}
+
+			j.child = child
+
+			if !yield(StringSource(key), child) {
+				return
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (j *jsonStreamValue) Iter() (iter.Seq[Source], error) {
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	if j.first == nil {
+		return nil, ErrNoValue
+	}
+
+	if j.first != json.Delim('[') {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(Source) bool) {
+		for !j.closed {
+			if err := j.finishChild(); err != nil {
+				yield(errorSource{err})
+				return
+			}
+
+			if !j.stream.dec.More() {
+				if _, err := j.stream.token(); err != nil {
+					yield(errorSource{err})
+					return
+				}
+
+				j.closed = true
+				return
+			}
+
+			child, err := j.stream.next()
+			if err != nil {
+				yield(errorSource{err})
+				return
+			}
+
+			j.child = child
+
+			if !yield(child) {
+				return
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// Raw re-encodes the tokens of this value. Objects and arrays that were already
+// partially consumed using Get, KeyValues or Iter are not supported.
+func (j *jsonStreamValue) Raw() ([]byte, error) {
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := j.first.(json.Delim); !ok {
+		return json.Marshal(j.first)
+	}
+
+	if j.child != nil || len(j.skipped) > 0 || j.closed {
+		return nil, ErrNotSupported
+	}
+
+	var buf bytes.Buffer
+
+	// number of tokens written within each open object or array
+	type frame struct {
+		object bool
+		count  int
+	}
+
+	stack := []frame{{object: j.first == json.Delim('{')}}
+	buf.WriteString(j.first.(json.Delim).String())
+
+	for j.stream.depth > j.parentDepth {
+		tok, err := j.stream.token()
+		if err != nil {
+			return nil, err
+		}
+
+		top := &stack[len(stack)-1]
+
+		if tok == json.Delim('}') || tok == json.Delim(']') {
+			buf.WriteString(tok.(json.Delim).String())
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		switch {
+		case top.object && top.count%2 == 1:
+			// a value within an object follows its key
+			buf.WriteByte(':')
+		case top.count > 0:
+			buf.WriteByte(',')
+		}
+
+		top.count++
+
+		if delim, ok := tok.(json.Delim); ok {
+			buf.WriteString(delim.String())
+			stack = append(stack, frame{object: delim == json.Delim('{')})
+			continue
+		}
+
+		encoded, err := json.Marshal(tok)
+		if err != nil {
+			return nil, fmt.Errorf("encode json: %w", err)
+		}
+
+		buf.Write(encoded)
+	}
+
+	j.closed = true
+	return buf.Bytes(), nil
+}
+
+// jsonSourceOfRaw returns a source for a buffered raw JSON value.
+func jsonSourceOfRaw(raw json.RawMessage) (Source, error) {
+	value, err := jsonValueOf(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// errorSource is a [Source] that fails every access with the same error. It is used
+// to report errors that occur while iterating, where an error can not be returned directly.
+type errorSource struct {
+	err error
+}
+
+func (e errorSource) Bool() (bool, error) {
+	return false, e.err
+}
+
+func (e errorSource) Int() (int64, error) {
+	return 0, e.err
+}
+
+func (e errorSource) Uint() (uint64, error) {
+	return 0, e.err
+}
+
+func (e errorSource) Float() (float64, error) {
+	return 0, e.err
+}
+
+func (e errorSource) String() (string, error) {
+	return "", e.err
+}
+
+func (e errorSource) Get(key string) (Source, error) {
+	return nil, e.err
+}
+
+func (e errorSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	return nil, e.err
+}
+
+func (e errorSource) Iter() (iter.Seq[Source], error) {
+	return nil, e.err
+}
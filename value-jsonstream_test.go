@@ -0,0 +1,112 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestJSONStreamSource(t *testing.T) {
+	type Event struct {
+		ID      int               `json:"id"`
+		Kind    string            `json:"kind"`
+		Tags    []string          `json:"tags"`
+		Score   *float64          `json:"score"`
+		Labels  map[string]string `json:"labels"`
+		Enabled bool              `json:"enabled"`
+	}
+
+	input := `[
+		{"kind": "create", "ignored": {"deeply": [1, 2, {"nested": true}]}, "id": 1, "tags": ["a", "b"], "enabled": true},
+		{"id": 2, "kind": "delete", "score": 0.5, "labels": {"x": "y", "z": null}},
+		{"id": 3, "kind": "update", "score": null}
+	]`
+
+	score := 0.5
+
+	events, err := UnmarshalNew[[]Event](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, events, []Event{
+		{ID: 1, Kind: "create", Tags: []string{"a", "b"}, Enabled: true},
+		{ID: 2, Kind: "delete", Score: &score, Labels: map[string]string{"x": "y"}},
+		{ID: 3, Kind: "update"},
+	})
+}
+
+func TestJSONStreamSourceIsLazy(t *testing.T) {
+	type Header struct {
+		Version int `json:"version"`
+	}
+
+	// the document is truncated after the version, the source
+	// must not read further than required.
+	input := `{"version": 3, "items": [1, 2,`
+
+	header, err := UnmarshalNew[Header](JSONStreamSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, header, Header{Version: 3})
+}
+
+func TestJSONStreamSourceSyntaxError(t *testing.T) {
+	_, err := UnmarshalNew[[]int](JSONStreamSource(strings.NewReader(`[1, 2, }`)))
+	require.Error(t, err)
+}
+
+func TestJSONStreamSourceExplicitNull(t *testing.T) {
+	type Patch struct {
+		Name    *string        `json:"name"`
+		Email   *string        `json:"email"`
+		Phone   *string        `json:"phone"`
+		Age     Optional[*int] `json:"age"`
+		Missing *string        `json:"missing"`
+	}
+
+	name, email, phone, missing := "Alex", "alex@example.com", "555", "keep"
+
+	patch := Patch{Name: &name, Email: &email, Phone: &phone, Missing: &missing}
+
+	// the key "missing" is looked up last, it forces email to be buffered
+	input := `{"name": null, "phone": "123", "email": null, "age": null}`
+
+	err := Unmarshal(JSONStreamSource(strings.NewReader(input)), &patch)
+	require.NoError(t, err)
+
+	require.Nil(t, patch.Name)
+	require.Nil(t, patch.Email)
+	require.Equal(t, *patch.Phone, "123")
+	require.Equal(t, patch.Age, Optional[*int]{})
+	require.Equal(t, *patch.Missing, "keep")
+}
+
+func TestJSONStreamSourceBufferedKeys(t *testing.T) {
+	source := JSONStreamSource(strings.NewReader(`{"a": 1, "b": [2], "c": 3, "d": 4, "e": {"x": 5}, "f": 6, "g": 7}`))
+
+	// the values of the keys before f are buffered
+	value, err := source.Get("f")
+	require.NoError(t, err)
+
+	intValue, err := value.Int()
+	require.NoError(t, err)
+	require.Equal(t, intValue, int64(6))
+
+	value, err = source.Get("c")
+	require.NoError(t, err)
+
+	intValue, err = value.Int()
+	require.NoError(t, err)
+	require.Equal(t, intValue, int64(3))
+
+	keyValues, err := source.KeyValues()
+	require.NoError(t, err)
+
+	// buffered values are yielded in document order, followed by the remaining keys
+	var keys []string
+	for keySource := range keyValues {
+		key, err := keySource.String()
+		require.NoError(t, err)
+
+		keys = append(keys, key)
+	}
+
+	require.Equal(t, keys, []string{"a", "b", "d", "e", "g"})
+}
@@ -0,0 +1,180 @@
+package unravel
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// ErrTopicMismatch is returned by [MQTTSource] if a topic does not match the topic pattern.
+var ErrTopicMismatch = errors.New("topic does not match pattern")
+
+// MQTTSource returns a [Source] for an MQTT message. The source is an object with two
+// keys: "topic" provides access to the topic the message was published to, "payload"
+// returns the given payload source, e.g. a source decoding the payload as JSON.
+//
+// Segments of the topic are addressable by their zero based position, e.g. "0", "1", and
+// by name. Names are declared using the pattern, an MQTT topic filter where wildcards
+// can be followed by a name: a single level wildcard `+name` matches exactly one segment,
+// a multi level wildcard `#name` matches all remaining segments. A multi level value is
+// accessible as a string, joined by "/", or as a list of segments. Wildcards without a
+// name only match. An empty pattern matches every topic.
+//
+// The topic can also be read as a string, returning the full topic, or iterated,
+// yielding each segment in order.
+//
+// An error wrapping [ErrTopicMismatch] is returned if the topic does not match the pattern.
+//
+// Example:
+//
+//	type Reading struct {
+//	    Topic struct {
+//	        Building string `json:"building"`
+//	        Sensor   string `json:"sensor"`
+//	    } `json:"topic"`
+//
+//	    Payload struct {
+//	        Temperature float64 `json:"temperature"`
+//	    } `json:"payload"`
+//	}
+//
+//	payload := unravel.JSONStreamSource(bytes.NewReader(msg.Payload()))
+//
+//	source, err := unravel.MQTTSource("sites/+building/sensors/+sensor", msg.Topic(), payload)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	reading, err := unravel.UnmarshalNew[Reading](source)
+func MQTTSource(pattern, topic string, payload Source) (Source, error) {
+	segments := strings.Split(topic, "/")
+
+	named, err := matchTopic(pattern, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	source := mqttSource{
+		topic:   mqttTopicSource{segments: segments, named: named},
+		payload: payload,
+	}
+
+	return source, nil
+}
+
+// matchTopic matches the segments of a topic against the pattern and returns the
+// values captured by named wildcards.
+func matchTopic(pattern string, segments []string) (map[string]Source, error) {
+	named := map[string]Source{}
+
+	if pattern == "" {
+		return named, nil
+	}
+
+	filter := strings.Split(pattern, "/")
+
+	for idx, level := range filter {
+		switch {
+		case strings.HasPrefix(level, "#"):
+			if idx != len(filter)-1 {
+				return nil, fmt.Errorf("multi level wildcard must be last in pattern %q", pattern)
+			}
+
+			if name := level[1:]; name != "" {
+				named[name] = mqttTopicSource{segments: segments[min(idx, len(segments)):]}
+			}
+
+			return named, nil
+
+		case idx >= len(segments):
+			return nil, fmt.Errorf("topic %q, pattern %q: %w", strings.Join(segments, "/"), pattern, ErrTopicMismatch)
+
+		case strings.HasPrefix(level, "+"):
+			if name := level[1:]; name != "" {
+				named[name] = StringSource(segments[idx])
+			}
+
+		case level != segments[idx]:
+			return nil, fmt.Errorf("topic %q, pattern %q: %w", strings.Join(segments, "/"), pattern, ErrTopicMismatch)
+		}
+	}
+
+	if len(filter) != len(segments) {
+		return nil, fmt.Errorf("topic %q, pattern %q: %w", strings.Join(segments, "/"), pattern, ErrTopicMismatch)
+	}
+
+	return named, nil
+}
+
+type mqttSource struct {
+	EmptySource
+	topic   mqttTopicSource
+	payload Source
+}
+
+func (m mqttSource) Get(key string) (Source, error) {
+	switch key {
+	case "topic":
+		return m.topic, nil
+
+	case "payload":
+		if m.payload == nil {
+			return nil, ErrNoValue
+		}
+
+		return m.payload, nil
+
+	default:
+		return nil, ErrNoValue
+	}
+}
+
+// mqttTopicSource provides access to the segments of a topic.
+type mqttTopicSource struct {
+	EmptySource
+	segments []string
+	named    map[string]Source
+}
+
+func (m mqttTopicSource) String() (string, error) {
+	return strings.Join(m.segments, "/"), nil
+}
+
+func (m mqttTopicSource) Get(key string) (Source, error) {
+	if value, ok := m.named[key]; ok {
+		return value, nil
+	}
+
+	idx, err := strconv.Atoi(key)
+	if err != nil || idx < 0 || idx >= len(m.segments) {
+		return nil, ErrNoValue
+	}
+
+	return StringSource(m.segments[idx]), nil
+}
+
+func (m mqttTopicSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	it := func(yield func(Source, Source) bool) {
+		for name, value := range m.named {
+			if !yield(StringSource(name), value) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (m mqttTopicSource) Iter() (iter.Seq[Source], error) {
+	it := func(yield func(Source) bool) {
+		for _, segment := range m.segments {
+			if !yield(StringSource(segment)) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
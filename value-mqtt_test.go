@@ -0,0 +1,79 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestMQTTSource(t *testing.T) {
+	type Reading struct {
+		Topic struct {
+			Site     string   `json:"0"`
+			Building string   `json:"building"`
+			Sensor   string   `json:"sensor"`
+			Rest     []string `json:"rest"`
+		} `json:"topic"`
+
+		Payload struct {
+			Temperature float64 `json:"temperature"`
+		} `json:"payload"`
+	}
+
+	payload := JSONStreamSource(strings.NewReader(`{"temperature": 21.5}`))
+
+	source, err := MQTTSource("sites/+building/sensors/+sensor/#rest", "sites/hq/sensors/t1/raw/v2", payload)
+	require.NoError(t, err)
+
+	reading, err := UnmarshalNew[Reading](source)
+	require.NoError(t, err)
+
+	require.Equal(t, reading.Topic.Site, "sites")
+	require.Equal(t, reading.Topic.Building, "hq")
+	require.Equal(t, reading.Topic.Sensor, "t1")
+	require.Equal(t, reading.Topic.Rest, []string{"raw", "v2"})
+	require.Equal(t, reading.Payload.Temperature, 21.5)
+
+	type Strings struct {
+		Topic struct {
+			Rest string `json:"rest"`
+		} `json:"topic"`
+	}
+
+	strs, err := UnmarshalNew[Strings](source)
+	require.NoError(t, err)
+	require.Equal(t, strs.Topic.Rest, "raw/v2")
+
+	type Topic struct {
+		Topic string `json:"topic"`
+	}
+
+	topic, err := UnmarshalNew[Topic](source)
+	require.NoError(t, err)
+	require.Equal(t, topic.Topic, "sites/hq/sensors/t1/raw/v2")
+
+	type Segments struct {
+		Topic []string `json:"topic"`
+	}
+
+	segments, err := UnmarshalNew[Segments](source)
+	require.NoError(t, err)
+	require.Equal(t, segments.Topic, []string{"sites", "hq", "sensors", "t1", "raw", "v2"})
+}
+
+func TestMQTTSourceMismatch(t *testing.T) {
+	_, err := MQTTSource("sites/+building", "sites/hq/sensors", nil)
+	require.ErrorIs(t, err, ErrTopicMismatch)
+
+	_, err = MQTTSource("sites/+building/+sensor", "sites/hq", nil)
+	require.ErrorIs(t, err, ErrTopicMismatch)
+
+	_, err = MQTTSource("devices/+id", "sites/hq", nil)
+	require.ErrorIs(t, err, ErrTopicMismatch)
+
+	_, err = MQTTSource("sites/#/x", "sites/hq/x", nil)
+	require.Error(t, err)
+
+	_, err = MQTTSource("sites/#", "sites", nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,69 @@
+package unravel
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// NDJSONSource returns a [Source] over newline delimited JSON (also known as JSON Lines)
+// read from r. The source is a list: [Source.Iter] yields one source per line, each line
+// being decoded as a separate JSON document. Empty lines are skipped.
+//
+// Lines are read lazily while iterating, which allows decoding large log exports without
+// reading them into memory at once. A line that is not valid JSON fails the decoding of
+// the corresponding element.
+//
+// Example:
+//
+//	type Event struct {
+//	    Level   string `json:"level"`
+//	    Message string `json:"msg"`
+//	}
+//
+//	events, err := unravel.UnmarshalNew[[]Event](unravel.NDJSONSource(file))
+func NDJSONSource(r io.Reader) Source {
+	return ndjsonSource{r: bufio.NewReader(r)}
+}
+
+type ndjsonSource struct {
+	EmptySource
+	r *bufio.Reader
+}
+
+func (n ndjsonSource) Iter() (iter.Seq[Source], error) {
+	it := func(yield func(Source) bool) {
+		for lineNo := 1; ; lineNo++ {
+			line, err := n.r.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				yield(errorSource{fmt.Errorf("read line %d: %w", lineNo, err)})
+				return
+			}
+
+			if line := bytes.TrimSpace(line); len(line) > 0 {
+				var source Source
+
+				value, parseErr := jsonValueOf(line)
+				if parseErr != nil {
+					source = errorSource{fmt.Errorf("line %d: %w", lineNo, parseErr)}
+				} else {
+					source = value
+				}
+
+				if !yield(source) {
+					return
+				}
+			}
+
+			if err != nil {
+				// reached the end of input
+				return
+			}
+		}
+	}
+
+	return it, nil
+}
@@ -0,0 +1,38 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONSource(t *testing.T) {
+	type Event struct {
+		Level   string   `json:"level"`
+		Message string   `json:"msg"`
+		Code    *int     `json:"code"`
+		Tags    []string `json:"tags"`
+	}
+
+	input := `{"level": "info", "msg": "started", "tags": ["boot"]}
+
+{"level": "warn", "msg": "disk almost full", "code": 17, "unused": {"a": 1}}
+{"level": "info", "msg": "no trailing newline", "code": null}`
+
+	code := 17
+
+	events, err := UnmarshalNew[[]Event](NDJSONSource(strings.NewReader(input)))
+	require.NoError(t, err)
+	require.Equal(t, events, []Event{
+		{Level: "info", Message: "started", Tags: []string{"boot"}},
+		{Level: "warn", Message: "disk almost full", Code: &code},
+		{Level: "info", Message: "no trailing newline"},
+	})
+}
+
+func TestNDJSONSourceInvalidLine(t *testing.T) {
+	input := "{\"level\": \"info\"}\n{\"level\": \n"
+
+	_, err := UnmarshalNew[[]map[string]string](NDJSONSource(strings.NewReader(input)))
+	require.ErrorContains(t, err, "line 2")
+}
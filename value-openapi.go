@@ -0,0 +1,367 @@
+package unravel
+
+import (
+	"iter"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Parameter serialization styles as defined by the OpenAPI specification.
+const (
+	StyleSimple         = "simple"
+	StyleForm           = "form"
+	StyleSpaceDelimited = "spaceDelimited"
+	StylePipeDelimited  = "pipeDelimited"
+	StyleDeepObject     = "deepObject"
+)
+
+// ParameterStyle describes how an OpenAPI parameter is serialized, see the `style`
+// and `explode` properties of a parameter object in the OpenAPI specification.
+type ParameterStyle struct {
+	Style   string
+	Explode bool
+}
+
+// defaultParameterStyles holds the default style of each parameter location.
+var defaultParameterStyles = map[string]ParameterStyle{
+	"path":   {Style: StyleSimple, Explode: false},
+	"query":  {Style: StyleForm, Explode: true},
+	"header": {Style: StyleSimple, Explode: false},
+	"cookie": {Style: StyleForm, Explode: true},
+}
+
+// OpenAPIParameterSource returns a [Source] that binds the parameters of an http request
+// following the parameter serialization rules of the OpenAPI specification. The source is an
+// object with one child per parameter location: "path", "query", "header" and "cookie".
+// Parameters of each location are accessed by name.
+//
+// Each location uses the default style of the OpenAPI specification: path and header
+// parameters use style simple, query and cookie parameters use style form with explode
+// enabled. The style of individual parameters can be overridden using the styles map,
+// indexed by the parameter name. The following styles are supported:
+//   - simple: arrays are comma separated (`3,4,5`). Objects are serialized as
+//     `role,admin,name,Alex` or, if exploded, as `role=admin,name=Alex`.
+//   - form: exploded arrays repeat the parameter (`id=3&id=4`), otherwise they are comma
+//     separated. An exploded object uses one query parameter per property.
+//   - spaceDelimited and pipeDelimited: arrays are separated by space or pipe.
+//   - deepObject: object properties are serialized as `id[role]=admin&id[name]=Alex`.
+//
+// Example:
+//
+//	type ListUsers struct {
+//	    Path struct {
+//	        TenantID int `json:"tenant"`
+//	    } `json:"path"`
+//
+//	    Query struct {
+//	        Roles  []string          `json:"roles"`
+//	        Filter map[string]string `json:"filter"`
+//	    } `json:"query"`
+//
+//	    Header struct {
+//	        RequestID string `json:"X-Request-Id"`
+//	    } `json:"header"`
+//	}
+//
+//	styles := map[string]unravel.ParameterStyle{
+//	    "filter": {Style: unravel.StyleDeepObject, Explode: true},
+//	}
+//
+//	params, err := unravel.UnmarshalNew[ListUsers](unravel.OpenAPIParameterSource(req, styles))
+func OpenAPIParameterSource(req *http.Request, styles map[string]ParameterStyle) Source {
+	return openAPISource{req: req, styles: styles}
+}
+
+type openAPISource struct {
+	EmptySource
+	req    *http.Request
+	styles map[string]ParameterStyle
+}
+
+func (o openAPISource) Get(key string) (Source, error) {
+	if _, ok := defaultParameterStyles[key]; !ok {
+		return nil, ErrNoValue
+	}
+
+	return openAPILocationSource{openAPISource: o, location: key}, nil
+}
+
+// openAPILocationSource holds the parameters of a single location.
+type openAPILocationSource struct {
+	openAPISource
+	location string
+}
+
+func (o openAPILocationSource) styleOf(name string) ParameterStyle {
+	if style, ok := o.styles[name]; ok {
+		return style
+	}
+
+	return defaultParameterStyles[o.location]
+}
+
+func (o openAPILocationSource) Get(name string) (Source, error) {
+	style := o.styleOf(name)
+
+	param := parameterSource{style: style}
+
+	switch o.location {
+	case "path":
+		value := o.req.PathValue(name)
+		if value == "" {
+			return nil, ErrNoValue
+		}
+
+		param.values = []string{value}
+
+	case "query":
+		query := o.req.URL.Query()
+
+		switch {
+		case style.Style == StyleDeepObject:
+			param.query, param.prefix = query, name
+			if !hasDeepObject(query, name) {
+				return nil, ErrNoValue
+			}
+
+		case style.Style == StyleForm && style.Explode && query[name] == nil:
+			// an exploded object stores its properties as separate parameters
+			if _, configured := o.styles[name]; !configured {
+				return nil, ErrNoValue
+			}
+
+			param.query = query
+
+		default:
+			param.values = query[name]
+			if len(param.values) == 0 {
+				return nil, ErrNoValue
+			}
+		}
+
+	case "header":
+		param.values = o.req.Header.Values(name)
+		if len(param.values) == 0 {
+			return nil, ErrNoValue
+		}
+
+	case "cookie":
+		cookie, err := o.req.Cookie(name)
+		if err != nil {
+			return nil, ErrNoValue
+		}
+
+		param.values = []string{cookie.Value}
+	}
+
+	return param, nil
+}
+
+func hasDeepObject(query url.Values, name string) bool {
+	for key := range query {
+		if strings.HasPrefix(key, name+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parameterSource is the value of a single parameter.
+type parameterSource struct {
+	EmptySource
+	style  ParameterStyle
+	values []string
+
+	// set for objects stored in multiple query parameters, e.g.
+	// exploded form or deepObject style.
+	query  url.Values
+	prefix string
+}
+
+func (p parameterSource) scalar() (StringSource, error) {
+	if len(p.values) != 1 {
+		return "", ErrNotSupported
+	}
+
+	return StringSource(p.values[0]), nil
+}
+
+func (p parameterSource) Bool() (bool, error) {
+	value, err := p.scalar()
+	if err != nil {
+		return false, err
+	}
+
+	return value.Bool()
+}
+
+func (p parameterSource) Int() (int64, error) {
+	value, err := p.scalar()
+	if err != nil {
+		return 0, err
+	}
+
+	return value.Int()
+}
+
+func (p parameterSource) Uint() (uint64, error) {
+	value, err := p.scalar()
+	if err != nil {
+		return 0, err
+	}
+
+	return value.Uint()
+}
+
+func (p parameterSource) Float() (float64, error) {
+	value, err := p.scalar()
+	if err != nil {
+		return 0, err
+	}
+
+	return value.Float()
+}
+
+func (p parameterSource) String() (string, error) {
+	value, err := p.scalar()
+	if err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+// elements returns the elements of an array parameter.
+func (p parameterSource) elements() []string {
+	if len(p.values) != 1 {
+		// exploded array, one value per element
+		return p.values
+	}
+
+	switch p.style.Style {
+	case StyleSpaceDelimited:
+		return strings.Split(p.values[0], " ")
+	case StylePipeDelimited:
+		return strings.Split(p.values[0], "|")
+	case StyleForm:
+		if p.style.Explode {
+			return p.values
+		}
+
+		return strings.Split(p.values[0], ",")
+	default:
+		return strings.Split(p.values[0], ",")
+	}
+}
+
+func (p parameterSource) Iter() (iter.Seq[Source], error) {
+	if p.query != nil {
+		return nil, ErrNotSupported
+	}
+
+	elements := p.elements()
+
+	it := func(yield func(Source) bool) {
+		for _, element := range elements {
+			if !yield(StringSource(element)) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// properties returns the properties of an object parameter as a list
+// of alternating keys and values.
+func (p parameterSource) properties() ([]string, error) {
+	switch {
+	case p.query != nil && p.style.Style == StyleDeepObject:
+		var properties []string
+		for key, values := range p.query {
+			property, ok := strings.CutPrefix(key, p.prefix+"[")
+			if !ok || !strings.HasSuffix(property, "]") || len(values) == 0 {
+				continue
+			}
+
+			properties = append(properties, strings.TrimSuffix(property, "]"), values[0])
+		}
+
+		return properties, nil
+
+	case p.query != nil:
+		var properties []string
+		for key, values := range p.query {
+			if len(values) > 0 {
+				properties = append(properties, key, values[0])
+			}
+		}
+
+		return properties, nil
+	}
+
+	value, err := p.scalar()
+	if err != nil {
+		return nil, err
+	}
+
+	if string(value) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(string(value), ",")
+
+	if !p.style.Explode {
+		if len(parts)%2 != 0 {
+			return nil, ErrNotSupported
+		}
+
+		return parts, nil
+	}
+
+	var properties []string
+	for _, part := range parts {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, ErrNotSupported
+		}
+
+		properties = append(properties, key, value)
+	}
+
+	return properties, nil
+}
+
+func (p parameterSource) Get(key string) (Source, error) {
+	properties, err := p.properties()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := 0; idx < len(properties); idx += 2 {
+		if properties[idx] == key {
+			return StringSource(properties[idx+1]), nil
+		}
+	}
+
+	return nil, ErrNoValue
+}
+
+func (p parameterSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	properties, err := p.properties()
+	if err != nil {
+		return nil, err
+	}
+
+	it := func(yield func(Source, Source) bool) {
+		for idx := 0; idx < len(properties); idx += 2 {
+			if !yield(StringSource(properties[idx]), StringSource(properties[idx+1])) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
@@ -0,0 +1,94 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAPIParameterSource(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/tenants/12/users?roles=admin&roles=dev&ids=3,4,5&filter[name]=Alex&filter[team]=core&tags=a|b", nil)
+	req.SetPathValue("tenant", "12")
+	req.Header.Set("X-Request-Id", "abc")
+	req.Header.Set("X-Point", "x=1,y=2")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s3cr3t"})
+
+	type Point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	type Params struct {
+		Path struct {
+			TenantID int `json:"tenant"`
+		} `json:"path"`
+
+		Query struct {
+			Roles  []string          `json:"roles"`
+			IDs    []int             `json:"ids"`
+			Filter map[string]string `json:"filter"`
+			Tags   []string          `json:"tags"`
+			Page   *int              `json:"page"`
+		} `json:"query"`
+
+		Header struct {
+			RequestID string `json:"X-Request-Id"`
+			Point     Point  `json:"X-Point"`
+		} `json:"header"`
+
+		Cookie struct {
+			Session string `json:"session"`
+		} `json:"cookie"`
+	}
+
+	styles := map[string]ParameterStyle{
+		"ids":     {Style: StyleForm, Explode: false},
+		"filter":  {Style: StyleDeepObject, Explode: true},
+		"tags":    {Style: StylePipeDelimited},
+		"X-Point": {Style: StyleSimple, Explode: true},
+	}
+
+	params, err := UnmarshalNew[Params](OpenAPIParameterSource(req, styles))
+	require.NoError(t, err)
+
+	require.Equal(t, params.Path.TenantID, 12)
+	require.Equal(t, params.Query.Roles, []string{"admin", "dev"})
+	require.Equal(t, params.Query.IDs, []int{3, 4, 5})
+	require.Equal(t, params.Query.Filter, map[string]string{"name": "Alex", "team": "core"})
+	require.Equal(t, params.Query.Tags, []string{"a", "b"})
+	require.Nil(t, params.Query.Page)
+	require.Equal(t, params.Header.RequestID, "abc")
+	require.Equal(t, params.Header.Point, Point{X: 1, Y: 2})
+	require.Equal(t, params.Cookie.Session, "s3cr3t")
+}
+
+func TestOpenAPIParameterSourceExplodedObject(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?x=1&y=2", nil)
+	req.Header.Set("X-Point", "x,3,y,4")
+
+	type Point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	type Params struct {
+		Query struct {
+			Point Point `json:"point"`
+		} `json:"query"`
+
+		Header struct {
+			Point Point `json:"X-Point"`
+		} `json:"header"`
+	}
+
+	styles := map[string]ParameterStyle{
+		"point": {Style: StyleForm, Explode: true},
+	}
+
+	params, err := UnmarshalNew[Params](OpenAPIParameterSource(req, styles))
+	require.NoError(t, err)
+
+	require.Equal(t, params.Query.Point, Point{X: 1, Y: 2})
+	require.Equal(t, params.Header.Point, Point{X: 3, Y: 4})
+}
@@ -0,0 +1,493 @@
+package unravel
+
+import (
+	"encoding"
+	"fmt"
+	"iter"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// SourceOf returns a [Source] that reads from an existing Go value. Structs are resolved
+// using the same rules as the [Decoder] uses: fields are named by their `json` struct tag,
+// fields of embedded structs are promoted and conflicting names are ignored.
+//
+// This allows any Go value to be used as input to [Unmarshal], e.g. to map one struct
+// onto another struct with a similar shape, or to transcode a value into a [Sink].
+//
+// Values are mapped as follows:
+//   - Pointers and interfaces are dereferenced. A nil pointer, interface, map or
+//     slice returns [ErrNoValue] when requested using [Source.Get].
+//   - Numbers are accessible using the numeric methods as long as the value fits
+//     into the requested type.
+//   - Values implementing [encoding.TextMarshaler], byte slices and integers are
+//     accessible using [Source.String].
+//   - Structs and maps are accessible using [Source.Get] and [Source.KeyValues].
+//     Map keys must be strings, integers or implement [encoding.TextUnmarshaler]
+//     to be accessible using [Source.Get].
+//   - Slices and arrays are accessible using [Source.Iter].
+//
+// Example:
+//
+//	type UserDTO struct {
+//	    Name  string `json:"name"`
+//	    Email string `json:"email"`
+//	}
+//
+//	dto, err := unravel.UnmarshalNew[UserDTO](unravel.SourceOf(user))
+func SourceOf(v any) Source {
+	return reflectSource{Value: reflect.ValueOf(v)}
+}
+
+// reflectFieldsCache caches the fields of struct types, indexed by [reflect.Type].
+var reflectFieldsCache sync.Map
+
+func reflectFieldsOf(ty reflect.Type) []field {
+	if cached, ok := reflectFieldsCache.Load(ty); ok {
+		return cached.([]field)
+	}
+
+	fields := fieldsToSerialize(ty, "json")
+	reflectFieldsCache.Store(ty, fields)
+	return fields
+}
+
+type reflectSource struct {
+	Value reflect.Value
+}
+
+var _ Source = reflectSource{}
+var _ NullableSource = reflectSource{}
+var _ KindSource = reflectSource{}
+var _ BytesSource = reflectSource{}
+var _ IndexSource = reflectSource{}
+var _ LenSource = reflectSource{}
+var _ StringKeyValuesSource = reflectSource{}
+
+// indirect dereferences pointers and interfaces. Returns false if a nil value was found.
+func (r reflectSource) indirect() (reflect.Value, bool) {
+	value := r.Value
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		value = value.Elem()
+	}
+
+	return value, value.IsValid()
+}
+
+func (r reflectSource) Kind() ValueKind {
+	value, ok := r.indirect()
+	if !ok {
+		return KindNull
+	}
+
+	if _, ok := textMarshalerOf(value); ok {
+		return KindString
+	}
+
+	switch {
+	case value.Kind() == reflect.Bool:
+		return KindBool
+	case value.CanInt(), value.CanUint(), value.CanFloat():
+		return KindNumber
+	case value.Kind() == reflect.String:
+		return KindString
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return KindString
+	case value.Kind() == reflect.Struct, value.Kind() == reflect.Map:
+		return KindObject
+	case value.Kind() == reflect.Slice, value.Kind() == reflect.Array:
+		return KindArray
+	default:
+		return KindUnknown
+	}
+}
+
+func (r reflectSource) IsNull() bool {
+	_, ok := r.indirect()
+	return !ok
+}
+
+func (r reflectSource) Bool() (bool, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return false, ErrNoValue
+	}
+
+	if value.Kind() != reflect.Bool {
+		return false, ErrNotSupported
+	}
+
+	return value.Bool(), nil
+}
+
+func (r reflectSource) Int() (int64, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return 0, ErrNoValue
+	}
+
+	switch {
+	case value.CanInt():
+		return value.Int(), nil
+
+	case value.CanUint():
+		uintValue := value.Uint()
+		if uintValue > math.MaxInt64 {
+			return 0, fmt.Errorf("invalid int64 value %d: %w", uintValue, strconv.ErrRange)
+		}
+
+		return int64(uintValue), nil
+
+	default:
+		return 0, ErrNotSupported
+	}
+}
+
+func (r reflectSource) Uint() (uint64, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return 0, ErrNoValue
+	}
+
+	switch {
+	case value.CanUint():
+		return value.Uint(), nil
+
+	case value.CanInt():
+		intValue := value.Int()
+		if intValue < 0 {
+			return 0, fmt.Errorf("invalid uint64 value %d: %w", intValue, strconv.ErrRange)
+		}
+
+		return uint64(intValue), nil
+
+	default:
+		return 0, ErrNotSupported
+	}
+}
+
+func (r reflectSource) Float() (float64, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return 0, ErrNoValue
+	}
+
+	switch {
+	case value.CanFloat():
+		return value.Float(), nil
+	case value.CanInt():
+		return float64(value.Int()), nil
+	case value.CanUint():
+		return float64(value.Uint()), nil
+	default:
+		return 0, ErrNotSupported
+	}
+}
+
+func (r reflectSource) String() (string, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return "", ErrNoValue
+	}
+
+	if m, ok := textMarshalerOf(value); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("marshal text: %w", err)
+		}
+
+		return string(text), nil
+	}
+
+	switch {
+	case value.Kind() == reflect.String:
+		return value.String(), nil
+
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return string(value.Bytes()), nil
+
+	case value.CanInt():
+		// allows integer map keys to be decoded into string keys
+		return strconv.FormatInt(value.Int(), 10), nil
+
+	case value.CanUint():
+		return strconv.FormatUint(value.Uint(), 10), nil
+
+	default:
+		return "", ErrNotSupported
+	}
+}
+
+func (r reflectSource) Bytes() ([]byte, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, ErrNotSupported
+	}
+
+	return value.Bytes(), nil
+}
+
+// textMarshalerOf returns the value as [encoding.TextMarshaler], if it implements it.
+func textMarshalerOf(value reflect.Value) (encoding.TextMarshaler, bool) {
+	if value.Type().Implements(tyTextMarshaler) {
+		return value.Interface().(encoding.TextMarshaler), true
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(tyTextMarshaler) {
+		return value.Addr().Interface().(encoding.TextMarshaler), true
+	}
+
+	return nil, false
+}
+
+func (r reflectSource) Get(key string) (Source, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, field := range reflectFieldsOf(value.Type()) {
+			if field.Name != key {
+				continue
+			}
+
+			fieldValue, ok := fieldByIndex(value, field.Index)
+			if !ok || isNil(fieldValue) {
+				return nil, ErrNoValue
+			}
+
+			return reflectSource{Value: fieldValue}, nil
+		}
+
+		return nil, ErrNoValue
+
+	case reflect.Map:
+		keyValue, err := mapKeyOf(value.Type().Key(), key)
+		if err != nil {
+			return nil, err
+		}
+
+		entryValue := value.MapIndex(keyValue)
+		if !entryValue.IsValid() || isNil(entryValue) {
+			return nil, ErrNoValue
+		}
+
+		return reflectSource{Value: entryValue}, nil
+
+	default:
+		return nil, ErrNotSupported
+	}
+}
+
+// mapKeyOf converts a string into a map key of the given type.
+func mapKeyOf(ty reflect.Type, key string) (reflect.Value, error) {
+	keyValue := reflect.New(ty).Elem()
+
+	if reflect.PointerTo(ty).Implements(tyTextUnmarshaler) {
+		m := keyValue.Addr().Interface().(encoding.TextUnmarshaler)
+		if err := m.UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, fmt.Errorf("parse key %q: %w", key, err)
+		}
+
+		return keyValue, nil
+	}
+
+	switch {
+	case ty.Kind() == reflect.String:
+		keyValue.SetString(key)
+
+	case keyValue.CanInt():
+		intValue, err := strconv.ParseInt(key, 10, ty.Bits())
+		if err != nil {
+			return reflect.Value{}, ErrNoValue
+		}
+
+		keyValue.SetInt(intValue)
+
+	case keyValue.CanUint():
+		uintValue, err := strconv.ParseUint(key, 10, ty.Bits())
+		if err != nil {
+			return reflect.Value{}, ErrNoValue
+		}
+
+		keyValue.SetUint(uintValue)
+
+	default:
+		return reflect.Value{}, ErrNotSupported
+	}
+
+	return keyValue, nil
+}
+
+func (r reflectSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	value, ok := r.indirect()
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		fields := reflectFieldsOf(value.Type())
+
+		it := func(yield func(Source, Source) bool) {
+			for _, field := range fields {
+				fieldValue, ok := fieldByIndex(value, field.Index)
+				if !ok || isNil(fieldValue) {
+					continue
+				}
+
+				if !yield(StringSource(field.Name), reflectSource{Value: fieldValue}) {
+					break
+				}
+			}
+		}
+
+		return it, nil
+
+	case reflect.Map:
+		it := func(yield func(Source, Source) bool) {
+			for keySource, valueSource := range mapEntriesOf(value) {
+				if !yield(keySource, valueSource) {
+					break
+				}
+			}
+		}
+
+		return it, nil
+
+	default:
+		return nil, ErrNotSupported
+	}
+}
+
+func (r reflectSource) StringKeyValues() (iter.Seq2[string, Source], error) {
+	value, ok := r.indirect()
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	switch {
+	case value.Kind() == reflect.Struct:
+		fields := reflectFieldsOf(value.Type())
+
+		it := func(yield func(string, Source) bool) {
+			for _, field := range fields {
+				fieldValue, ok := fieldByIndex(value, field.Index)
+				if !ok || isNil(fieldValue) {
+					continue
+				}
+
+				if !yield(field.Name, reflectSource{Value: fieldValue}) {
+					break
+				}
+			}
+		}
+
+		return it, nil
+
+	case value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String:
+		it := func(yield func(string, Source) bool) {
+			for keySource, valueSource := range mapEntriesOf(value) {
+				if !yield(keySource.Value.String(), valueSource) {
+					break
+				}
+			}
+		}
+
+		return it, nil
+
+	default:
+		// keys of other types are not converted to strings
+		return nil, ErrNotSupported
+	}
+}
+
+// mapEntriesOf returns the entries of a map as sources. Keys, values and their sources
+// are copied into storage allocated once per map, as [reflect.MapIter.Key] and
+// [reflect.MapIter.Value] allocate a copy of every entry, and so does converting
+// each reflectSource into a [Source].
+func mapEntriesOf(value reflect.Value) iter.Seq2[*reflectSource, *reflectSource] {
+	return func(yield func(*reflectSource, *reflectSource) bool) {
+		count := value.Len()
+
+		keys := reflect.MakeSlice(reflect.SliceOf(value.Type().Key()), count, count)
+		values := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), count, count)
+		sources := make([]reflectSource, 2*count)
+
+		// entries added while iterating may be skipped, as with a plain range over the map
+		iter := value.MapRange()
+		for idx := 0; idx < count && iter.Next(); idx++ {
+			keySource, valueSource := &sources[2*idx], &sources[2*idx+1]
+
+			keySource.Value = keys.Index(idx)
+			keySource.Value.SetIterKey(iter)
+
+			valueSource.Value = values.Index(idx)
+			valueSource.Value.SetIterValue(iter)
+
+			if !yield(keySource, valueSource) {
+				return
+			}
+		}
+	}
+}
+
+func (r reflectSource) Iter() (iter.Seq[Source], error) {
+	value, ok := r.indirect()
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, ErrNotSupported
+	}
+
+	it := func(yield func(Source) bool) {
+		for idx := range value.Len() {
+			if !yield(reflectSource{Value: value.Index(idx)}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (r reflectSource) Len() (int, bool) {
+	value, ok := r.indirect()
+	if !ok || value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return 0, false
+	}
+
+	return value.Len(), true
+}
+
+func (r reflectSource) Index(idx int) (Source, error) {
+	value, ok := r.indirect()
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, ErrNotSupported
+	}
+
+	if idx < 0 || idx >= value.Len() {
+		return nil, ErrNoValue
+	}
+
+	return reflectSource{Value: value.Index(idx)}, nil
+}
@@ -0,0 +1,89 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSourceOf(t *testing.T) {
+	type Base struct {
+		ID uint32 `json:"id"`
+	}
+
+	type User struct {
+		Base
+		Name      string            `json:"name"`
+		Email     *string           `json:"email"`
+		Roles     []string          `json:"roles"`
+		Limits    map[string]int    `json:"limits"`
+		Labels    map[int]string    `json:"labels"`
+		CreatedAt time.Time         `json:"createdAt"`
+		IP        net.IP            `json:"ip"`
+		Extra     map[string]any    `json:"extra"`
+		Secret    string            `json:"-"`
+		Ignored   map[string]string `json:"ignored"`
+	}
+
+	type UserDTO struct {
+		ID        int64             `json:"id"`
+		Name      string            `json:"name"`
+		Email     string            `json:"email"`
+		Roles     [2]string         `json:"roles"`
+		Limits    map[string]uint8  `json:"limits"`
+		Labels    map[string]string `json:"labels"`
+		CreatedAt time.Time         `json:"createdAt"`
+		IP        string            `json:"ip"`
+		Extra     struct {
+			Score float64 `json:"score"`
+		} `json:"extra"`
+		Secret string `json:"-"`
+	}
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	user := User{
+		Base:      Base{ID: 7},
+		Name:      "Alex",
+		Roles:     []string{"admin", "dev"},
+		Limits:    map[string]int{"requests": 100},
+		Labels:    map[int]string{1: "one"},
+		CreatedAt: createdAt,
+		IP:        net.IPv4(10, 0, 0, 1),
+		Extra:     map[string]any{"score": 3},
+		Secret:    "secret",
+	}
+
+	dto, err := UnmarshalNew[UserDTO](SourceOf(&user))
+	require.NoError(t, err)
+
+	require.Equal(t, dto.ID, int64(7))
+	require.Equal(t, dto.Name, "Alex")
+	require.Equal(t, dto.Email, "")
+	require.Equal(t, dto.Roles, [2]string{"admin", "dev"})
+	require.Equal(t, dto.Limits, map[string]uint8{"requests": 100})
+	require.Equal(t, dto.Labels, map[string]string{"1": "one"})
+	require.Equal(t, dto.CreatedAt, createdAt)
+	require.Equal(t, dto.IP, "10.0.0.1")
+	require.Equal(t, dto.Extra.Score, 3.0)
+	require.Equal(t, dto.Secret, "")
+}
+
+func TestSourceOfErrors(t *testing.T) {
+	_, err := UnmarshalNew[uint8](SourceOf(300))
+	require.Error(t, err)
+
+	_, err = UnmarshalNew[uint](SourceOf(-1))
+	require.Error(t, err)
+
+	_, err = UnmarshalNew[int](SourceOf("text"))
+	require.ErrorIs(t, err, ErrNotSupported)
+
+	type Struct struct {
+		Value int `json:"value"`
+	}
+
+	_, err = UnmarshalNewWith[Struct](NewDecoder().RequireValues(), SourceOf(map[string]*int{"value": nil}))
+	require.ErrorIs(t, err, ErrNoValue)
+}
@@ -0,0 +1,173 @@
+package unravel
+
+import (
+	"iter"
+	"regexp"
+)
+
+// RegexpSource returns a [Source] that exposes the matches of a regular expression
+// in the given input. Named capture groups become keys accessible using [Source.Get].
+//
+// Used as an object, the source represents the first match of the expression within the
+// input. Used as a slice, [Source.Iter] yields one source per non-overlapping match,
+// as found by [regexp.Regexp.FindAllStringSubmatch]. Each match can also be read as a
+// string, returning the text of the full match.
+//
+// Capture groups are also accessible by their position using [IndexSource.Index], with
+// index 0 being the full match. Capture groups that did not participate in a match return
+// [ErrNoValue]. Values of
+// capture groups are provided as [StringSource], so they can be decoded into numbers
+// and other primitive types.
+//
+// Example:
+//
+//	re := regexp.MustCompile(`(?P<level>[A-Z]+) (?P<code>\d+): (?P<message>.*)`)
+//
+//	type LogLine struct {
+//	    Level   string `json:"level"`
+//	    Code    int    `json:"code"`
+//	    Message string `json:"message"`
+//	}
+//
+//	line, err := unravel.UnmarshalNew[LogLine](unravel.RegexpSource(re, "WARN 42: disk almost full"))
+func RegexpSource(re *regexp.Regexp, input string) Source {
+	return regexpSource{re: re, input: input}
+}
+
+var _ IndexSource = regexpSource{}
+var _ IndexSource = regexpMatch{}
+
+type regexpSource struct {
+	EmptySource
+	re    *regexp.Regexp
+	input string
+}
+
+func (r regexpSource) firstMatch() (regexpMatch, error) {
+	match := r.re.FindStringSubmatchIndex(r.input)
+	if match == nil {
+		return regexpMatch{}, ErrNoValue
+	}
+
+	return regexpMatch{re: r.re, input: r.input, match: match}, nil
+}
+
+func (r regexpSource) String() (string, error) {
+	match, err := r.firstMatch()
+	if err != nil {
+		return "", err
+	}
+
+	return match.String()
+}
+
+func (r regexpSource) Get(key string) (Source, error) {
+	match, err := r.firstMatch()
+	if err != nil {
+		return nil, err
+	}
+
+	return match.Get(key)
+}
+
+func (r regexpSource) Index(idx int) (Source, error) {
+	match, err := r.firstMatch()
+	if err != nil {
+		return nil, err
+	}
+
+	return match.Index(idx)
+}
+
+func (r regexpSource) KeyValues() (iter.Seq2[Source, Source], error) {
+	match, err := r.firstMatch()
+	if err != nil {
+		return nil, err
+	}
+
+	return match.KeyValues()
+}
+
+func (r regexpSource) Iter() (iter.Seq[Source], error) {
+	it := func(yield func(Source) bool) {
+		for _, match := range r.re.FindAllStringSubmatchIndex(r.input, -1) {
+			if !yield(regexpMatch{re: r.re, input: r.input, match: match}) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// regexpMatch is a single match of a regular expression. match holds the
+// submatch indices as returned by [regexp.Regexp.FindStringSubmatchIndex].
+type regexpMatch struct {
+	EmptySource
+	re    *regexp.Regexp
+	input string
+	match []int
+}
+
+func (r regexpMatch) group(idx int) (string, bool) {
+	start, end := r.match[2*idx], r.match[2*idx+1]
+	if start < 0 {
+		// group did not participate in the match
+		return "", false
+	}
+
+	return r.input[start:end], true
+}
+
+func (r regexpMatch) String() (string, error) {
+	text, _ := r.group(0)
+	return text, nil
+}
+
+func (r regexpMatch) Get(key string) (Source, error) {
+	idx := r.re.SubexpIndex(key)
+	if idx < 0 {
+		return nil, ErrNoValue
+	}
+
+	text, ok := r.group(idx)
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	return StringSource(text), nil
+}
+
+func (r regexpMatch) Index(idx int) (Source, error) {
+	if idx < 0 || idx > r.re.NumSubexp() {
+		return nil, ErrNoValue
+	}
+
+	text, ok := r.group(idx)
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	return StringSource(text), nil
+}
+
+func (r regexpMatch) KeyValues() (iter.Seq2[Source, Source], error) {
+	it := func(yield func(Source, Source) bool) {
+		for idx, name := range r.re.SubexpNames() {
+			if name == "" {
+				continue
+			}
+
+			text, ok := r.group(idx)
+			if !ok {
+				continue
+			}
+
+			if !yield(StringSource(name), StringSource(text)) {
+				break
+			}
+		}
+	}
+
+	return it, nil
+}
@@ -0,0 +1,78 @@
+package unravel
+
+import (
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestRegexpSource(t *testing.T) {
+	re := regexp.MustCompile(`(?P<level>[A-Z]+) (?P<code>\d+)(?: \[(?P<tag>\w+)\])?: (?P<message>[^\n]*)`)
+
+	type LogLine struct {
+		Level   string  `json:"level"`
+		Code    int     `json:"code"`
+		Tag     *string `json:"tag"`
+		Message string  `json:"message"`
+	}
+
+	input := "WARN 42 [disk]: almost full\nINFO 7: started"
+
+	first, err := UnmarshalNew[LogLine](RegexpSource(re, input))
+	require.NoError(t, err)
+
+	tag := "disk"
+	require.Equal(t, first, LogLine{Level: "WARN", Code: 42, Tag: &tag, Message: "almost full"})
+
+	lines, err := UnmarshalNew[[]LogLine](RegexpSource(re, input))
+	require.NoError(t, err)
+	require.Equal(t, lines, []LogLine{
+		{Level: "WARN", Code: 42, Tag: &tag, Message: "almost full"},
+		{Level: "INFO", Code: 7, Message: "started"},
+	})
+
+	groups, err := UnmarshalNew[map[string]string](RegexpSource(re, input))
+	require.NoError(t, err)
+	require.Equal(t, groups, map[string]string{"level": "WARN", "code": "42", "tag": "disk", "message": "almost full"})
+
+	matches, err := UnmarshalNew[[]string](RegexpSource(regexp.MustCompile(`\d+`), input))
+	require.NoError(t, err)
+	require.Equal(t, matches, []string{"42", "7"})
+}
+
+func TestRegexpSourceNoMatch(t *testing.T) {
+	re := regexp.MustCompile(`(?P<id>\d+)`)
+
+	dec := NewDecoder().RequireValues()
+
+	type Struct struct {
+		ID int `json:"id"`
+	}
+
+	_, err := UnmarshalNewWith[Struct](dec, RegexpSource(re, "no digits here"))
+	require.ErrorIs(t, err, ErrNoValue)
+}
+
+func TestRegexpSourceIndex(t *testing.T) {
+	type Version struct {
+		Full  string `idx:"0"`
+		Major int    `idx:"1"`
+		Minor int    `idx:"2"`
+		Patch *int   `idx:"4"`
+	}
+
+	re := regexp.MustCompile(`v(\d+)\.(\d+)(\.(\d+))?`)
+
+	version, err := UnmarshalNew[Version](RegexpSource(re, "release v1.23"))
+	require.NoError(t, err)
+	require.Equal(t, version, Version{Full: "v1.23", Major: 1, Minor: 23})
+
+	versions, err := UnmarshalNew[[]Version](RegexpSource(re, "v1.2.3 v4.5"))
+	require.NoError(t, err)
+
+	patch := 3
+	require.Equal(t, versions, []Version{
+		{Full: "v1.2.3", Major: 1, Minor: 2, Patch: &patch},
+		{Full: "v4.5", Major: 4, Minor: 5},
+	})
+}
@@ -0,0 +1,92 @@
+package unravel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WarningKind is the kind of event reported by a [Warning].
+type WarningKind int
+
+const (
+	// WarningNoValue is reported for fields without a value in the [Source], that
+	// are skipped as they are not required.
+	WarningNoValue WarningKind = iota
+
+	// WarningFieldConflict is reported once per struct type for fields that are
+	// dropped due to a naming conflict, see [Decoder.StrictFields].
+	WarningFieldConflict
+
+	// WarningTruncated is reported if the [Source] holds more elements than fit
+	// into the array they are decoded into.
+	WarningTruncated
+
+	// WarningSkipped is reported for fields skipped by [Decoder.BestEffort].
+	WarningSkipped
+
+	// WarningUnsupported is reported once per struct type for fields that are
+	// skipped due to their type, see [Decoder.SkipUnsupportedFields].
+	WarningUnsupported
+)
+
+// Warning describes a non-fatal event during decoding, e.g. input that was silently
+// ignored. See [Decoder.WithWarnings].
+type Warning struct {
+	Kind WarningKind
+
+	// The struct or array type the warning is about.
+	Type reflect.Type
+
+	// The name of the field the warning is about, if any.
+	Field string
+
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Field != "" {
+		return fmt.Sprintf("field %q of %q: %s", w.Field, w.Type, w.Message)
+	}
+
+	return fmt.Sprintf("%q: %s", w.Type, w.Message)
+}
+
+// WithWarnings returns a [Decoder] that calls the handler for each non-fatal event, like
+// fields without a value or array elements that were dropped. This gives visibility into
+// input that is otherwise silently ignored. The handler is called synchronously while
+// decoding, and must be safe for concurrent use if the decoder is.
+func (d *Decoder) WithWarnings(handler func(Warning)) *Decoder {
+	derived := d.clone()
+	derived.warn = handler
+	return derived
+}
+
+// BestEffort returns a [Decoder] that skips fields whose value can not be represented by
+// the type of the field, e.g. a string value for an int field, instead of failing. The field
+// keeps its previous value, though entries might have been added to an existing map, and a
+// [WarningSkipped] is reported, see [Decoder.WithWarnings]. When scraping heterogeneous or
+// dirty data, partial results are often more useful than an error.
+func (d *Decoder) BestEffort() *Decoder {
+	if d.bestEffort {
+		return d
+	}
+
+	derived := d.clone()
+	derived.bestEffort = true
+	return derived
+}
+
+// SkipUnsupportedFields returns a [Decoder] that ignores struct fields of types that can
+// not be decoded, e.g. channels, functions or interfaces with methods, instead of failing
+// with a [NotSupportedError]. This allows decoding structs that also hold runtime-only
+// members. Skipped fields are reported as [WarningUnsupported] when the setter of the
+// struct is built, see [Decoder.WithWarnings].
+func (d *Decoder) SkipUnsupportedFields() *Decoder {
+	if d.skipUnsupportedFields {
+		return d
+	}
+
+	derived := d.cloneWithSetters()
+	derived.skipUnsupportedFields = true
+	return derived
+}
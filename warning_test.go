@@ -0,0 +1,134 @@
+package unravel
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoderWithWarnings(t *testing.T) {
+	type First struct{ ID string }
+	type Second struct{ ID string }
+
+	type Config struct {
+		First
+		Second
+		Name     string  `json:"name"`
+		Position [2]int  `json:"position"`
+		Optional *string `json:"optional"`
+	}
+
+	var warnings []Warning
+
+	dec := NewDecoder().WithWarnings(func(warning Warning) {
+		warnings = append(warnings, warning)
+	})
+
+	source := SourceOf(map[string]any{
+		"ID":       "id",
+		"name":     "app",
+		"position": []int{1, 2, 3},
+	})
+
+	config, err := UnmarshalNewWith[Config](dec, source)
+	require.NoError(t, err)
+	require.Equal(t, config, Config{Name: "app", Position: [2]int{1, 2}})
+
+	tyConfig := reflect.TypeFor[Config]()
+
+	require.Equal(t, warnings, []Warning{
+		{Kind: WarningFieldConflict, Type: tyConfig, Field: "ID", Message: `"ID" is used by First.ID, Second.ID`},
+		{Kind: WarningTruncated, Type: reflect.TypeFor[[2]int](), Message: "dropped elements after the first 2"},
+		{Kind: WarningNoValue, Type: tyConfig, Field: "optional", Message: "no value"},
+	})
+
+	require.Equal(t, warnings[2].String(), `field "optional" of "unravel.Config": no value`)
+
+	// conflicts are reported when the setter is built, other warnings on each call
+	warnings = nil
+
+	_, err = UnmarshalNewWith[Config](dec, source)
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+}
+
+func TestDecoderBestEffort(t *testing.T) {
+	type Product struct {
+		Name   string   `json:"name"`
+		Price  float64  `json:"price"`
+		Sizes  []int    `json:"sizes"`
+		Rating *float64 `json:"rating"`
+	}
+
+	var warnings []Warning
+
+	dec := NewDecoder().BestEffort().WithWarnings(func(warning Warning) {
+		if warning.Kind == WarningSkipped {
+			warnings = append(warnings, warning)
+		}
+	})
+
+	source := JSONStreamSource(strings.NewReader(`[
+		{"name": "shirt", "price": "n/a", "sizes": [1, "xl"], "rating": 4.5},
+		{"name": "socks", "price": 2.5, "sizes": [3], "rating": {}}
+	]`))
+
+	products, err := UnmarshalNewWith[[]Product](dec, source)
+	require.NoError(t, err)
+
+	rating := 4.5
+	require.Equal(t, products, []Product{
+		{Name: "shirt", Rating: &rating},
+		{Name: "socks", Price: 2.5, Sizes: []int{3}},
+	})
+
+	var fields []string
+	for _, warning := range warnings {
+		fields = append(fields, warning.Field)
+	}
+
+	require.Equal(t, fields, []string{"price", "sizes", "rating"})
+
+	// by default, decoding fails
+	_, err = UnmarshalNew[Product](SourceOf(map[string]any{"price": "n/a"}))
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestDecoderSkipUnsupportedFields(t *testing.T) {
+	type Worker struct {
+		Name     string       `json:"name"`
+		Done     chan bool    `json:"done"`
+		OnClose  func()       `json:"on_close"`
+		Closed   chan bool    `json:"closed"`
+		Stringer fmt.Stringer `json:"stringer"`
+		Retries  int          `json:"retries"`
+	}
+
+	var warnings []Warning
+
+	dec := NewDecoder().SkipUnsupportedFields().WithWarnings(func(warning Warning) {
+		warnings = append(warnings, warning)
+	})
+
+	source := SourceOf(map[string]any{"name": "worker", "done": true, "retries": 3})
+
+	worker, err := UnmarshalNewWith[Worker](dec, source)
+	require.NoError(t, err)
+	require.Equal(t, worker.Name, "worker")
+	require.Equal(t, worker.Retries, 3)
+	require.Nil(t, worker.Done)
+
+	var fields []string
+	for _, warning := range warnings {
+		require.Equal(t, warning.Kind, WarningUnsupported)
+		fields = append(fields, warning.Field)
+	}
+
+	require.Equal(t, fields, []string{"done", "on_close", "closed", "stringer"})
+
+	// by default, the struct can not be decoded at all
+	_, err = UnmarshalNew[Worker](source)
+	require.ErrorAs(t, err, &NotSupportedError{})
+}